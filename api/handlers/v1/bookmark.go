@@ -0,0 +1,147 @@
+package v1
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"univer/api/models"
+	"univer/internal/entity"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Security  		BearerAuth
+// @Summary   		Bookmark Post
+// @Description 	Api for saving a post to the current user's bookmarks
+// @Tags 			bookmark
+// @Produce 		json
+// @Param 			id path string true "Post ID"
+// @Success 		200 {object} models.Response
+// @Failure 		401 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/post/{id}/bookmark [POST]
+func (h *HandlerV1) BookmarkPost(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	userId, statusCode := GetIdFromToken(c.Request, &h.Config)
+	if statusCode != 0 {
+		c.JSON(http.StatusUnauthorized, models.Error{
+			Message: models.TokenInvalidMessage,
+		})
+		return
+	}
+
+	err := h.Service.Bookmark().AddBookmark(ctx, &entity.Bookmark{
+		UserId: userId,
+		PostId: c.Param("id"),
+	})
+	if err != nil {
+		c.JSON(collectionErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Response: "Post bookmarked",
+	})
+}
+
+// @Security  		BearerAuth
+// @Summary   		Delete Bookmark
+// @Description 	Api for removing a post from the current user's bookmarks
+// @Tags 			bookmark
+// @Produce 		json
+// @Param 			id path string true "Post ID"
+// @Success 		200 {object} models.Response
+// @Failure 		401 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/post/{id}/bookmark [DELETE]
+func (h *HandlerV1) DeleteBookmark(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	userId, statusCode := GetIdFromToken(c.Request, &h.Config)
+	if statusCode != 0 {
+		c.JSON(http.StatusUnauthorized, models.Error{
+			Message: models.TokenInvalidMessage,
+		})
+		return
+	}
+
+	err := h.Service.Bookmark().DeleteBookmark(ctx, userId, c.Param("id"))
+	if err != nil {
+		c.JSON(collectionErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Response: "Bookmark deleted",
+	})
+}
+
+// @Security  		BearerAuth
+// @Summary   		List Bookmarks
+// @Description 	Api for getting the posts the current user bookmarked, most recently saved first. Deleted posts are left out. Page with page and limit, or leave page out and follow next_cursor and prev_cursor.
+// @Tags 			bookmark
+// @Produce 		json
+// @Param 			page query int false "Page"
+// @Param 			limit query int true "Limit"
+// @Param 			cursor query string false "Cursor from a previous response"
+// @Param 			count query bool false "Set to true for TotalCount when paging by cursor"
+// @Success 		200 {object} models.ListPost
+// @Failure 		400 {object} models.Error
+// @Failure 		401 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/user/bookmarks [GET]
+func (h *HandlerV1) ListBookmarks(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	userId, statusCode := GetIdFromToken(c.Request, &h.Config)
+	if statusCode != 0 {
+		c.JSON(http.StatusUnauthorized, models.Error{
+			Message: models.TokenInvalidMessage,
+		})
+		return
+	}
+
+	req, ok := listRequest(c, map[string]string{
+		"user_id": userId,
+	})
+	if !ok {
+		return
+	}
+	listPost, err := h.Service.Bookmark().ListBookmark(ctx, req)
+	if err != nil {
+		c.JSON(listErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	posts := h.postsResponse(listPost.Post)
+	if err := h.lockPaidPosts(ctx, c, posts...); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ListPost{
+		Post:       posts,
+		TotalCount: listPost.TotalCount,
+		NextCursor: encodeCursor(listPost.NextCursor),
+		PrevCursor: encodeCursor(listPost.PrevCursor),
+	})
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"log"
 	"net/http"
-	"strconv"
 	"univer/api/models"
 	"univer/internal/entity"
 
@@ -183,12 +182,14 @@ func (h *HandlerV1) GetCategory(c *gin.Context) {
 
 // @Security 		BearerAuth
 // @Summary 		List Category
-// @Description 	This API for getting categories
+// @Description 	This API for getting categories. Page with page and limit, or leave page out and follow next_cursor and prev_cursor.
 // @Tags 			category
 // @Produce 		json
 // @Accept 			json
-// @Param 			page query uint64 true "Page"
+// @Param 			page query uint64 false "Page"
 // @Param 			limit query uint64 true "Limit"
+// @Param 			cursor query string false "Cursor from a previous response"
+// @Param 			count query bool false "Set to true for total_count when paging by cursor"
 // @Success			200 {object} models.ListCategory
 // @Failure 		404 {object} models.Error
 // @Failure 		401 {object} models.Error
@@ -200,32 +201,13 @@ func (h *HandlerV1) ListCategory(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
 	defer cancel()
 
-	page := c.Query("page")
-	limit := c.Query("limit")
-	pageInt, err := strconv.Atoi(page)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{
-			Message: err.Error(),
-		})
-		log.Println(err.Error())
+	req, ok := listRequest(c, nil)
+	if !ok {
 		return
 	}
-	limitInt, err := strconv.Atoi(limit)
+	listCategories, err := h.Service.Category().ListCategory(ctx, req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{
-			Message: err.Error(),
-		})
-		log.Println(err.Error())
-		return
-	}
-	offset := (pageInt - 1) * limitInt
-
-	listCategories, err := h.Service.Category().ListCategory(ctx, &entity.ListReq{
-		Offset: offset,
-		Limit:  limitInt,
-	})
-	if err != nil {
-		c.JSON(http.StatusNotFound, models.Error{
+		c.JSON(listErrorStatus(err), models.Error{
 			Message: err.Error(),
 		})
 		log.Println(err.Error())
@@ -243,5 +225,7 @@ func (h *HandlerV1) ListCategory(c *gin.Context) {
 	c.JSON(http.StatusOK, models.ListCategory{
 		Categories: categories,
 		Total:      uint64(listCategories.Totalcount),
+		NextCursor: encodeCursor(listCategories.NextCursor),
+		PrevCursor: encodeCursor(listCategories.PrevCursor),
 	})
 }
@@ -0,0 +1,492 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+	"univer/api/models"
+	"univer/internal/entity"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Security  		BearerAuth
+// @Summary   		Create Collection
+// @Description 	Api for creating a named collection of posts. Public collections can be opened by anyone with the link.
+// @Tags 			collection
+// @Accept 			json
+// @Produce 		json
+// @Param 			data body models.CollectionReq true "Collection"
+// @Success 		201 {object} models.Collection
+// @Failure 		400 {object} models.Error
+// @Failure 		401 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/collection [POST]
+func (h *HandlerV1) CreateCollection(c *gin.Context) {
+	var body models.CollectionReq
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	userId, statusCode := GetIdFromToken(c.Request, &h.Config)
+	if statusCode != 0 {
+		c.JSON(http.StatusUnauthorized, models.Error{
+			Message: models.TokenInvalidMessage,
+		})
+		return
+	}
+
+	err := c.ShouldBindJSON(&body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	collection, err := h.Service.Collection().CreateCollection(ctx, &entity.Collection{
+		UserId:      userId,
+		Name:        body.Name,
+		Description: body.Description,
+		Public:      body.Public,
+	})
+	if err != nil {
+		c.JSON(collectionErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, collectionResponse(collection))
+}
+
+// @Security  		BearerAuth
+// @Summary   		Update Collection
+// @Description 	Api for renaming a collection, changing its description or making it public or private
+// @Tags 			collection
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path string true "Collection ID"
+// @Param 			data body models.CollectionReq true "Collection"
+// @Success 		200 {object} models.Collection
+// @Failure 		400 {object} models.Error
+// @Failure 		401 {object} models.Error
+// @Failure 		403 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/collection/{id} [PUT]
+func (h *HandlerV1) UpdateCollection(c *gin.Context) {
+	var body models.CollectionReq
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	collection, ok := h.ownCollection(ctx, c)
+	if !ok {
+		return
+	}
+
+	err := c.ShouldBindJSON(&body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	collection.Name = body.Name
+	collection.Description = body.Description
+	collection.Public = body.Public
+	collection, err = h.Service.Collection().UpdateCollection(ctx, collection)
+	if err != nil {
+		c.JSON(collectionErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, collectionResponse(collection))
+}
+
+// @Security  		BearerAuth
+// @Summary   		Delete Collection
+// @Description 	Api for deleting a collection. The posts in it are not touched.
+// @Tags 			collection
+// @Produce 		json
+// @Param 			id path string true "Collection ID"
+// @Success 		200 {object} models.Response
+// @Failure 		401 {object} models.Error
+// @Failure 		403 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/collection/{id} [DELETE]
+func (h *HandlerV1) DeleteCollection(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	collection, ok := h.ownCollection(ctx, c)
+	if !ok {
+		return
+	}
+
+	err := h.Service.Collection().DeleteCollection(ctx, &entity.DeleteReq{
+		Id: collection.Id,
+	})
+	if err != nil {
+		c.JSON(collectionErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Response: "Collection deleted",
+	})
+}
+
+// @Security  		BearerAuth
+// @Summary   		Get Collection
+// @Description 	Api for getting a collection with its posts in order. Public collections can be opened without a token; private ones only by their owner.
+// @Tags 			collection
+// @Produce 		json
+// @Param 			id path string true "Collection ID"
+// @Success 		200 {object} models.Collection
+// @Failure 		403 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/collection/{id} [GET]
+func (h *HandlerV1) GetCollection(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	collection, err := h.Service.Collection().GetCollection(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(collectionErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	userId, _ := GetIdFromToken(c.Request, &h.Config)
+	role, _ := GetRoleFromToken(c.Request, &h.Config)
+	if !collection.Public && collection.UserId != userId && role != "admin" {
+		c.JSON(http.StatusForbidden, models.Error{
+			Message: models.NoAccessMessage,
+		})
+		return
+	}
+
+	posts, err := h.Service.Collection().Posts(ctx, collection.Id)
+	if err != nil {
+		c.JSON(collectionErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	response := collectionResponse(collection)
+	response.Posts = h.postsResponse(posts)
+	if err := h.lockPaidPosts(ctx, c, response.Posts...); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Security  		BearerAuth
+// @Summary   		List Collections
+// @Description 	Api for listing collections, newest first. Without user_id the caller's own collections are listed; with it only that user's public ones. Page with page and limit, or leave page out and follow next_cursor and prev_cursor.
+// @Tags 			collection
+// @Produce 		json
+// @Param 			user_id query string false "User ID"
+// @Param 			page query int false "Page"
+// @Param 			limit query int true "Limit"
+// @Param 			cursor query string false "Cursor from a previous response"
+// @Param 			count query bool false "Set to true for total_count when paging by cursor"
+// @Success 		200 {object} models.ListCollection
+// @Failure 		400 {object} models.Error
+// @Failure 		401 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Router 			/v1/user/collections [GET]
+func (h *HandlerV1) ListUserCollections(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	userId, statusCode := GetIdFromToken(c.Request, &h.Config)
+	if statusCode != 0 {
+		c.JSON(http.StatusUnauthorized, models.Error{
+			Message: models.TokenInvalidMessage,
+		})
+		return
+	}
+
+	filter := map[string]string{
+		"user_id": userId,
+	}
+	if owner := c.Query("user_id"); owner != "" && owner != userId {
+		filter["user_id"] = owner
+		filter["public"] = "true"
+	}
+
+	req, ok := listRequest(c, filter)
+	if !ok {
+		return
+	}
+	listCollection, err := h.Service.Collection().ListCollection(ctx, req)
+	if err != nil {
+		c.JSON(listErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	response := models.ListCollection{
+		Collections: []*models.Collection{},
+		TotalCount:  listCollection.TotalCount,
+		NextCursor:  encodeCursor(listCollection.NextCursor),
+		PrevCursor:  encodeCursor(listCollection.PrevCursor),
+	}
+	for _, collection := range listCollection.Collection {
+		response.Collections = append(response.Collections, collectionResponse(collection))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Security  		BearerAuth
+// @Summary   		Add Post To Collection
+// @Description 	Api for adding a post to the end of a collection. Adding a post that is there already keeps its place.
+// @Tags 			collection
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path string true "Collection ID"
+// @Param 			data body models.CollectionPostReq true "Post"
+// @Success 		200 {object} models.Response
+// @Failure 		400 {object} models.Error
+// @Failure 		401 {object} models.Error
+// @Failure 		403 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/collection/{id}/posts [POST]
+func (h *HandlerV1) AddCollectionPost(c *gin.Context) {
+	var body models.CollectionPostReq
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	collection, ok := h.ownCollection(ctx, c)
+	if !ok {
+		return
+	}
+
+	err := c.ShouldBindJSON(&body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	err = h.Service.Collection().AddPost(ctx, collection.Id, body.PostId)
+	if err != nil {
+		c.JSON(collectionErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Response: "Post added to collection",
+	})
+}
+
+// @Security  		BearerAuth
+// @Summary   		Remove Post From Collection
+// @Description 	Api for taking a post out of a collection
+// @Tags 			collection
+// @Produce 		json
+// @Param 			id path string true "Collection ID"
+// @Param 			post_id path string true "Post ID"
+// @Success 		200 {object} models.Response
+// @Failure 		401 {object} models.Error
+// @Failure 		403 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/collection/{id}/posts/{post_id} [DELETE]
+func (h *HandlerV1) RemoveCollectionPost(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	collection, ok := h.ownCollection(ctx, c)
+	if !ok {
+		return
+	}
+
+	err := h.Service.Collection().RemovePost(ctx, collection.Id, c.Param("post_id"))
+	if err != nil {
+		c.JSON(collectionErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Response: "Post removed from collection",
+	})
+}
+
+// @Security  		BearerAuth
+// @Summary   		Reorder Collection
+// @Description 	Api for changing the order of the posts of a collection. post_ids must list every post of the collection once, in the new order.
+// @Tags 			collection
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path string true "Collection ID"
+// @Param 			data body models.CollectionReorderReq true "Post IDs"
+// @Success 		200 {object} models.Response
+// @Failure 		400 {object} models.Error
+// @Failure 		401 {object} models.Error
+// @Failure 		403 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/collection/{id}/posts [PUT]
+func (h *HandlerV1) ReorderCollection(c *gin.Context) {
+	var body models.CollectionReorderReq
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	collection, ok := h.ownCollection(ctx, c)
+	if !ok {
+		return
+	}
+
+	err := c.ShouldBindJSON(&body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	err = h.Service.Collection().Reorder(ctx, collection.Id, body.PostIds)
+	if err != nil {
+		c.JSON(collectionErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Response: "Collection reordered",
+	})
+}
+
+// ownCollection loads the collection named in the path and checks that the
+// caller is its owner or an admin. On failure the error response is already
+// written.
+func (h *HandlerV1) ownCollection(ctx context.Context, c *gin.Context) (*entity.Collection, bool) {
+	userId, statusCode := GetIdFromToken(c.Request, &h.Config)
+	if statusCode != 0 {
+		c.JSON(http.StatusUnauthorized, models.Error{
+			Message: models.TokenInvalidMessage,
+		})
+		return nil, false
+	}
+	role, _ := GetRoleFromToken(c.Request, &h.Config)
+
+	collection, err := h.Service.Collection().GetCollection(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(collectionErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return nil, false
+	}
+	if collection.UserId != userId && role != "admin" {
+		c.JSON(http.StatusForbidden, models.Error{
+			Message: models.NoAccessMessage,
+		})
+		return nil, false
+	}
+
+	return collection, true
+}
+
+// postsResponse converts posts for a response. Paid posts still have to be
+// locked with lockPaidPosts.
+func (h *HandlerV1) postsResponse(posts []*entity.Post) []*models.Post {
+	response := []*models.Post{}
+	for _, post := range posts {
+		response = append(response, &models.Post{
+			Id:           post.Id,
+			UserId:       post.UserId,
+			Theme:        post.Theme,
+			Path:         post.Path,
+			PdfPath:      post.PdfPath,
+			PreviewUrls:  h.previewURLs(post.Previews),
+			Pages:        post.Pages,
+			Views:        post.Views,
+			UserViews:    post.Views - post.GuestViews,
+			GuestViews:   post.GuestViews,
+			Downloads:    post.Downloads,
+			Comments:     post.Comments,
+			RatingAvg:    post.RatingAvg,
+			RatingCount:  post.RatingCount,
+			CategoryId:   post.CategoryId,
+			Science:      post.Science,
+			SubjectId:    post.SubjectId,
+			Tags:         post.Tags,
+			Price:        post.Price,
+			PriceStatus:  post.PriceStatus,
+			UniversityId: post.UniversityId,
+			FacultyId:    post.FacultyId,
+			CourseId:     post.CourseId,
+		})
+	}
+
+	return response
+}
+
+func collectionResponse(collection *entity.Collection) *models.Collection {
+	return &models.Collection{
+		Id:          collection.Id,
+		UserId:      collection.UserId,
+		Name:        collection.Name,
+		Description: collection.Description,
+		Public:      collection.Public,
+		PostsCount:  collection.Posts,
+		CreatedAt:   collection.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   collection.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func collectionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrorNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entity.ErrorCollectionFull), errors.Is(err, entity.ErrorReorder):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -190,12 +190,14 @@ func (h *HandlerV1) GetComment(c *gin.Context) {
 
 // @Security  		BearerAuth
 // @Summary   		List Comment
-// @Description 	Api for getting list comment
+// @Description 	Api for getting list comment. Page with page and limit, or leave page out and follow next_cursor and prev_cursor.
 // @Tags 			comment
 // @Accept 			json
 // @Produce 		json
-// @Param 			page query uint64 true "Page"
+// @Param 			page query uint64 false "Page"
 // @Param 			limit query uint64 true "Limit"
+// @Param 			cursor query string false "Cursor from a previous response"
+// @Param 			count query bool false "Set to true for TotalCount when paging by cursor"
 // @Success 		200 {object} models.ListComment
 // @Failure 		404 {object} models.Error
 // @Failure 		401 {object} models.Error
@@ -206,34 +208,14 @@ func (h *HandlerV1) ListComment(c *gin.Context) {
 	
 	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
 	defer cancel()
-	page := c.Query("page")
-	limit := c.Query("limit")
-	pageInt, err := strconv.Atoi(page)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{
-			Message: err.Error(),
-		})
-		log.Println(err.Error())
-		return
-	}
-	limitInt, err := strconv.Atoi(limit)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{
-			Message: err.Error(),
-		})
-		log.Println(err.Error())
+
+	req, ok := listRequest(c, map[string]string{})
+	if !ok {
 		return
 	}
-
-	offset := (pageInt - 1) * limitInt
-	filter := map[string]string{}
-	listComment, err := h.Service.Comment().ListComment(ctx, &entity.ListReq{
-		Offset: offset,
-		Limit:  limitInt,
-		Filter: filter,
-	})
+	listComment, err := h.Service.Comment().ListComment(ctx, req)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.Error{
+		c.JSON(listErrorStatus(err), models.Error{
 			Message: err.Error(),
 		})
 		log.Println(err.Error())
@@ -255,6 +237,8 @@ func (h *HandlerV1) ListComment(c *gin.Context) {
 	c.JSON(http.StatusOK, models.ListComment{
 		Comment:    comments,
 		TotalCount: int(listComment.TotalCount),
+		NextCursor: encodeCursor(listComment.NextCursor),
+		PrevCursor: encodeCursor(listComment.PrevCursor),
 	})
 }
 
@@ -0,0 +1,113 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"univer/api/models"
+	"univer/internal/pkg/converter"
+	"univer/internal/pkg/filetype"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minio/minio-go/v7"
+)
+
+// @Security  		BearerAuth
+// @Summary   		Convert File
+// @Description 	Api for converting a .doc, .docx, .ppt, .pptx or .xls file to PDF
+// @Tags 			post
+// @Accept 			multipart/form-data
+// @Produce 		application/pdf
+// @Param 			file formData file true "File"
+// @Success 		200 {file} file
+// @Failure 		400 {object} models.Error
+// @Failure 		401 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/post/convert [POST]
+func (h *HandlerV1) ConvertFile(c *gin.Context) {
+	var file models.File
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout+h.Config.Converter.Timeout)
+	defer cancel()
+
+	err := c.ShouldBind(&file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	if file.File.Size > 10<<20 {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: "File size cannot be larger than 10 MB",
+		})
+		return
+	}
+	if !converter.Convertible(filepath.Ext(file.File.Filename)) {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: "Only .doc, .docx, .ppt, .pptx, .xls and .xlsx format files can be converted",
+		})
+		return
+	}
+
+	src, err := file.File.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err)
+		return
+	}
+	defer src.Close()
+
+	if _, err := filetype.Validate(src, file.File.Size, filepath.Ext(file.File.Filename)); err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	pdf, err := h.Converter.Convert(ctx, file.File.Filename, src)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	name := strings.TrimSuffix(filepath.Base(file.File.Filename), filepath.Ext(file.File.Filename)) + ".pdf"
+	c.Header("Content-Disposition", "attachment; filename=\""+name+"\"")
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+// convertPost renders the uploaded office document of a post to PDF, stores it
+// next to the original and returns the PDF bytes.
+func (h *HandlerV1) convertPost(ctx context.Context, postId string, version int, objectName string) ([]byte, error) {
+	object, err := h.MinIO.GetObject(ctx, h.Config.Minio.FileUploadBucketName, objectName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+
+	pdf, err := h.Converter.Convert(ctx, objectName, object)
+	if err != nil {
+		return nil, err
+	}
+
+	pdfName := strings.TrimSuffix(objectName, filepath.Ext(objectName)) + ".pdf"
+	_, err = h.MinIO.PutObject(ctx, h.Config.Minio.FileUploadBucketName, pdfName, bytes.NewReader(pdf), int64(len(pdf)), minio.PutObjectOptions{
+		ContentType: "application/pdf",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pdf, h.Service.Post().UpdatePdfPath(ctx, postId, version, pdfName)
+}
@@ -0,0 +1,150 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"univer/api/models"
+	"univer/internal/entity"
+	"univer/internal/pkg/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultDownloadStatsDays = 30
+	maxDownloadStatsDays     = 365
+)
+
+// newDownload is the download of a post's file the caller is about to make,
+// or nil for authors fetching their own file. Guests download under their
+// visitor id, whose cookie has to be set before the response is written, so
+// it is called before the file is handed out.
+func (h *HandlerV1) newDownload(c *gin.Context, post *entity.Post, version int) *entity.Download {
+	download := &entity.Download{
+		PostId:  post.Id,
+		Version: version,
+	}
+	if userId, statusCode := GetIdFromToken(c.Request, &h.Config); statusCode == 0 {
+		if userId == post.UserId {
+			return nil
+		}
+		download.UserId = userId
+	} else {
+		download.UserId = h.visitorId(c)
+		download.Anonymous = true
+	}
+	if c.Query("format") == "pdf" {
+		download.Format = "pdf"
+	}
+
+	return download
+}
+
+// countDownload counts a download once its URL is issued. A student or guest
+// fetching the file again the same day counts once, and failing to count one
+// does not fail the download.
+func (h *HandlerV1) countDownload(ctx context.Context, download *entity.Download) {
+	if download == nil {
+		return
+	}
+	if err := h.Service.Post().CreateDownload(ctx, download); err != nil {
+		log.Println(err.Error())
+	}
+}
+
+// @Security  		BearerAuth
+// @Summary   		Post Download Stats
+// @Description 	Api for the author of a post to see how often its file was downloaded: in total, how often by guests, by how many students, and per day for the last days days
+// @Tags 			post
+// @Produce 		json
+// @Param 			id path string true "Post ID"
+// @Param 			days query int false "Days, 30 by default and at most 365"
+// @Success 		200 {object} models.DownloadStats
+// @Failure 		400 {object} models.Error
+// @Failure 		403 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/post/{id}/downloads [GET]
+func (h *HandlerV1) PostDownloadStats(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	id := c.Param("id")
+	if !validation.ValidateUUID(id) {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: "invalid post id",
+		})
+		return
+	}
+
+	days := defaultDownloadStatsDays
+	if value := c.Query("days"); value != "" {
+		var err error
+		if days, err = strconv.Atoi(value); err != nil || days < 1 || days > maxDownloadStatsDays {
+			c.JSON(http.StatusBadRequest, models.Error{
+				Message: "days must be between 1 and " + strconv.Itoa(maxDownloadStatsDays),
+			})
+			return
+		}
+	}
+
+	post, err := h.Service.Post().GetPost(ctx, &entity.GetReq{
+		Filter: map[string]string{
+			"id": id,
+		},
+	})
+	if err != nil {
+		c.JSON(downloadErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	userId, _ := GetIdFromToken(c.Request, &h.Config)
+	role, _ := GetRoleFromToken(c.Request, &h.Config)
+	if post.UserId != userId && role != "admin" {
+		c.JSON(http.StatusForbidden, models.Error{
+			Message: "only the author can see the downloads of a post",
+		})
+		return
+	}
+
+	stats, err := h.Service.Post().DownloadStats(ctx, post.Id, days)
+	if err != nil {
+		c.JSON(downloadErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	response := models.DownloadStats{
+		PostId:         stats.PostId,
+		Downloads:      stats.Downloads,
+		GuestDownloads: stats.GuestDownloads,
+		Downloaders:    stats.Downloaders,
+		Since:          stats.Since.Format("2006-01-02"),
+		Days:           []*models.DownloadDay{},
+	}
+	for _, day := range stats.Days {
+		response.Days = append(response.Days, &models.DownloadDay{
+			Date:      day.Date.Format("2006-01-02"),
+			Downloads: day.Downloads,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func downloadErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrorNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
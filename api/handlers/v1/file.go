@@ -0,0 +1,229 @@
+package v1
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"univer/api/models"
+	"univer/internal/entity"
+	"univer/internal/pkg/filetype"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minio/minio-go/v7"
+)
+
+// hashFile returns the hex encoded sha256 of r and rewinds it for the upload.
+func hashFile(r io.ReadSeeker) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, r); err != nil {
+		return "", err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// storePostFile validates a post file sent with the request and stores it
+// under objectId unless the same content is stored already. On failure the
+// error response is already written.
+func (h *HandlerV1) storePostFile(ctx context.Context, c *gin.Context, header *multipart.FileHeader, objectId string) (*entity.File, *entity.Post, bool) {
+	if header.Size > 10<<20 {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: "File size cannot be larger than 10 MB, use /v1/upload for larger files",
+		})
+		return nil, nil, false
+	}
+	ext := strings.ToLower(filepath.Ext(header.Filename))
+	if !postFileExtensions[ext] {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: "Only .pdf, .doc, .docx, .ppt, .pptx, .xls, .xlsx, .xlsm and .zip format files are accepted"})
+		return nil, nil, false
+	}
+
+	objectName := objectId + ext
+
+	fileHeader, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err)
+		return nil, nil, false
+	}
+	defer fileHeader.Close()
+
+	contentType, err := filetype.Validate(fileHeader, header.Size, ext)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return nil, nil, false
+	}
+
+	hash, err := hashFile(fileHeader)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err)
+		return nil, nil, false
+	}
+
+	stored, duplicate, err := h.acquirePostFile(ctx, &entity.File{
+		Hash:        hash,
+		Path:        objectName,
+		Size:        header.Size,
+		ContentType: contentType,
+	}, func(objectName string) error {
+		_, err := h.MinIO.PutObject(ctx, h.Config.Minio.FileUploadBucketName, objectName, fileHeader, header.Size, minio.PutObjectOptions{
+			ContentType: contentType,
+		})
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err)
+		return nil, nil, false
+	}
+
+	return stored, duplicate, true
+}
+
+// acquirePostFile takes a reference to the blob with file.Hash and returns the
+// stored file together with the oldest live post already using it. upload
+// writes the content under the object name it is given; pass nil when the
+// object has been written under file.Path already. The reference is taken
+// first, so the content is only written when the blob is new or its object
+// is missing, for instance because the request that stored it failed.
+func (h *HandlerV1) acquirePostFile(ctx context.Context, file *entity.File, upload func(objectName string) error) (*entity.File, *entity.Post, error) {
+	written := upload == nil
+	if written {
+		upload = func(objectName string) error {
+			_, err := h.MinIO.CopyObject(ctx, minio.CopyDestOptions{
+				Bucket: h.Config.Minio.FileUploadBucketName,
+				Object: objectName,
+			}, minio.CopySrcOptions{
+				Bucket: h.Config.Minio.FileUploadBucketName,
+				Object: file.Path,
+			})
+			return err
+		}
+	}
+
+	acquired, err := h.Service.File().Acquire(ctx, file)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if acquired.Path == file.Path {
+		if !written {
+			if err := upload(file.Path); err != nil {
+				h.releasePostFile(ctx, file.Hash)
+				return nil, nil, err
+			}
+		}
+	} else {
+		if written {
+			// our copy is not needed once the stored object is in place
+			defer h.removeObject(ctx, file.Path)
+		}
+		_, err := h.MinIO.StatObject(ctx, h.Config.Minio.FileUploadBucketName, acquired.Path, minio.StatObjectOptions{})
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			err = upload(acquired.Path)
+		}
+		if err != nil {
+			h.releasePostFile(ctx, file.Hash)
+			return nil, nil, err
+		}
+	}
+	if acquired.RefCount == 1 {
+		return acquired, nil, nil
+	}
+
+	duplicate, err := h.Service.Post().GetPost(ctx, &entity.GetReq{
+		Filter: map[string]string{
+			"file_hash": file.Hash,
+		},
+	})
+	if errors.Is(err, entity.ErrorNotFound) {
+		return acquired, nil, nil
+	}
+	if err != nil {
+		h.releasePostFile(ctx, file.Hash)
+		return nil, nil, err
+	}
+
+	return acquired, duplicate, nil
+}
+
+// releasePostFile drops the reference a post held on its blob. The object and
+// its PDF rendition are removed together with the last reference.
+func (h *HandlerV1) releasePostFile(ctx context.Context, hash string) {
+	file, err := h.Service.File().Release(ctx, hash)
+	if err != nil {
+		log.Println("release file", hash, err.Error())
+		return
+	}
+	if file == nil {
+		return
+	}
+
+	h.removeObject(ctx, file.Path)
+	if ext := filepath.Ext(file.Path); ext != ".pdf" {
+		h.removeObject(ctx, strings.TrimSuffix(file.Path, ext)+".pdf")
+	}
+}
+
+func (h *HandlerV1) removeObject(ctx context.Context, objectName string) {
+	err := h.MinIO.RemoveObject(ctx, h.Config.Minio.FileUploadBucketName, objectName, minio.RemoveObjectOptions{})
+	if err != nil {
+		log.Println("remove object", objectName, err.Error())
+	}
+}
+
+// startPostProcessing reuses the PDF and previews of a post with the same
+// content when they are ready and falls back to processing the upload. The
+// text is extracted either way.
+func (h *HandlerV1) startPostProcessing(ctx context.Context, postId string, version int, objectName string, duplicate *entity.Post) {
+	if duplicate == nil || (duplicate.PdfPath == "" && len(duplicate.Previews) == 0) {
+		go h.processPost(postId, version, objectName)
+		return
+	}
+
+	if duplicate.PdfPath != "" {
+		if err := h.Service.Post().UpdatePdfPath(ctx, postId, version, duplicate.PdfPath); err != nil {
+			log.Println("reuse pdf", postId, err.Error())
+		}
+	}
+	if len(duplicate.Previews) != 0 {
+		if err := h.Service.Post().UpdatePreviews(ctx, postId, version, duplicate.Previews); err != nil {
+			log.Println("reuse previews", postId, err.Error())
+		}
+	}
+
+	go h.reindexPost(postId, version, objectName, duplicate.PdfPath)
+}
+
+func postCreateResponse(postId string, duplicate *entity.Post) models.PostCreateResponse {
+	response := models.PostCreateResponse{
+		Id: postId,
+	}
+	if duplicate != nil {
+		response.DuplicateOf = duplicate.Id
+		response.Warning = "duplicate of post " + duplicate.Id
+	}
+
+	return response
+}
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"fmt"
 	"image/png"
 	"io/ioutil"
 	"log"
@@ -211,7 +210,7 @@ func (h *HandlerV1) GoogleCallback(c *gin.Context) {
 		return
 	}
 
-	minioURL := fmt.Sprintf("http://localhost:9000/%s/%s", h.Config.Minio.ImageUrlUploadBucketName, objectName)
+	minioURL := h.publicObjectURL(h.Config.Minio.ImageUrlUploadBucketName, objectName)
 
 	Resp, err := h.Service.User().CreateUser(ctx, &entity.User{
 		Id:       id,
@@ -1,10 +1,14 @@
 package v1
 
 import (
+	"fmt"
 	"time"
 	"univer/internal/infrastructure/clientService"
 	repo "univer/internal/infrastructure/repository/redisdb"
 	"univer/internal/pkg/config"
+	"univer/internal/pkg/converter"
+	"univer/internal/pkg/extract"
+	"univer/internal/pkg/preview"
 	tokens "univer/internal/pkg/token"
 
 	"github.com/casbin/casbin/v2"
@@ -21,6 +25,9 @@ type HandlerV1 struct {
 	Enforcer       *casbin.Enforcer
 	Service        clientService.ServiceClient
 	MinIO          *minio.Client
+	Converter      converter.Converter
+	Preview        preview.Renderer
+	Extractor      extract.Extractor
 }
 
 // HandlerV1Config ...
@@ -33,6 +40,9 @@ type HandlerV1Config struct {
 	Enforcer       *casbin.Enforcer
 	Service        clientService.ServiceClient
 	MinIO          *minio.Client
+	Converter      converter.Converter
+	Preview        preview.Renderer
+	Extractor      extract.Extractor
 }
 
 // New ...
@@ -46,5 +56,13 @@ func New(c *HandlerV1Config) *HandlerV1 {
 		RefreshToken:   c.RefreshToken,
 		Service:        c.Service,
 		MinIO:          c.MinIO,
+		Converter:      c.Converter,
+		Preview:        c.Preview,
+		Extractor:      c.Extractor,
 	}
 }
+
+// publicObjectURL returns the address of an object in a public-read bucket.
+func (h *HandlerV1) publicObjectURL(bucket, object string) string {
+	return fmt.Sprintf("http://%s/%s/%s", h.Config.Minio.Endpoint, bucket, object)
+}
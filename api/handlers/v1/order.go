@@ -0,0 +1,278 @@
+package v1
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+	"univer/api/models"
+	"univer/internal/entity"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Security  		BearerAuth
+// @Summary   		Purchase Post
+// @Description 	Api for opening an order for a paid post. The order stays pending, and the post locked, until the payment provider confirms the payment
+// @Tags 			order
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path string true "Post ID"
+// @Success 		201 {object} models.Order
+// @Failure 		400 {object} models.Error
+// @Failure 		401 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		409 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/post/{id}/purchase [POST]
+func (h *HandlerV1) PurchasePost(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	userId, statusCode := GetIdFromToken(c.Request, &h.Config)
+	if statusCode != 0 {
+		c.JSON(http.StatusUnauthorized, models.Error{
+			Message: models.TokenInvalidMessage,
+		})
+		return
+	}
+
+	order, err := h.Service.Order().Purchase(ctx, &entity.Order{
+		UserId: userId,
+		PostId: c.Param("id"),
+	})
+	if err != nil {
+		c.JSON(orderErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.Order{
+		Id:        order.Id,
+		UserId:    order.UserId,
+		PostId:    order.PostId,
+		Price:     order.Price,
+		Status:    order.Status,
+		CreatedAt: order.CreatedAt.Format(time.RFC3339),
+	})
+}
+
+// @Summary   		Payment Callback
+// @Description 	Api for the payment provider to confirm an order as paid or failed. The body is signed with the shared webhook secret: X-Signature is the hex HMAC-SHA256 of the raw body
+// @Tags 			order
+// @Accept 			json
+// @Produce 		json
+// @Param 			X-Signature header string true "HMAC-SHA256 of the body"
+// @Param 			payment body models.PaymentCallback true "Payment"
+// @Success 		200 {object} models.Order
+// @Failure 		400 {object} models.Error
+// @Failure 		401 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		409 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/payments/callback [POST]
+func (h *HandlerV1) PaymentCallback(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: err.Error(),
+		})
+		return
+	}
+	if !validPaymentSignature(h.Config.Payment.WebhookSecret, body, c.GetHeader("X-Signature")) {
+		c.JSON(http.StatusUnauthorized, models.Error{
+			Message: "invalid payment signature",
+		})
+		return
+	}
+
+	var payment models.PaymentCallback
+	if err := json.Unmarshal(body, &payment); err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: err.Error(),
+		})
+		return
+	}
+	if payment.Status != entity.OrderStatusPaid && payment.Status != entity.OrderStatusFailed {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: "status must be paid or failed",
+		})
+		return
+	}
+	if payment.Status == entity.OrderStatusPaid && payment.TransactionId == "" {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: "a paid order needs a transaction id",
+		})
+		return
+	}
+
+	order, err := h.Service.Order().ConfirmPayment(ctx, &entity.Order{
+		Id:            payment.OrderId,
+		Status:        payment.Status,
+		TransactionId: payment.TransactionId,
+		Price:         payment.Amount,
+	})
+	if err != nil {
+		c.JSON(orderErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Order{
+		Id:        order.Id,
+		UserId:    order.UserId,
+		PostId:    order.PostId,
+		Price:     order.Price,
+		Status:    order.Status,
+		CreatedAt: order.CreatedAt.Format(time.RFC3339),
+	})
+}
+
+// validPaymentSignature checks that signature is the hex HMAC-SHA256 of body
+// under secret. Nothing is valid without a secret.
+func validPaymentSignature(secret string, body []byte, signature string) bool {
+	if secret == "" {
+		return false
+	}
+	sum, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(sum, mac.Sum(nil))
+}
+
+func orderErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrorNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entity.ErrorPurchased), errors.Is(err, entity.ErrorOrderClosed):
+		return http.StatusConflict
+	case errors.Is(err, entity.ErrorFreePost), errors.Is(err, entity.ErrorOwnPost), errors.Is(err, entity.ErrorPaymentAmount):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// @Security  		BearerAuth
+// @Summary   		List Orders
+// @Description 	Api for getting the current user's purchases
+// @Tags 			order
+// @Accept 			json
+// @Produce 		json
+// @Param 			page query int true "Page"
+// @Param 			limit query int true "Limit"
+// @Success 		200 {object} models.ListOrder
+// @Failure 		400 {object} models.Error
+// @Failure 		401 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/user/orders [GET]
+func (h *HandlerV1) ListOrders(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	userId, statusCode := GetIdFromToken(c.Request, &h.Config)
+	if statusCode != 0 {
+		c.JSON(http.StatusUnauthorized, models.Error{
+			Message: models.TokenInvalidMessage,
+		})
+		return
+	}
+
+	pageInt, err := strconv.Atoi(c.Query("page"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+	limitInt, err := strconv.Atoi(c.Query("limit"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	listOrder, err := h.Service.Order().ListOrder(ctx, &entity.ListReq{
+		Offset: (pageInt - 1) * limitInt,
+		Limit:  limitInt,
+		Filter: map[string]string{
+			"user_id": userId,
+			"status":  entity.OrderStatusPaid,
+		},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	var orders []*models.Order
+	for _, order := range listOrder.Order {
+		orders = append(orders, &models.Order{
+			Id:        order.Id,
+			UserId:    order.UserId,
+			PostId:    order.PostId,
+			Price:     order.Price,
+			Status:    order.Status,
+			CreatedAt: order.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	c.JSON(http.StatusOK, models.ListOrder{
+		Orders:     orders,
+		TotalCount: listOrder.TotalCount,
+	})
+}
+
+// lockPaidPosts clears Path on every post the caller has not bought and marks
+// it as locked, so paid materials are only reachable after a purchase.
+func (h *HandlerV1) lockPaidPosts(ctx context.Context, c *gin.Context, posts ...*models.Post) error {
+	userId, _ := GetIdFromToken(c.Request, &h.Config)
+	role, _ := GetRoleFromToken(c.Request, &h.Config)
+
+	items := make([]*entity.Post, 0, len(posts))
+	for _, post := range posts {
+		items = append(items, &entity.Post{
+			Id:          post.Id,
+			UserId:      post.UserId,
+			PriceStatus: post.PriceStatus,
+		})
+	}
+
+	access, err := h.Service.Order().CanAccess(ctx, userId, role, items...)
+	if err != nil {
+		return err
+	}
+
+	for _, post := range posts {
+		if !access[post.Id] {
+			post.Path = ""
+			post.PdfPath = ""
+			post.Locked = true
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package v1
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"univer/api/models"
+	"univer/internal/entity"
+	"univer/internal/pkg/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+// listRequest reads the paging of a list endpoint. Clients either send page
+// and limit, as before, and always get the total count, or follow the
+// next_cursor and prev_cursor tokens of the previous response. The first
+// cursor page is asked for without page; the count is then only made with
+// count=true. On failure the error response is already written.
+func listRequest(c *gin.Context, filter map[string]string) (*entity.ListReq, bool) {
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+
+	req := &entity.ListReq{
+		Limit:     limit,
+		Filter:    filter,
+		SkipCount: c.Query("count") != "true",
+	}
+
+	switch {
+	case c.Query("cursor") != "":
+		req.Cursor, err = decodeCursor(c.Query("cursor"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.Error{
+				Message: "invalid cursor",
+			})
+			return nil, false
+		}
+	case c.Query("page") != "":
+		page, err := strconv.Atoi(c.Query("page"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.Error{
+				Message: err.Error(),
+			})
+			return nil, false
+		}
+		req.Offset = (page - 1) * limit
+		req.SkipCount = false
+	}
+
+	return req, true
+}
+
+// postSorts are the orders a post list accepts in its sort parameter.
+var postSorts = map[string]bool{
+	entity.SortNewest:    true,
+	entity.SortOldest:    true,
+	entity.SortViews:     true,
+	entity.SortComments:  true,
+	entity.SortPriceAsc:  true,
+	entity.SortPriceDesc: true,
+	entity.SortRating:    true,
+}
+
+// postSort reads the sort parameter of a post list; relevance is only
+// accepted by searches. On failure the error response is already written.
+func postSort(c *gin.Context, relevance bool) (string, bool) {
+	sort := c.Query("sort")
+	if sort == "" || postSorts[sort] || (relevance && sort == entity.SortRelevance) {
+		return sort, true
+	}
+
+	c.JSON(http.StatusBadRequest, models.Error{
+		Message: "unknown sort " + sort,
+	})
+	return "", false
+}
+
+// listErrorStatus maps an error of a list endpoint to its status code.
+func listErrorStatus(err error) int {
+	if errors.Is(err, entity.ErrorInvalidCursor) {
+		return http.StatusBadRequest
+	}
+	return http.StatusNotFound
+}
+
+// encodeCursor turns a cursor into the opaque token handed to clients, "" for
+// no cursor.
+func encodeCursor(cursor *entity.Cursor) string {
+	if cursor == nil {
+		return ""
+	}
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(token string) (*entity.Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor entity.Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+	if !validation.ValidateUUID(cursor.Id) {
+		return nil, entity.ErrorInvalidCursor
+	}
+
+	return &cursor, nil
+}
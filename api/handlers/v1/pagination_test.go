@@ -0,0 +1,83 @@
+package v1
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+	"univer/internal/entity"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	key := 4.5
+	createdAt := time.Date(2024, 3, 1, 12, 30, 0, 123000000, time.UTC)
+
+	tests := []struct {
+		name   string
+		cursor *entity.Cursor
+	}{
+		{
+			name:   "newest",
+			cursor: &entity.Cursor{CreatedAt: createdAt, Id: "3f1c2f4e-8a5b-4c1d-9e0f-6a7b8c9d0e1f"},
+		},
+		{
+			name: "sorted by key, backwards",
+			cursor: &entity.Cursor{
+				Sort:      entity.SortRating,
+				Key:       &key,
+				CreatedAt: createdAt,
+				Id:        "3f1c2f4e-8a5b-4c1d-9e0f-6a7b8c9d0e1f",
+				Before:    true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := encodeCursor(tt.cursor)
+			if token == "" {
+				t.Fatal("encodeCursor() returned no token")
+			}
+
+			got, err := decodeCursor(token)
+			if err != nil {
+				t.Fatalf("decodeCursor() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.cursor) {
+				t.Errorf("decodeCursor() = %+v, want %+v", got, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestEncodeNoCursor(t *testing.T) {
+	if token := encodeCursor(nil); token != "" {
+		t.Errorf("encodeCursor(nil) = %q, want empty", token)
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+		err   error
+	}{
+		{"not base64", "not a cursor!", nil},
+		{"not json", base64.RawURLEncoding.EncodeToString([]byte("page 2")), nil},
+		{"id is not a uuid", base64.RawURLEncoding.EncodeToString([]byte(`{"Id":"1 OR 1=1"}`)), entity.ErrorInvalidCursor},
+		{"no id", base64.RawURLEncoding.EncodeToString([]byte(`{}`)), entity.ErrorInvalidCursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := decodeCursor(tt.token)
+			if err == nil {
+				t.Fatalf("decodeCursor() = %+v, want an error", cursor)
+			}
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("decodeCursor() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
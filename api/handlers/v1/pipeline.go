@@ -0,0 +1,170 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"univer/internal/entity"
+	"univer/internal/pkg/converter"
+	"univer/internal/pkg/extract"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// processPost runs the work that follows an upload: office documents are
+// converted to PDF, the first pages of the PDF are rendered to thumbnails and
+// the text of the file is extracted for search. It runs after the upload
+// request has returned, so failures are only logged and the post stays
+// downloadable in its original format. Results are only stored while the post
+// is still at version, a newer revision is processed on its own.
+func (h *HandlerV1) processPost(postId string, version int, objectName string) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout+h.Config.Converter.Timeout+h.Config.Preview.Timeout+h.Config.Extract.Timeout)
+	defer cancel()
+
+	var (
+		pdf io.ReadSeeker
+		err error
+	)
+	switch ext := strings.ToLower(filepath.Ext(objectName)); {
+	case converter.Convertible(ext):
+		var converted []byte
+		if converted, err = h.convertPost(ctx, postId, version, objectName); converted != nil {
+			pdf = bytes.NewReader(converted)
+		}
+	case ext == ".pdf":
+		var file *os.File
+		if file, err = h.spoolObject(ctx, h.Config.Minio.FileUploadBucketName, objectName); err == nil {
+			defer removeSpool(file)
+			pdf = file
+		}
+	}
+	if errors.Is(err, entity.ErrorNotFound) {
+		// the post was revised or deleted while converting
+		return
+	}
+	if err != nil {
+		log.Println("process post", postId, err.Error())
+	}
+
+	if pdf != nil {
+		if err := h.previewPost(ctx, postId, version, objectName, pdf); err != nil {
+			log.Println("process post", postId, err.Error())
+		}
+	}
+
+	if err := h.extractPost(ctx, postId, version, objectName, pdf); err != nil {
+		log.Println("process post", postId, err.Error())
+	}
+}
+
+// reindexPost extracts the text of a post that took its PDF and previews from
+// another post with the same file or from one of its older versions.
+func (h *HandlerV1) reindexPost(postId string, version int, objectName, pdfPath string) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout+h.Config.Extract.Timeout)
+	defer cancel()
+
+	var pdf io.ReadSeeker
+	if pdfPath != "" {
+		file, err := h.spoolObject(ctx, h.Config.Minio.FileUploadBucketName, pdfPath)
+		if err != nil {
+			log.Println("reindex post", postId, err.Error())
+		} else {
+			defer removeSpool(file)
+			pdf = file
+		}
+	}
+
+	if err := h.extractPost(ctx, postId, version, objectName, pdf); err != nil {
+		log.Println("reindex post", postId, err.Error())
+	}
+}
+
+// extractPost stores the text of a post's file for search. Office Open XML
+// files are read directly, anything else through its PDF when it has one.
+func (h *HandlerV1) extractPost(ctx context.Context, postId string, version int, objectName string, pdf io.ReadSeeker) error {
+	var (
+		document *extract.Document
+		err      error
+	)
+	switch ext := strings.ToLower(filepath.Ext(objectName)); ext {
+	case ".docx", ".pptx", ".xlsx", ".xlsm":
+		file, err := h.spoolObject(ctx, h.Config.Minio.FileUploadBucketName, objectName)
+		if err != nil {
+			return err
+		}
+		defer removeSpool(file)
+
+		info, err := file.Stat()
+		if err != nil {
+			return err
+		}
+		document, err = extract.OOXML(file, info.Size(), ext)
+		if err != nil {
+			return err
+		}
+		// Word only saves a page count when it lays the document out
+		if document.Pages == 0 && pdf != nil {
+			if _, err := pdf.Seek(0, io.SeekStart); err == nil {
+				if rendered, err := h.Extractor.Extract(ctx, pdf); err == nil {
+					document.Pages = rendered.Pages
+				}
+			}
+		}
+	default:
+		if pdf == nil {
+			return nil
+		}
+		if _, err = pdf.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		document, err = h.Extractor.Extract(ctx, pdf)
+		if err != nil {
+			return err
+		}
+	}
+
+	document.Truncate(h.Config.Extract.MaxSize)
+	return h.Service.Post().UpdateContent(ctx, &entity.PostContent{
+		PostId:  postId,
+		Version: version,
+		Text:    document.Text,
+		Pages:   document.Pages,
+	})
+}
+
+// spoolObject copies an object to a temporary file, so files of any size can
+// be rendered and read more than once without holding them in memory. The
+// file is dropped with removeSpool.
+func (h *HandlerV1) spoolObject(ctx context.Context, bucketName, objectName string) (*os.File, error) {
+	object, err := h.MinIO.GetObject(ctx, bucketName, objectName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+
+	file, err := os.CreateTemp("", "object-*"+filepath.Ext(objectName))
+	if err != nil {
+		return nil, err
+	}
+	if _, err = io.Copy(file, object); err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		removeSpool(file)
+		return nil, err
+	}
+
+	return file, nil
+}
+
+func removeSpool(file *os.File) {
+	file.Close()
+	if err := os.Remove(file.Name()); err != nil {
+		log.Println("remove spool", file.Name(), err.Error())
+	}
+}
@@ -2,19 +2,32 @@ package v1
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
-	"path/filepath"
+	"net/url"
 	"strconv"
+	"strings"
 	"univer/api/models"
 	"univer/internal/entity"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"github.com/minio/minio-go/v7"
 )
 
+var postFileExtensions = map[string]bool{
+	".pdf":  true,
+	".doc":  true,
+	".docx": true,
+	".ppt":  true,
+	".pptx": true,
+	".xls":  true,
+	".xlsx": true,
+	".xlsm": true,
+	".zip":  true,
+}
+
 // @Security      BearerAuth
 // @Summary       Create Post
 // @Description   Api for create a new Post
@@ -25,8 +38,12 @@ import (
 // @Param         science query string true "Science"
 // @Param         id query string true "Category Id"
 // @Param         price query string false "Price"
+// @Param         tags query string false "Comma separated tags, at most 10"
+// @Param         university_id query string false "University ID"
+// @Param         faculty_id query string false "Faculty ID"
+// @Param         course_id query string false "Course ID; the faculty and university follow from it"
 // @Param         file formData file true "File"
-// @Success       201 {object} models.CreateResponse
+// @Success       201 {object} models.PostCreateResponse
 // @Failure       400 {object} models.Error
 // @Failure       401 {object} models.Error
 // @Failure       403 {object} models.Error
@@ -44,6 +61,12 @@ func (h *HandlerV1) CreatePost(c *gin.Context) {
 	body.CategoryId = c.Query("id")
 	body.Science = c.Query("science")
 	body.Theme = c.Query("theme")
+	body.UniversityId = c.Query("university_id")
+	body.FacultyId = c.Query("faculty_id")
+	body.CourseId = c.Query("course_id")
+	if tags := c.Query("tags"); tags != "" {
+		body.Tags = strings.Split(tags, ",")
+	}
 	price := c.Query("price")
 	var err error
 	body.Price, err = strconv.ParseFloat(price, 64)
@@ -64,58 +87,11 @@ func (h *HandlerV1) CreatePost(c *gin.Context) {
 		return
 	}
 
-	if file.File.Size > 10<<20 {
-		c.JSON(http.StatusBadRequest, models.Error{
-			Message: "File size cannot be larger than 10 MB",
-		})
-		return
-	}
-	ext := filepath.Ext(file.File.Filename)
-	allowedExtensions := map[string]bool{
-		".pdf":  true,
-		".doc":  true,
-		".docx": true,
-		".ppt":  true,
-		".pptx": true,
-		".xls":  true,
-		".xlsx": true,
-		".xlsm": true,
-		".zip":  true,
-	}
-
-	if !allowedExtensions[ext] {
-		c.JSON(http.StatusBadRequest, models.Error{
-			Message: "Only .pdf, .doc, .docx, .ppt, and .pptx format files are accepted"})
-		return
-	}
-
 	id := uuid.New().String()
-	objectName := id + ext
-
-	fileHeader, err := file.File.Open()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.Error{
-			Message: err.Error(),
-		})
-		log.Println(err)
+	stored, duplicate, ok := h.storePostFile(ctx, c, file.File, id)
+	if !ok {
 		return
 	}
-	defer fileHeader.Close()
-
-	contentType := c.ContentType()
-	_, err = h.MinIO.PutObject(ctx, h.Config.Minio.FileUploadBucketName, objectName, fileHeader, file.File.Size, minio.PutObjectOptions{
-		ContentType: contentType,
-	})
-
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.Error{
-			Message: err.Error(),
-		})
-		log.Println(err)
-		return
-	}
-
-	minioURL := fmt.Sprintf("http://localhost:9000/%s/%s", h.Config.Minio.FileUploadBucketName, objectName)
 
 	userId, statusCode := GetIdFromToken(c.Request, &h.Config)
 	if statusCode != 0 {
@@ -130,49 +106,38 @@ func (h *HandlerV1) CreatePost(c *gin.Context) {
 		})
 	}
 
+	post := &entity.Post{
+		Id:         id,
+		UserId:     userId,
+		Theme:      body.Theme,
+		Path:       stored.Path,
+		FileHash:   stored.Hash,
+		Science:    body.Science,
+		Tags:       body.Tags,
+		CategoryId: body.CategoryId,
+
+		UniversityId: body.UniversityId,
+		FacultyId:    body.FacultyId,
+		CourseId:     body.CourseId,
+	}
 	if body.Price > 0 && role == "prouser" {
-		post, err := h.Service.Post().CreatePost(ctx, &entity.Post{
-			Id:          id,
-			UserId:      userId,
-			Theme:       body.Theme,
-			Path:        minioURL,
-			Science:     body.Science,
-			CategoryId:  body.CategoryId,
-			PriceStatus: true,
-			Price:       body.Price,
-		})
-		if err != nil {
-			c.JSON(http.StatusBadRequest, models.Error{
-				Message: err.Error(),
-			})
-			log.Println(err.Error())
-			return
-		}
-
-		c.JSON(http.StatusCreated, models.CreateResponse{
-			Id: post.Id,
-		})
-	} else {
-		post, err := h.Service.Post().CreatePost(ctx, &entity.Post{
-			Id:         id,
-			UserId:     userId,
-			Theme:      body.Theme,
-			Path:       minioURL,
-			Science:    body.Science,
-			CategoryId: body.CategoryId,
-		})
-		if err != nil {
-			c.JSON(http.StatusBadRequest, models.Error{
-				Message: err.Error(),
-			})
-			log.Println(err.Error())
-			return
-		}
+		post.PriceStatus = true
+		post.Price = body.Price
+	}
 
-		c.JSON(http.StatusCreated, models.CreateResponse{
-			Id: post.Id,
+	post, err = h.Service.Post().CreatePost(ctx, post)
+	if err != nil {
+		h.releasePostFile(ctx, stored.Hash)
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: err.Error(),
 		})
+		log.Println(err.Error())
+		return
 	}
+
+	h.startPostProcessing(ctx, post.Id, post.Version, stored.Path, duplicate)
+
+	c.JSON(http.StatusCreated, postCreateResponse(post.Id, duplicate))
 }
 
 // @Security  		BearerAuth
@@ -209,50 +174,49 @@ func (h *HandlerV1) UpdatePost(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, models.Error{
 			Message: "oops something went wrong",
 		})
+		return
 	}
 
-	if role == "prouser" {
-		post, err := h.Service.Post().UpdatePost(ctx, &entity.PostUpdateReq{
-			Id:         body.Id,
-			Theme:      body.Theme,
-			Science:    body.Science,
-			CategoryId: body.CategoryId,
-		})
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.Error{
-				Message: err.Error(),
-			})
-			log.Println(err.Error())
-			return
-		}
-		c.JSON(http.StatusOK, models.PostUpdateReq{
-			Id:         post.Id,
-			Theme:      post.Theme,
-			Science:    post.Science,
-			CategoryId: post.CategoryId,
-		})
-	} else {
-		post, err := h.Service.Post().UpdatePost(ctx, &entity.PostUpdateReq{
-			Id:         body.Id,
-			Theme:      body.Theme,
-			Science:    body.Science,
-			CategoryId: body.CategoryId,
-			Price:      body.Price,
-		})
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.Error{
-				Message: err.Error(),
-			})
-			log.Println(err.Error())
-			return
-		}
-		c.JSON(http.StatusOK, models.PostUpdateReq{
-			Id:         post.Id,
-			Theme:      post.Theme,
-			Science:    post.Science,
-			CategoryId: post.CategoryId,
+	if _, ok := h.ownPostById(ctx, c, body.Id); !ok {
+		return
+	}
+
+	update := &entity.PostUpdateReq{
+		Id:         body.Id,
+		Theme:      body.Theme,
+		Science:    body.Science,
+		CategoryId: body.CategoryId,
+		Tags:       body.Tags,
+
+		UniversityId: body.UniversityId,
+		FacultyId:    body.FacultyId,
+		CourseId:     body.CourseId,
+	}
+	if body.Price > 0 && (role == "prouser" || role == "admin") {
+		update.PriceStatus = true
+		update.Price = body.Price
+	}
+
+	post, err := h.Service.Post().UpdatePost(ctx, update)
+	if err != nil {
+		c.JSON(tagErrorStatus(err), models.Error{
+			Message: err.Error(),
 		})
+		log.Println(err.Error())
+		return
 	}
+	c.JSON(http.StatusOK, models.PostUpdateReq{
+		Id:         post.Id,
+		Theme:      post.Theme,
+		Science:    post.Science,
+		CategoryId: post.CategoryId,
+		Price:      post.Price,
+		Tags:       post.Tags,
+
+		UniversityId: post.UniversityId,
+		FacultyId:    post.FacultyId,
+		CourseId:     post.CourseId,
+	})
 }
 
 // @Security  		BearerAuth
@@ -273,25 +237,33 @@ func (h *HandlerV1) DeletePost(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
 	defer cancel()
 
-	userID := c.Param("id")
+	post, ok := h.ownPost(ctx, c)
+	if !ok {
+		return
+	}
 
 	err := h.Service.Post().DeletePost(ctx, &entity.DeleteReq{
-		Id: userID,
+		Id: post.Id,
 	})
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.Error{
+		c.JSON(postVersionErrorStatus(err), models.Error{
 			Message: err.Error(),
 		})
 		log.Println(err.Error())
 		return
 	}
 
+	if post.FileHash != "" {
+		h.releasePostFile(ctx, post.FileHash)
+	}
+	h.releasePostVersions(ctx, post.Id)
+
 	c.JSON(http.StatusOK, true)
 }
 
 // @Security  		BearerAuth
 // @Summary   		Get Post
-// @Description 	Api for getting a post
+// @Description 	Api for getting a post. Guests can read posts too; their views are counted under a signed visitor cookie, or their address and user agent when they keep no cookies.
 // @Tags 			post
 // @Accept 			json
 // @Produce 		json
@@ -308,16 +280,15 @@ func (h *HandlerV1) GetPost(c *gin.Context) {
 
 	id := c.Param("id")
 
-	userId, statusCode := GetIdFromToken(c.Request, &h.Config)
-	if statusCode != 0 {
-		c.JSON(http.StatusBadRequest, models.Error{
-			Message: "oops something went wrong",
-		})
-	}
-
+	// the view is counted for the signed in student, or for the guest's
+	// visitor id
 	filter := map[string]string{
-		"id":      id,
-		"user_id": userId,
+		"id": id,
+	}
+	if userId, statusCode := GetIdFromToken(c.Request, &h.Config); statusCode == 0 {
+		filter["user_id"] = userId
+	} else {
+		filter["visitor_id"] = h.visitorId(c)
 	}
 	post, err := h.Service.Post().GetPost(ctx, &entity.GetReq{
 		Filter: filter,
@@ -330,17 +301,40 @@ func (h *HandlerV1) GetPost(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, models.Post{
-		Id:          id,
-		UserId:      post.UserId,
-		Theme:       post.Theme,
-		Path:        post.Path,
-		Science:     post.Science,
-		Views:       post.Views,
-		CategoryId:  post.CategoryId,
-		PriceStatus: post.PriceStatus,
-		Price:       post.Price,
-	})
+	response := models.Post{
+		Id:           id,
+		UserId:       post.UserId,
+		Theme:        post.Theme,
+		Path:         post.Path,
+		PdfPath:      post.PdfPath,
+		PreviewUrls:  h.previewURLs(post.Previews),
+		Pages:        post.Pages,
+		Science:      post.Science,
+		SubjectId:    post.SubjectId,
+		Tags:         post.Tags,
+		Views:        post.Views,
+		UserViews:    post.Views - post.GuestViews,
+		GuestViews:   post.GuestViews,
+		Downloads:    post.Downloads,
+		Comments:     post.Comments,
+		RatingAvg:    post.RatingAvg,
+		RatingCount:  post.RatingCount,
+		CategoryId:   post.CategoryId,
+		PriceStatus:  post.PriceStatus,
+		UniversityId: post.UniversityId,
+		FacultyId:    post.FacultyId,
+		CourseId:     post.CourseId,
+		Price:        post.Price,
+	}
+	if err := h.lockPaidPosts(ctx, c, &response); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 // @Security  		BearerAuth
@@ -378,27 +372,56 @@ func (h *HandlerV1) GetDelPost(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, models.Post{
-		Id:          userID,
-		UserId:      post.UserId,
-		Theme:       post.Theme,
-		Path:        post.Path,
-		Science:     post.Science,
-		Views:       post.Views,
-		CategoryId:  post.CategoryId,
-		PriceStatus: post.PriceStatus,
-		Price:       post.Price,
-	})
+	response := models.Post{
+		Id:           userID,
+		UserId:       post.UserId,
+		Theme:        post.Theme,
+		Path:         post.Path,
+		PdfPath:      post.PdfPath,
+		PreviewUrls:  h.previewURLs(post.Previews),
+		Pages:        post.Pages,
+		Science:      post.Science,
+		SubjectId:    post.SubjectId,
+		Tags:         post.Tags,
+		Views:        post.Views,
+		UserViews:    post.Views - post.GuestViews,
+		GuestViews:   post.GuestViews,
+		Downloads:    post.Downloads,
+		Comments:     post.Comments,
+		RatingAvg:    post.RatingAvg,
+		RatingCount:  post.RatingCount,
+		CategoryId:   post.CategoryId,
+		PriceStatus:  post.PriceStatus,
+		UniversityId: post.UniversityId,
+		FacultyId:    post.FacultyId,
+		CourseId:     post.CourseId,
+		Price:        post.Price,
+	}
+	if err := h.lockPaidPosts(ctx, c, &response); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 // @Security  		BearerAuth
 // @Summary   		List Post
-// @Description 	Api for getting list post
+// @Description 	Api for getting list post. The list is scoped to the caller's university unless a university, faculty or course is asked for. Page with page and limit, or leave page out and follow next_cursor and prev_cursor.
 // @Tags 			post
 // @Accept 			json
 // @Produce 		json
-// @Param 			page query uint64 true "Page"
+// @Param 			page query uint64 false "Page"
 // @Param 			limit query uint64 true "Limit"
+// @Param 			cursor query string false "Cursor from a previous response"
+// @Param 			count query bool false "Set to true for TotalCount when paging by cursor"
+// @Param 			sort query string false "Order of the posts, newest by default" Enums(newest, oldest, views, comments, price_asc, price_desc, rating)
+// @Param 			university_id query string false "University ID; the caller's own university and posts of none by default, all for every university"
+// @Param 			faculty_id query string false "Faculty ID"
+// @Param 			course_id query string false "Course ID"
 // @Success 		200 {object} models.ListPost
 // @Failure 		404 {object} models.Error
 // @Failure 		401 {object} models.Error
@@ -409,34 +432,23 @@ func (h *HandlerV1) ListPost(c *gin.Context) {
 
 	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
 	defer cancel()
-	page := c.Query("page")
-	limit := c.Query("limit")
-	pageInt, err := strconv.Atoi(page)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{
-			Message: err.Error(),
-		})
-		log.Println(err.Error())
+
+	filter := map[string]string{}
+	if !placementFilter(c, filter) {
 		return
 	}
-	limitInt, err := strconv.Atoi(limit)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{
-			Message: err.Error(),
-		})
-		log.Println(err.Error())
+	h.scopeToUniversity(ctx, c, filter)
+
+	req, ok := listRequest(c, filter)
+	if !ok {
 		return
 	}
-
-	offset := (pageInt - 1) * limitInt
-	filter := map[string]string{}
-	listPost, err := h.Service.Post().ListPost(ctx, &entity.ListReq{
-		Offset: offset,
-		Limit:  limitInt,
-		Filter: filter,
-	})
+	if req.Sort, ok = postSort(c, false); !ok {
+		return
+	}
+	listPost, err := h.Service.Post().ListPost(ctx, req)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.Error{
+		c.JSON(listErrorStatus(err), models.Error{
 			Message: err.Error(),
 		})
 		log.Println(err.Error())
@@ -446,32 +458,59 @@ func (h *HandlerV1) ListPost(c *gin.Context) {
 	var posts []*models.Post
 	for _, post := range listPost.Post {
 		posts = append(posts, &models.Post{
-			Id:          post.Id,
-			UserId:      post.UserId,
-			Theme:       post.Theme,
-			Path:        post.Path,
-			Views:       post.Views,
-			CategoryId:  post.CategoryId,
-			Science:     post.Science,
-			Price:       post.Price,
-			PriceStatus: post.PriceStatus,
+			Id:           post.Id,
+			UserId:       post.UserId,
+			Theme:        post.Theme,
+			Path:         post.Path,
+			PdfPath:      post.PdfPath,
+			PreviewUrls:  h.previewURLs(post.Previews),
+			Pages:        post.Pages,
+			Views:        post.Views,
+			UserViews:    post.Views - post.GuestViews,
+			GuestViews:   post.GuestViews,
+			Downloads:    post.Downloads,
+			Comments:     post.Comments,
+			RatingAvg:    post.RatingAvg,
+			RatingCount:  post.RatingCount,
+			CategoryId:   post.CategoryId,
+			Science:      post.Science,
+			SubjectId:    post.SubjectId,
+			Tags:         post.Tags,
+			Price:        post.Price,
+			PriceStatus:  post.PriceStatus,
+			UniversityId: post.UniversityId,
+			FacultyId:    post.FacultyId,
+			CourseId:     post.CourseId,
 		})
 	}
 
+	if err := h.lockPaidPosts(ctx, c, posts...); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
 	c.JSON(http.StatusOK, models.ListPost{
 		Post:       posts,
 		TotalCount: int(listPost.TotalCount),
+		NextCursor: encodeCursor(listPost.NextCursor),
+		PrevCursor: encodeCursor(listPost.PrevCursor),
 	})
 }
 
 // @Security  		BearerAuth
 // @Summary   		List Post
-// @Description 	Api for getting user's posts
+// @Description 	Api for getting user's posts. Page with page and limit, or leave page out and follow next_cursor and prev_cursor.
 // @Tags 			post
 // @Accept 			json
 // @Produce 		json
-// @Param 			page query int true "Page"
+// @Param 			page query int false "Page"
 // @Param 			limit query int true "Limit"
+// @Param 			cursor query string false "Cursor from a previous response"
+// @Param 			count query bool false "Set to true for TotalCount when paging by cursor"
+// @Param 			sort query string false "Order of the posts, newest by default" Enums(newest, oldest, views, comments, price_asc, price_desc, rating)
 // @Param 			id query string true "User Id"
 // @Success 		200 {object} models.ListPost
 // @Failure 		404 {object} models.Error
@@ -486,62 +525,164 @@ func (h *HandlerV1) GetAllPostByUserId(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
 	defer cancel()
 	body.UserId = c.Query("id")
-	page := c.Query("page")
-	limit := c.Query("limit")
-	pageInt, err := strconv.Atoi(page)
+
+	req, ok := listRequest(c, map[string]string{
+		"user_id": body.UserId,
+	})
+	if !ok {
+		return
+	}
+	if req.Sort, ok = postSort(c, false); !ok {
+		return
+	}
+	listPost, err := h.Service.Post().ListPost(ctx, req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{
+		c.JSON(listErrorStatus(err), models.Error{
 			Message: err.Error(),
 		})
 		log.Println(err.Error())
 		return
 	}
-	body.Page = pageInt
-	limitInt, err := strconv.Atoi(limit)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{
+
+	var posts []*models.Post
+	for _, post := range listPost.Post {
+		posts = append(posts, &models.Post{
+			Id:           post.Id,
+			UserId:       post.UserId,
+			Theme:        post.Theme,
+			Path:         post.Path,
+			PdfPath:      post.PdfPath,
+			PreviewUrls:  h.previewURLs(post.Previews),
+			Pages:        post.Pages,
+			Views:        post.Views,
+			UserViews:    post.Views - post.GuestViews,
+			GuestViews:   post.GuestViews,
+			Downloads:    post.Downloads,
+			Comments:     post.Comments,
+			RatingAvg:    post.RatingAvg,
+			RatingCount:  post.RatingCount,
+			CategoryId:   post.CategoryId,
+			Science:      post.Science,
+			SubjectId:    post.SubjectId,
+			Tags:         post.Tags,
+			Price:        post.Price,
+			PriceStatus:  post.PriceStatus,
+			UniversityId: post.UniversityId,
+			FacultyId:    post.FacultyId,
+			CourseId:     post.CourseId,
+		})
+	}
+
+	if err := h.lockPaidPosts(ctx, c, posts...); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
 			Message: err.Error(),
 		})
 		log.Println(err.Error())
 		return
 	}
-	body.Limit = limitInt
 
-	offset := (body.Page - 1) * body.Limit
+	c.JSON(http.StatusOK, models.ListPost{
+		Post:       posts,
+		TotalCount: int(listPost.TotalCount),
+		NextCursor: encodeCursor(listPost.NextCursor),
+		PrevCursor: encodeCursor(listPost.PrevCursor),
+	})
+}
+
+// @Security  		BearerAuth
+// @Summary   		Download Post
+// @Description 	Api for downloading a post's file through a short-lived presigned URL. Guests can download free posts
+// @Tags 			post
+// @Produce 		json
+// @Param 			id path string true "Post ID"
+// @Param 			format query string false "Set to pdf for the converted PDF version" Enums(pdf)
+// @Success 		302
+// @Failure 		401 {object} models.Error
+// @Failure 		403 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/post/{id}/download [GET]
+func (h *HandlerV1) DownloadPost(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	post, ok := h.accessiblePost(ctx, c)
+	if !ok {
+		return
+	}
+
+	objectName := post.Path
+	if c.Query("format") == "pdf" {
+		if post.PdfPath == "" {
+			c.JSON(http.StatusNotFound, models.Error{
+				Message: "PDF version of this post is not available",
+			})
+			return
+		}
+		objectName = post.PdfPath
+	}
 
-	filter := map[string]string{
-		"user_id": body.UserId,
+	download := h.newDownload(c, post, 0)
+	if h.redirectToObject(ctx, c, objectName) {
+		h.countDownload(ctx, download)
 	}
-	listPost, err := h.Service.Post().ListPost(ctx, &entity.ListReq{
-		Offset: offset,
-		Limit:  body.Limit,
-		Filter: filter,
+}
+
+// accessiblePost loads the post named in the path and checks that the caller
+// may read its file. On failure the error response is already written.
+func (h *HandlerV1) accessiblePost(ctx context.Context, c *gin.Context) (*entity.Post, bool) {
+	post, err := h.Service.Post().GetPost(ctx, &entity.GetReq{
+		Filter: map[string]string{
+			"id": c.Param("id"),
+		},
 	})
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.Error{
+		status := http.StatusInternalServerError
+		if errors.Is(err, entity.ErrorNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, models.Error{
 			Message: err.Error(),
 		})
 		log.Println(err.Error())
-		return
+		return nil, false
 	}
 
-	var posts []*models.Post
-	for _, post := range listPost.Post {
-		posts = append(posts, &models.Post{
-			Id:          post.Id,
-			UserId:      post.UserId,
-			Theme:       post.Theme,
-			Path:        post.Path,
-			Views:       post.Views,
-			CategoryId:  post.CategoryId,
-			Science:     post.Science,
-			Price:       post.Price,
-			PriceStatus: post.PriceStatus,
+	userId, _ := GetIdFromToken(c.Request, &h.Config)
+	role, _ := GetRoleFromToken(c.Request, &h.Config)
+	access, err := h.Service.Order().CanAccess(ctx, userId, role, post)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return nil, false
+	}
+	if !access[post.Id] {
+		c.JSON(http.StatusForbidden, models.Error{
+			Message: models.NoAccessMessage,
 		})
+		return nil, false
 	}
 
-	c.JSON(http.StatusOK, models.ListPost{
-		Post:       posts,
-		TotalCount: int(listPost.TotalCount),
-	})
+	return post, true
+}
+
+// redirectToObject sends the client to a short-lived presigned URL of an
+// object in the file bucket. On failure the error response is already
+// written.
+func (h *HandlerV1) redirectToObject(ctx context.Context, c *gin.Context, objectName string) bool {
+	params := url.Values{}
+	params.Set("response-content-disposition", fmt.Sprintf("attachment; filename=%q", objectName))
+	presignedURL, err := h.MinIO.PresignedGetObject(ctx, h.Config.Minio.FileUploadBucketName, objectName, h.Config.Minio.PresignedURLTTL, params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return false
+	}
+
+	c.Redirect(http.StatusFound, presignedURL.String())
+	return true
 }
@@ -0,0 +1,333 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+	"univer/api/models"
+	"univer/internal/entity"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// @Security      BearerAuth
+// @Summary       Update Post File
+// @Description   Api for uploading a new revision of a post's file. The previous file is kept as an older version.
+// @Tags          post
+// @Accept        multipart/form-data
+// @Produce       json
+// @Param         id path string true "Post ID"
+// @Param         file formData file true "File"
+// @Success       200 {object} models.PostFileResponse
+// @Failure       400 {object} models.Error
+// @Failure       401 {object} models.Error
+// @Failure       403 {object} models.Error
+// @Failure       404 {object} models.Error
+// @Failure       409 {object} models.Error
+// @Failure       500 {object} models.Error
+// @Router        /v1/post/{id}/file [PUT]
+func (h *HandlerV1) UpdatePostFile(c *gin.Context) {
+	var file models.File
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	post, ok := h.ownPost(ctx, c)
+	if !ok {
+		return
+	}
+
+	err := c.ShouldBind(&file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	stored, duplicate, ok := h.storePostFile(ctx, c, file.File, uuid.New().String())
+	if !ok {
+		return
+	}
+	if stored.Hash == post.FileHash {
+		h.releasePostFile(ctx, stored.Hash)
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: "File is the same as the current version",
+		})
+		return
+	}
+
+	post, err = h.Service.PostVersion().Revise(ctx, &entity.Post{
+		Id:       post.Id,
+		Path:     stored.Path,
+		FileHash: stored.Hash,
+	})
+	if err != nil {
+		h.releasePostFile(ctx, stored.Hash)
+		c.JSON(postVersionErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	h.startPostProcessing(ctx, post.Id, post.Version, stored.Path, duplicate)
+
+	response := postCreateResponse(post.Id, duplicate)
+	c.JSON(http.StatusOK, models.PostFileResponse{
+		Id:          post.Id,
+		Version:     post.Version,
+		DuplicateOf: response.DuplicateOf,
+		Warning:     response.Warning,
+	})
+}
+
+// @Security  		BearerAuth
+// @Summary   		List Post Versions
+// @Description 	Api for listing the previous versions of a post's file
+// @Tags 			post
+// @Produce 		json
+// @Param 			id path string true "Post ID"
+// @Success 		200 {object} models.ListPostVersion
+// @Failure 		401 {object} models.Error
+// @Failure 		403 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/post/{id}/versions [GET]
+func (h *HandlerV1) ListPostVersions(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	post, ok := h.accessiblePost(ctx, c)
+	if !ok {
+		return
+	}
+
+	versions, err := h.Service.PostVersion().ListVersions(ctx, post.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	response := models.ListPostVersion{
+		Current:  post.Version,
+		Versions: []*models.PostVersion{},
+	}
+	for _, version := range versions {
+		response.Versions = append(response.Versions, &models.PostVersion{
+			Version:     version.Version,
+			PdfPath:     version.PdfPath,
+			PreviewUrls: h.previewURLs(version.Previews),
+			CreatedAt:   version.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Security  		BearerAuth
+// @Summary   		Download Post Version
+// @Description 	Api for downloading a previous version of a post's file through a short-lived presigned URL
+// @Tags 			post
+// @Produce 		json
+// @Param 			id path string true "Post ID"
+// @Param 			version path int true "Version"
+// @Param 			format query string false "Set to pdf for the converted PDF version" Enums(pdf)
+// @Success 		302
+// @Failure 		400 {object} models.Error
+// @Failure 		401 {object} models.Error
+// @Failure 		403 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/post/{id}/versions/{version}/download [GET]
+func (h *HandlerV1) DownloadPostVersion(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	number, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	post, ok := h.accessiblePost(ctx, c)
+	if !ok {
+		return
+	}
+
+	version, err := h.Service.PostVersion().GetVersion(ctx, post.Id, number)
+	if err != nil {
+		c.JSON(postVersionErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	objectName := version.Path
+	if c.Query("format") == "pdf" {
+		if version.PdfPath == "" {
+			c.JSON(http.StatusNotFound, models.Error{
+				Message: "PDF version of this post is not available",
+			})
+			return
+		}
+		objectName = version.PdfPath
+	}
+
+	download := h.newDownload(c, post, version.Version)
+	if h.redirectToObject(ctx, c, objectName) {
+		h.countDownload(ctx, download)
+	}
+}
+
+// @Security  		BearerAuth
+// @Summary   		Rollback Post
+// @Description 	Api for restoring a previous version of a post's file. The restored file becomes a new version.
+// @Tags 			post
+// @Produce 		json
+// @Param 			id path string true "Post ID"
+// @Param 			version path int true "Version"
+// @Success 		200 {object} models.PostFileResponse
+// @Failure 		400 {object} models.Error
+// @Failure 		401 {object} models.Error
+// @Failure 		403 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		409 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/post/{id}/versions/{version}/rollback [POST]
+func (h *HandlerV1) RollbackPost(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	number, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	post, ok := h.ownPost(ctx, c)
+	if !ok {
+		return
+	}
+
+	version, err := h.Service.PostVersion().GetVersion(ctx, post.Id, number)
+	if err != nil {
+		c.JSON(postVersionErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	// the post takes its own reference next to the one the version keeps
+	if version.FileHash != "" {
+		_, err = h.Service.File().Acquire(ctx, &entity.File{
+			Hash: version.FileHash,
+			Path: version.Path,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.Error{
+				Message: err.Error(),
+			})
+			log.Println(err.Error())
+			return
+		}
+	}
+
+	post, err = h.Service.PostVersion().Rollback(ctx, post.Id, number)
+	if err != nil {
+		if version.FileHash != "" {
+			h.releasePostFile(ctx, version.FileHash)
+		}
+		c.JSON(postVersionErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	go h.reindexPost(post.Id, post.Version, post.Path, post.PdfPath)
+
+	c.JSON(http.StatusOK, models.PostFileResponse{
+		Id:      post.Id,
+		Version: post.Version,
+	})
+}
+
+// ownPost loads the post named in the path and checks that the caller is its
+// author or an admin. On failure the error response is already written.
+func (h *HandlerV1) ownPost(ctx context.Context, c *gin.Context) (*entity.Post, bool) {
+	return h.ownPostById(ctx, c, c.Param("id"))
+}
+
+// ownPostById is ownPost for a post named elsewhere in the request.
+func (h *HandlerV1) ownPostById(ctx context.Context, c *gin.Context, postId string) (*entity.Post, bool) {
+	userId, statusCode := GetIdFromToken(c.Request, &h.Config)
+	if statusCode != 0 {
+		c.JSON(http.StatusUnauthorized, models.Error{
+			Message: models.TokenInvalidMessage,
+		})
+		return nil, false
+	}
+	role, _ := GetRoleFromToken(c.Request, &h.Config)
+
+	post, err := h.Service.Post().GetPost(ctx, &entity.GetReq{
+		Filter: map[string]string{
+			"id": postId,
+		},
+	})
+	if err != nil {
+		c.JSON(postVersionErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return nil, false
+	}
+	if post.UserId != userId && role != "admin" {
+		c.JSON(http.StatusForbidden, models.Error{
+			Message: models.NoAccessMessage,
+		})
+		return nil, false
+	}
+
+	return post, true
+}
+
+// releasePostVersions drops the file references held by the versions of a
+// deleted post.
+func (h *HandlerV1) releasePostVersions(ctx context.Context, postId string) {
+	versions, err := h.Service.PostVersion().ListVersions(ctx, postId)
+	if err != nil {
+		log.Println("release versions", postId, err.Error())
+		return
+	}
+	for _, version := range versions {
+		if version.FileHash != "" {
+			h.releasePostFile(ctx, version.FileHash)
+		}
+	}
+}
+
+func postVersionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrorNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entity.ErrorConflict):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,55 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image/png"
+	"io"
+	"path/filepath"
+	"strings"
+	imagepkg "univer/internal/pkg/image"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// previewPost renders the first pages of a post's PDF to PNG thumbnails in the
+// preview bucket and records their object keys on the post. The keys follow the
+// post file, so every version of a post keeps its own thumbnails.
+func (h *HandlerV1) previewPost(ctx context.Context, postId string, version int, objectName string, pdf io.ReadSeeker) error {
+	if _, err := pdf.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	pages, err := h.Preview.Render(ctx, pdf, h.Config.Preview.Pages)
+	if err != nil {
+		return err
+	}
+
+	previews := make([]string, 0, len(pages))
+	for i, page := range pages {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, imagepkg.Thumbnail(page, h.Config.Preview.Width)); err != nil {
+			return err
+		}
+
+		previewName := fmt.Sprintf("%s/%d.png", strings.TrimSuffix(objectName, filepath.Ext(objectName)), i+1)
+		_, err = h.MinIO.PutObject(ctx, h.Config.Minio.PreviewBucketName, previewName, &buf, int64(buf.Len()), minio.PutObjectOptions{
+			ContentType: "image/png",
+		})
+		if err != nil {
+			return err
+		}
+		previews = append(previews, previewName)
+	}
+
+	return h.Service.Post().UpdatePreviews(ctx, postId, version, previews)
+}
+
+// previewURLs turns the preview object keys of a post into public URLs.
+func (h *HandlerV1) previewURLs(previews []string) []string {
+	urls := make([]string, 0, len(previews))
+	for _, objectName := range previews {
+		urls = append(urls, h.publicObjectURL(h.Config.Minio.PreviewBucketName, objectName))
+	}
+	return urls
+}
@@ -0,0 +1,208 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+	"univer/api/models"
+	"univer/internal/entity"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Security  		BearerAuth
+// @Summary   		Rate Post
+// @Description 	Api for rating a post with 1 to 5 stars and an optional review. Rating the post again replaces the earlier rating.
+// @Tags 			rating
+// @Accept 			json
+// @Produce 		json
+// @Param 			id path string true "Post ID"
+// @Param 			data body models.RatingReq true "Rating"
+// @Success 		200 {object} models.Rating
+// @Failure 		400 {object} models.Error
+// @Failure 		401 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/post/{id}/rating [POST]
+func (h *HandlerV1) RatePost(c *gin.Context) {
+	var body models.RatingReq
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	userId, statusCode := GetIdFromToken(c.Request, &h.Config)
+	if statusCode != 0 {
+		c.JSON(http.StatusUnauthorized, models.Error{
+			Message: models.TokenInvalidMessage,
+		})
+		return
+	}
+
+	err := c.ShouldBindJSON(&body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	rating, err := h.Service.Rating().Rate(ctx, &entity.Rating{
+		PostId: c.Param("id"),
+		UserId: userId,
+		Stars:  body.Stars,
+		Review: body.Review,
+	})
+	if err != nil {
+		c.JSON(ratingErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, ratingResponse(rating))
+}
+
+// @Security  		BearerAuth
+// @Summary   		Get Own Rating
+// @Description 	Api for getting the current user's rating of a post
+// @Tags 			rating
+// @Produce 		json
+// @Param 			id path string true "Post ID"
+// @Success 		200 {object} models.Rating
+// @Failure 		401 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/post/{id}/rating [GET]
+func (h *HandlerV1) GetPostRating(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	userId, statusCode := GetIdFromToken(c.Request, &h.Config)
+	if statusCode != 0 {
+		c.JSON(http.StatusUnauthorized, models.Error{
+			Message: models.TokenInvalidMessage,
+		})
+		return
+	}
+
+	rating, err := h.Service.Rating().GetRating(ctx, c.Param("id"), userId)
+	if err != nil {
+		c.JSON(ratingErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, ratingResponse(rating))
+}
+
+// @Security  		BearerAuth
+// @Summary   		Delete Rating
+// @Description 	Api for withdrawing the current user's rating of a post
+// @Tags 			rating
+// @Produce 		json
+// @Param 			id path string true "Post ID"
+// @Success 		200 {object} models.Response
+// @Failure 		401 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/post/{id}/rating [DELETE]
+func (h *HandlerV1) DeletePostRating(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	userId, statusCode := GetIdFromToken(c.Request, &h.Config)
+	if statusCode != 0 {
+		c.JSON(http.StatusUnauthorized, models.Error{
+			Message: models.TokenInvalidMessage,
+		})
+		return
+	}
+
+	err := h.Service.Rating().DeleteRating(ctx, c.Param("id"), userId)
+	if err != nil {
+		c.JSON(ratingErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Response: "Rating deleted",
+	})
+}
+
+// @Security  		BearerAuth
+// @Summary   		List Post Ratings
+// @Description 	Api for getting the ratings and reviews of a post, newest first. Page with page and limit, or leave page out and follow next_cursor and prev_cursor.
+// @Tags 			rating
+// @Produce 		json
+// @Param 			id path string true "Post ID"
+// @Param 			page query int false "Page"
+// @Param 			limit query int true "Limit"
+// @Param 			cursor query string false "Cursor from a previous response"
+// @Param 			count query bool false "Set to true for total_count when paging by cursor"
+// @Success 		200 {object} models.ListRating
+// @Failure 		400 {object} models.Error
+// @Failure 		401 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Router 			/v1/post/{id}/ratings [GET]
+func (h *HandlerV1) ListPostRatings(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	req, ok := listRequest(c, map[string]string{
+		"post_id": c.Param("id"),
+	})
+	if !ok {
+		return
+	}
+	listRating, err := h.Service.Rating().ListRating(ctx, req)
+	if err != nil {
+		c.JSON(listErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	response := models.ListRating{
+		Ratings:    []*models.Rating{},
+		TotalCount: listRating.TotalCount,
+		NextCursor: encodeCursor(listRating.NextCursor),
+		PrevCursor: encodeCursor(listRating.PrevCursor),
+	}
+	for _, rating := range listRating.Rating {
+		response.Ratings = append(response.Ratings, ratingResponse(rating))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func ratingResponse(rating *entity.Rating) *models.Rating {
+	return &models.Rating{
+		PostId:    rating.PostId,
+		UserId:    rating.UserId,
+		Stars:     rating.Stars,
+		Review:    rating.Review,
+		CreatedAt: rating.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: rating.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func ratingErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrorNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entity.ErrorOwnRating):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
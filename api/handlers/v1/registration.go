@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"fmt"
 	"image/png"
 	"log"
 	"net/http"
@@ -243,7 +242,7 @@ func (h *HandlerV1) Verify(c *gin.Context) {
 		return
 	}
 
-	minioURL := fmt.Sprintf("http://localhost:9000/%s/%s", h.Config.Minio.ImageUrlUploadBucketName, objectName)
+	minioURL := h.publicObjectURL(h.Config.Minio.ImageUrlUploadBucketName, objectName)
 
 	_, err = h.Service.User().CreateUser(ctx, &entity.User{
 		Id:           id,
@@ -0,0 +1,95 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"univer/api/models"
+	"univer/internal/entity"
+	"univer/internal/pkg/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultRelatedLimit = 10
+
+// @Security  		BearerAuth
+// @Summary   		Related Posts
+// @Description 	Api for the posts students who viewed a post also viewed, most similar first. Related posts are recomputed periodically; when there are too few the rest come from the same subject and category.
+// @Tags 			post
+// @Produce 		json
+// @Param 			id path string true "Post ID"
+// @Param 			limit query int false "Limit, 10 by default"
+// @Success 		200 {object} []models.RelatedPost
+// @Failure 		400 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/post/{id}/related [GET]
+func (h *HandlerV1) RelatedPosts(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	id := c.Param("id")
+	if !validation.ValidateUUID(id) {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: "invalid post id",
+		})
+		return
+	}
+
+	limit := defaultRelatedLimit
+	if value := c.Query("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 || limit > h.Config.Related.Limit {
+			c.JSON(http.StatusBadRequest, models.Error{
+				Message: "limit must be between 1 and " + strconv.Itoa(h.Config.Related.Limit),
+			})
+			return
+		}
+	}
+
+	related, err := h.Service.Related().Related(ctx, id, limit)
+	if err != nil {
+		c.JSON(relatedErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	posts := make([]*entity.Post, 0, len(related))
+	for _, post := range related {
+		posts = append(posts, post.Post)
+	}
+	postsResponse := h.postsResponse(posts)
+	if err := h.lockPaidPosts(ctx, c, postsResponse...); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	response := make([]*models.RelatedPost, 0, len(related))
+	for i, post := range related {
+		response = append(response, &models.RelatedPost{
+			Post:    postsResponse[i],
+			Source:  post.Source,
+			Score:   post.Score,
+			CoViews: post.CoViews,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func relatedErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrorNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -2,28 +2,45 @@ package v1
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"univer/api/models"
 	"univer/internal/entity"
+	"univer/internal/pkg/translit"
+	"univer/internal/pkg/validation"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
 )
 
+const suggestCachePrefix = "suggest:"
+
 // @Security  		BearerAuth
 // @Summary   		Search
-// @Description 	Api for searching by theme
+// @Description 	Api for full-text search over theme, science and category, ordered by relevance. Use "quotes" for phrases, word* for prefixes and -word to exclude a word. The response counts the results per science, category, free or paid and price range. Page with page and limit, or leave page out and follow next_cursor and prev_cursor.
 // @Tags 			search
 // @Accept 			json
 // @Produce 		json
-// @Param 			page query int true "Page"
+// @Param 			page query int false "Page"
 // @Param 			limit query int true "Limit"
-// @Param 			theme query string true "Theme"
-// @Param           science  query string false "Science"
+// @Param 			cursor query string false "Cursor from a previous response"
+// @Param 			count query bool false "Set to true for TotalCount when paging by cursor"
+// @Param 			sort query string false "Order of the results, relevance by default" Enums(relevance, newest, oldest, views, comments, price_asc, price_desc, rating)
+// @Param 			theme query string true "Search query"
+// @Param           science  query string false "Subject ID, or science in Latin or Cyrillic"
 // @Param           priceStatus  query bool false "Price Satatus"
-// @Param           category  query string false "Category" Enums(slayt, mustaqil ish,kurs ishi)
-// @Success 		200 {object} models.ListPost
+// @Param           category  query string false "Category id or name, in Latin or Cyrillic"
+// @Param           priceMin  query number false "Lowest price, inclusive"
+// @Param           priceMax  query number false "Highest price, exclusive"
+// @Param           tags  query string false "Comma separated tags; results have all of them"
+// @Param           university_id  query string false "University ID"
+// @Param           faculty_id  query string false "Faculty ID"
+// @Param           course_id  query string false "Course ID"
+// @Success 		200 {object} models.SearchResult
 // @Failure 		404 {object} models.Error
 // @Failure 		401 {object} models.Error
 // @Failure 		403 {object} models.Error
@@ -33,69 +50,221 @@ func (h *HandlerV1) Search(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
 	defer cancel()
 	theme := c.Query("theme")
-	page := c.Query("page")
-	limit := c.Query("limit")
 	science := c.Query("science")
 	category := c.Query("category")
 	priceStatus := c.Query("priceStatus")
-	pageInt, err := strconv.Atoi(page)
+	filter := map[string]string{
+		"theme": theme,
+	}
+	if validation.ValidateUUID(science) {
+		filter["subject_id"] = science
+	} else if science != "" {
+		filter["science"] = science
+	}
+	if validation.ValidateUUID(category) {
+		filter["category_id"] = category
+	} else if category != "" {
+		filter["category"] = category
+	}
+	if priceStatus != "" {
+		filter["price_status"] = priceStatus
+	}
+	if tags := tagSlugs(c.Query("tags")); tags != "" {
+		filter["tags"] = tags
+	}
+	if !placementFilter(c, filter) {
+		return
+	}
+	for param, key := range map[string]string{"priceMin": "price_min", "priceMax": "price_max"} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			c.JSON(http.StatusBadRequest, models.Error{
+				Message: param + " must be a number",
+			})
+			return
+		}
+		filter[key] = value
+	}
+	req, ok := listRequest(c, filter)
+	if !ok {
+		return
+	}
+	if req.Sort, ok = postSort(c, true); !ok {
+		return
+	}
+	listPost, err := h.Service.Post().Search(ctx, req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{
+		c.JSON(listErrorStatus(err), models.Error{
 			Message: err.Error(),
 		})
 		log.Println(err.Error())
 		return
 	}
-	limitInt, err := strconv.Atoi(limit)
+
+	var posts []*models.Post
+	for _, post := range listPost.Post {
+		posts = append(posts, &models.Post{
+			Id:           post.Id,
+			UserId:       post.UserId,
+			Theme:        post.Theme,
+			Path:         post.Path,
+			PdfPath:      post.PdfPath,
+			PreviewUrls:  h.previewURLs(post.Previews),
+			Pages:        post.Pages,
+			Views:        post.Views,
+			UserViews:    post.Views - post.GuestViews,
+			GuestViews:   post.GuestViews,
+			Downloads:    post.Downloads,
+			Comments:     post.Comments,
+			RatingAvg:    post.RatingAvg,
+			RatingCount:  post.RatingCount,
+			CategoryId:   post.CategoryId,
+			Science:      post.Science,
+			SubjectId:    post.SubjectId,
+			Tags:         post.Tags,
+			Price:        post.Price,
+			PriceStatus:  post.PriceStatus,
+			UniversityId: post.UniversityId,
+			FacultyId:    post.FacultyId,
+			CourseId:     post.CourseId,
+			Headline:     post.Headline,
+		})
+	}
+
+	if err := h.lockPaidPosts(ctx, c, posts...); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	facets, err := h.Service.Post().SearchFacets(ctx, &entity.ListReq{
+		Filter: filter,
+	})
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{
+		c.JSON(http.StatusInternalServerError, models.Error{
 			Message: err.Error(),
 		})
 		log.Println(err.Error())
 		return
 	}
 
-	offset := (pageInt - 1) * limitInt
-	filter := map[string]string{
-		"theme": theme,
+	c.JSON(http.StatusOK, models.SearchResult{
+		Post:       posts,
+		TotalCount: int(listPost.TotalCount),
+		NextCursor: encodeCursor(listPost.NextCursor),
+		PrevCursor: encodeCursor(listPost.PrevCursor),
+		Facets:     searchFacets(facets),
+	})
+}
+
+func searchFacets(facets *entity.SearchFacets) *models.SearchFacets {
+	response := models.SearchFacets{
+		Science:  []*models.FacetValue{},
+		Category: []*models.FacetValue{},
+		Free:     facets.Free,
+		Paid:     facets.Paid,
+		Price:    []*models.PriceRange{},
 	}
-	if science != "" {
-		filter["science"] = science
+	for _, science := range facets.Science {
+		response.Science = append(response.Science, &models.FacetValue{
+			Value: science.Value,
+			Name:  science.Name,
+			Count: science.Count,
+		})
 	}
-	if category != "" {
-		filter["category_id"] = category
+	for _, category := range facets.Category {
+		response.Category = append(response.Category, &models.FacetValue{
+			Value: category.Value,
+			Name:  category.Name,
+			Count: category.Count,
+		})
 	}
-	if priceStatus != "" {
-		filter["price_status"] = priceStatus
+	for _, price := range facets.Price {
+		response.Price = append(response.Price, &models.PriceRange{
+			Min:   price.Min,
+			Max:   price.Max,
+			Count: price.Count,
+		})
 	}
-	listPost, err := h.Service.Post().Search(ctx, &entity.ListReq{
-		Offset: offset,
-		Limit:  limitInt,
-		Filter: filter,
-	})
+
+	return &response
+}
+
+// @Security  		BearerAuth
+// @Summary   		Search Suggestions
+// @Description 	Api for completing a partly typed search query with themes, sciences and category names. Misspelled and Cyrillic queries are matched too.
+// @Tags 			search
+// @Produce 		json
+// @Param 			q query string true "Partly typed query"
+// @Param 			limit query int false "Limit"
+// @Success 		200 {object} models.ListSuggestion
+// @Failure 		400 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/search/suggest [GET]
+func (h *HandlerV1) SuggestSearch(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: "q is required",
+		})
+		return
+	}
+
+	limit := h.Config.Search.SuggestLimit
+	if c.Query("limit") != "" {
+		limitInt, err := strconv.Atoi(c.Query("limit"))
+		if err != nil || limitInt < 1 || limitInt > 50 {
+			c.JSON(http.StatusBadRequest, models.Error{
+				Message: "limit must be between 1 and 50",
+			})
+			return
+		}
+		limit = limitInt
+	}
+
+	// suggestions are cached by the normalized query, so Latin and Cyrillic
+	// spellings of the same prefix share an entry
+	key := suggestCachePrefix + translit.Normalize(query) + ":" + strconv.Itoa(limit)
+	if data, err := h.redisStorage.Get(ctx, key); err == nil {
+		var response models.ListSuggestion
+		if err := json.Unmarshal(data, &response); err == nil {
+			c.JSON(http.StatusOK, response)
+			return
+		}
+	} else if !errors.Is(err, redis.Nil) {
+		log.Println("suggest cache", err.Error())
+	}
+
+	suggestions, err := h.Service.Post().Suggest(ctx, query, limit)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.Error{
+		c.JSON(http.StatusInternalServerError, models.Error{
 			Message: err.Error(),
 		})
 		log.Println(err.Error())
 		return
 	}
 
-	var posts []*models.Post
-	for _, post := range listPost.Post {
-		posts = append(posts, &models.Post{
-			Id:         post.Id,
-			UserId:     post.UserId,
-			Theme:      post.Theme,
-			Path:       post.Path,
-			Views:      post.Views,
-			CategoryId: post.CategoryId,
-			Science:    post.Science,
+	response := models.ListSuggestion{
+		Suggestions: []*models.Suggestion{},
+	}
+	for _, suggestion := range suggestions {
+		response.Suggestions = append(response.Suggestions, &models.Suggestion{
+			Text: suggestion.Text,
+			Kind: suggestion.Kind,
 		})
 	}
 
-	c.JSON(http.StatusOK, models.ListPost{
-		Post:       posts,
-		TotalCount: int(listPost.TotalCount),
-	})
+	if err := h.redisStorage.Set(ctx, key, response, h.Config.Search.SuggestCacheTTL); err != nil {
+		log.Println("suggest cache", err.Error())
+	}
+
+	c.JSON(http.StatusOK, response)
 }
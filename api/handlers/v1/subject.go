@@ -0,0 +1,281 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"univer/api/models"
+	"univer/internal/entity"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Security 		BearerAuth
+// @Summary 		Create Subject
+// @Description 	This API for create a new subject posts can be filed under
+// @Tags 			subject
+// @Produce 		json
+// @Accept 			json
+// @Param 			subject body models.SubjectReq true "Create Subject Model"
+// @Success			201 {object} models.Subject
+// @Failure 		400 {object} models.Error
+// @Failure 		401 {object} models.Error
+// @Failure 		403 {object} models.Error
+// @Failure 		409 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/subject [POST]
+func (h *HandlerV1) CreateSubject(c *gin.Context) {
+	var body models.SubjectReq
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	if !h.adminOnly(c) {
+		return
+	}
+
+	err := c.ShouldBindJSON(&body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	subject, err := h.Service.Subject().CreateSubject(ctx, &entity.Subject{
+		Name: body.Name,
+	})
+	if err != nil {
+		c.JSON(subjectErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.Subject{
+		ID:   subject.Id,
+		Name: subject.Name,
+	})
+}
+
+// @Security 		BearerAuth
+// @Summary 		Update Subject
+// @Description 	This API for renaming a subject. Its posts show the new name.
+// @Tags 			subject
+// @Produce 		json
+// @Accept 			json
+// @Param 			subject body models.Subject true "Update Subject Model"
+// @Success			200 {object} models.Subject
+// @Failure 		400 {object} models.Error
+// @Failure 		401 {object} models.Error
+// @Failure 		403 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		409 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/subject [PUT]
+func (h *HandlerV1) UpdateSubject(c *gin.Context) {
+	var body models.Subject
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	if !h.adminOnly(c) {
+		return
+	}
+
+	err := c.ShouldBindJSON(&body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	subject, err := h.Service.Subject().UpdateSubject(ctx, &entity.Subject{
+		Id:   body.ID,
+		Name: body.Name,
+	})
+	if err != nil {
+		c.JSON(subjectErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Subject{
+		ID:   subject.Id,
+		Name: subject.Name,
+	})
+}
+
+// @Security 		BearerAuth
+// @Summary 		Delete Subject
+// @Description 	This API for delete a subject with id. Subjects that still have posts cannot be deleted.
+// @Tags 			subject
+// @Produce 		json
+// @Param 			id path string true "Subject ID"
+// @Success			200 {object} models.Response
+// @Failure 		401 {object} models.Error
+// @Failure 		403 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		409 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/subject/{id} [DELETE]
+func (h *HandlerV1) DeleteSubject(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	if !h.adminOnly(c) {
+		return
+	}
+
+	err := h.Service.Subject().DeleteSubject(ctx, &entity.DeleteReq{
+		Id: c.Param("id"),
+	})
+	if err != nil {
+		c.JSON(subjectErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Response: "Subject has been deleted successfully",
+	})
+}
+
+// @Security 		BearerAuth
+// @Summary 		Get Subject
+// @Description 	This API for getting a subject with id
+// @Tags 			subject
+// @Produce 		json
+// @Param 			id path string true "Subject ID"
+// @Success			200 {object} models.Subject
+// @Failure 		404 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/subject/{id} [GET]
+func (h *HandlerV1) GetSubject(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	subject, err := h.Service.Subject().GetSubject(ctx, &entity.GetReq{
+		Filter: map[string]string{
+			"id": c.Param("id"),
+		},
+	})
+	if err != nil {
+		c.JSON(subjectErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Subject{
+		ID:   subject.Id,
+		Name: subject.Name,
+	})
+}
+
+// @Security 		BearerAuth
+// @Summary 		List Subjects
+// @Description 	This API for getting the subjects a post's science can be. Page with page and limit, or leave page out and follow next_cursor and prev_cursor.
+// @Tags 			subject
+// @Produce 		json
+// @Param 			page query uint64 false "Page"
+// @Param 			limit query uint64 true "Limit"
+// @Param 			cursor query string false "Cursor from a previous response"
+// @Param 			count query bool false "Set to true for total_count when paging by cursor"
+// @Success			200 {object} models.ListSubject
+// @Failure 		400 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/subjects [GET]
+func (h *HandlerV1) ListSubjects(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	req, ok := listRequest(c, nil)
+	if !ok {
+		return
+	}
+	listSubjects, err := h.Service.Subject().ListSubject(ctx, req)
+	if err != nil {
+		c.JSON(listErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	subjects := []models.Subject{}
+	for _, subject := range listSubjects.Subject {
+		subjects = append(subjects, models.Subject{
+			ID:   subject.Id,
+			Name: subject.Name,
+		})
+	}
+
+	c.JSON(http.StatusOK, models.ListSubject{
+		Subjects:   subjects,
+		Total:      uint64(listSubjects.TotalCount),
+		NextCursor: encodeCursor(listSubjects.NextCursor),
+		PrevCursor: encodeCursor(listSubjects.PrevCursor),
+	})
+}
+
+// @Security 		BearerAuth
+// @Summary 		Subject Mapping Report
+// @Description 	This API for the report of how the free-text sciences of existing posts were mapped to subjects when subjects were introduced
+// @Tags 			subject
+// @Produce 		json
+// @Success			200 {object} []models.SubjectMapping
+// @Failure 		401 {object} models.Error
+// @Failure 		403 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/subjects/mapping [GET]
+func (h *HandlerV1) SubjectMapping(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	if !h.adminOnly(c) {
+		return
+	}
+
+	mappings, err := h.Service.Subject().SubjectMapping(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	response := []*models.SubjectMapping{}
+	for _, mapping := range mappings {
+		response = append(response, &models.SubjectMapping{
+			Science:     mapping.Science,
+			SubjectId:   mapping.SubjectId,
+			SubjectName: mapping.SubjectName,
+			Posts:       mapping.Posts,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func subjectErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrorNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entity.ErrorConflict), errors.Is(err, entity.ErrorSubjectInUse):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,433 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"univer/api/models"
+	"univer/internal/entity"
+	"univer/internal/pkg/tag"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Security  		BearerAuth
+// @Summary   		List Tags
+// @Description 	Api for browsing the tags that are on posts, the most used first. Page with page and limit, or leave page out and follow next_cursor and prev_cursor.
+// @Tags 			tag
+// @Produce 		json
+// @Param 			page query int false "Page"
+// @Param 			limit query int true "Limit"
+// @Param 			cursor query string false "Cursor from a previous response"
+// @Param 			count query bool false "Set to true for total_count when paging by cursor"
+// @Success 		200 {object} models.ListTag
+// @Failure 		400 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Router 			/v1/tags [GET]
+func (h *HandlerV1) ListTags(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	req, ok := listRequest(c, map[string]string{})
+	if !ok {
+		return
+	}
+	listTag, err := h.Service.Tag().ListTag(ctx, req)
+	if err != nil {
+		c.JSON(listErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	response := models.ListTag{
+		Tags:       tagsResponse(listTag.Tag),
+		TotalCount: listTag.TotalCount,
+		NextCursor: encodeCursor(listTag.NextCursor),
+		PrevCursor: encodeCursor(listTag.PrevCursor),
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Security  		BearerAuth
+// @Summary   		Tag Suggestions
+// @Description 	Api for completing a partly typed tag. Synonyms are matched too, and the tag they lead to is returned.
+// @Tags 			tag
+// @Produce 		json
+// @Param 			q query string true "Partly typed tag"
+// @Param 			limit query int false "Limit"
+// @Success 		200 {object} []models.Tag
+// @Failure 		400 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/tags/suggest [GET]
+func (h *HandlerV1) SuggestTags(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: "q is required",
+		})
+		return
+	}
+
+	limit := h.Config.Search.SuggestLimit
+	if c.Query("limit") != "" {
+		limitInt, err := strconv.Atoi(c.Query("limit"))
+		if err != nil || limitInt < 1 || limitInt > 50 {
+			c.JSON(http.StatusBadRequest, models.Error{
+				Message: "limit must be between 1 and 50",
+			})
+			return
+		}
+		limit = limitInt
+	}
+
+	tags, err := h.Service.Tag().SuggestTag(ctx, query, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, tagsResponse(tags))
+}
+
+// @Security  		BearerAuth
+// @Summary   		Get Tag
+// @Description 	Api for getting a tag with its synonyms. A synonym leads to the tag it belongs to.
+// @Tags 			tag
+// @Produce 		json
+// @Param 			slug path string true "Tag"
+// @Success 		200 {object} models.Tag
+// @Failure 		404 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/tags/{slug} [GET]
+func (h *HandlerV1) GetTag(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	tag, err := h.Service.Tag().GetTag(ctx, c.Param("slug"))
+	if err != nil {
+		c.JSON(tagErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, tagResponse(tag))
+}
+
+// @Security  		BearerAuth
+// @Summary   		List Tag Posts
+// @Description 	Api for getting the posts with a tag. Synonyms of the tag work too. Page with page and limit, or leave page out and follow next_cursor and prev_cursor.
+// @Tags 			tag
+// @Produce 		json
+// @Param 			slug path string true "Tag"
+// @Param 			page query int false "Page"
+// @Param 			limit query int true "Limit"
+// @Param 			cursor query string false "Cursor from a previous response"
+// @Param 			count query bool false "Set to true for TotalCount when paging by cursor"
+// @Param 			sort query string false "Order of the posts, newest by default" Enums(newest, oldest, views, comments, price_asc, price_desc, rating)
+// @Success 		200 {object} models.ListPost
+// @Failure 		400 {object} models.Error
+// @Failure 		401 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/tags/{slug}/posts [GET]
+func (h *HandlerV1) ListTagPosts(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	tag, err := h.Service.Tag().GetTag(ctx, c.Param("slug"))
+	if err != nil {
+		c.JSON(tagErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	req, ok := listRequest(c, map[string]string{
+		"tags": tag.Slug,
+	})
+	if !ok {
+		return
+	}
+	if req.Sort, ok = postSort(c, false); !ok {
+		return
+	}
+	listPost, err := h.Service.Post().ListPost(ctx, req)
+	if err != nil {
+		c.JSON(listErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	posts := h.postsResponse(listPost.Post)
+	if err := h.lockPaidPosts(ctx, c, posts...); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ListPost{
+		Post:       posts,
+		TotalCount: int(listPost.TotalCount),
+		NextCursor: encodeCursor(listPost.NextCursor),
+		PrevCursor: encodeCursor(listPost.PrevCursor),
+	})
+}
+
+// @Security  		BearerAuth
+// @Summary   		Rename Tag
+// @Description 	Api for renaming a tag. The old name becomes a synonym and the posts keep the tag. Admin only.
+// @Tags 			tag
+// @Accept 			json
+// @Produce 		json
+// @Param 			slug path string true "Tag"
+// @Param 			data body models.TagRenameReq true "New name"
+// @Success 		200 {object} models.Tag
+// @Failure 		400 {object} models.Error
+// @Failure 		401 {object} models.Error
+// @Failure 		403 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		409 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/tags/{slug} [PUT]
+func (h *HandlerV1) RenameTag(c *gin.Context) {
+	var body models.TagRenameReq
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	if !h.adminOnly(c) {
+		return
+	}
+
+	err := c.ShouldBindJSON(&body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	tag, err := h.Service.Tag().RenameTag(ctx, c.Param("slug"), body.Name)
+	if err != nil {
+		c.JSON(tagErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, tagResponse(tag))
+}
+
+// @Security  		BearerAuth
+// @Summary   		Merge Tags
+// @Description 	Api for merging a tag into another one. Its posts and synonyms move to the other tag and its name becomes a synonym. Admin only.
+// @Tags 			tag
+// @Accept 			json
+// @Produce 		json
+// @Param 			slug path string true "Tag to merge away"
+// @Param 			data body models.TagMergeReq true "Tag to keep"
+// @Success 		200 {object} models.Tag
+// @Failure 		400 {object} models.Error
+// @Failure 		401 {object} models.Error
+// @Failure 		403 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/tags/{slug}/merge [POST]
+func (h *HandlerV1) MergeTag(c *gin.Context) {
+	var body models.TagMergeReq
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	if !h.adminOnly(c) {
+		return
+	}
+
+	err := c.ShouldBindJSON(&body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	tag, err := h.Service.Tag().MergeTag(ctx, c.Param("slug"), body.Into)
+	if err != nil {
+		c.JSON(tagErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, tagResponse(tag))
+}
+
+// @Security  		BearerAuth
+// @Summary   		Add Tag Synonym
+// @Description 	Api for making another spelling lead to a tag. Posts tagged with the synonym get the tag. Admin only.
+// @Tags 			tag
+// @Accept 			json
+// @Produce 		json
+// @Param 			slug path string true "Tag"
+// @Param 			data body models.TagSynonymReq true "Synonym"
+// @Success 		200 {object} models.Tag
+// @Failure 		400 {object} models.Error
+// @Failure 		401 {object} models.Error
+// @Failure 		403 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		409 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/tags/{slug}/synonyms [POST]
+func (h *HandlerV1) AddTagSynonym(c *gin.Context) {
+	var body models.TagSynonymReq
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	if !h.adminOnly(c) {
+		return
+	}
+
+	err := c.ShouldBindJSON(&body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	tag, err := h.Service.Tag().AddSynonym(ctx, c.Param("slug"), body.Synonym)
+	if err != nil {
+		c.JSON(tagErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, tagResponse(tag))
+}
+
+// @Security  		BearerAuth
+// @Summary   		Delete Tag Synonym
+// @Description 	Api for removing a synonym of a tag. Admin only.
+// @Tags 			tag
+// @Produce 		json
+// @Param 			slug path string true "Tag"
+// @Param 			synonym path string true "Synonym"
+// @Success 		200 {object} models.Response
+// @Failure 		401 {object} models.Error
+// @Failure 		403 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/tags/{slug}/synonyms/{synonym} [DELETE]
+func (h *HandlerV1) DeleteTagSynonym(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	if !h.adminOnly(c) {
+		return
+	}
+
+	err := h.Service.Tag().DeleteSynonym(ctx, c.Param("slug"), c.Param("synonym"))
+	if err != nil {
+		c.JSON(tagErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Response: "Synonym deleted",
+	})
+}
+
+// adminOnly checks that the caller is an admin. Tags, subjects and
+// universities are shared by every post, so only admins curate them. On
+// failure the error response is already written.
+func (h *HandlerV1) adminOnly(c *gin.Context) bool {
+	role, statusCode := GetRoleFromToken(c.Request, &h.Config)
+	if statusCode != 0 {
+		c.JSON(http.StatusUnauthorized, models.Error{
+			Message: models.TokenInvalidMessage,
+		})
+		return false
+	}
+	if role != "admin" {
+		c.JSON(http.StatusForbidden, models.Error{
+			Message: models.NoAccessMessage,
+		})
+		return false
+	}
+
+	return true
+}
+
+// tagSlugs normalizes a comma separated list of tags, dropping empty ones.
+func tagSlugs(tags string) string {
+	var slugs []string
+	for _, name := range strings.Split(tags, ",") {
+		if slug := tag.Slug(name); slug != "" {
+			slugs = append(slugs, slug)
+		}
+	}
+
+	return strings.Join(slugs, ",")
+}
+
+func tagResponse(tag *entity.Tag) *models.Tag {
+	return &models.Tag{
+		Slug:     tag.Slug,
+		Name:     tag.Name,
+		Posts:    tag.Posts,
+		Synonyms: tag.Synonyms,
+	}
+}
+
+func tagsResponse(tags []*entity.Tag) []*models.Tag {
+	response := []*models.Tag{}
+	for _, tag := range tags {
+		response = append(response, tagResponse(tag))
+	}
+
+	return response
+}
+
+func tagErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrorNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entity.ErrorTagExists), errors.Is(err, entity.ErrorConflict):
+		return http.StatusConflict
+	case errors.Is(err, entity.ErrorInvalidTag), errors.Is(err, entity.ErrorTooManyTags), errors.Is(err, entity.ErrorMergeItself),
+		errors.Is(err, entity.ErrorUnknownSubject), errors.Is(err, entity.ErrorUnknownCategory), errors.Is(err, entity.ErrorPlacement):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
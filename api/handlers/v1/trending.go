@@ -0,0 +1,163 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+	"univer/api/models"
+	"univer/internal/entity"
+	"univer/internal/pkg/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	trendingCachePrefix  = "trending:"
+	defaultTrendingLimit = 20
+)
+
+// @Security  		BearerAuth
+// @Summary   		Trending Posts
+// @Description 	Api for the posts viewed and downloaded most in a recent window. A download counts as three views, and recent ones weigh more than older ones. Scores are recomputed periodically, see computed_at. Without a category or science the categories and sciences trending in the window come along.
+// @Tags 			post
+// @Produce 		json
+// @Param 			window query string false "Window, 7d by default" Enums(24h, 7d, 30d)
+// @Param 			category query string false "Category ID"
+// @Param 			science query string false "Subject ID, or science in Latin or Cyrillic"
+// @Param 			limit query int false "Limit, 20 by default"
+// @Success 		200 {object} models.Trending
+// @Failure 		400 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/posts/trending [GET]
+func (h *HandlerV1) TrendingPosts(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	req := &entity.TrendingReq{
+		Window: c.DefaultQuery("window", "7d"),
+		Scope:  entity.TrendingAll,
+		Limit:  defaultTrendingLimit,
+	}
+	if limit := c.Query("limit"); limit != "" {
+		var err error
+		if req.Limit, err = strconv.Atoi(limit); err != nil || req.Limit < 1 || req.Limit > h.Config.Trending.Limit {
+			c.JSON(http.StatusBadRequest, models.Error{
+				Message: "limit must be between 1 and " + strconv.Itoa(h.Config.Trending.Limit),
+			})
+			return
+		}
+	}
+
+	if category := c.Query("category"); category != "" {
+		if !validation.ValidateUUID(category) {
+			c.JSON(http.StatusBadRequest, models.Error{
+				Message: "invalid category id",
+			})
+			return
+		}
+		req.Scope, req.Value = entity.TrendingCategory, category
+	} else if science := c.Query("science"); science != "" {
+		filter := map[string]string{"name": science}
+		if validation.ValidateUUID(science) {
+			filter = map[string]string{"id": science}
+		}
+		subject, err := h.Service.Subject().GetSubject(ctx, &entity.GetReq{
+			Filter: filter,
+		})
+		if err != nil {
+			c.JSON(subjectErrorStatus(err), models.Error{
+				Message: err.Error(),
+			})
+			log.Println(err.Error())
+			return
+		}
+		req.Scope, req.Value = entity.TrendingScience, subject.Id
+	}
+
+	// the cached response is the same for everyone; paid posts are locked
+	// per caller after it is read
+	key := trendingCachePrefix + req.Window + ":" + req.Scope + ":" + req.Value + ":" + strconv.Itoa(req.Limit)
+	var response models.Trending
+	if data, err := h.redisStorage.Get(ctx, key); err == nil && json.Unmarshal(data, &response) == nil {
+		h.trendingResponse(ctx, c, &response)
+		return
+	} else if err != nil && !errors.Is(err, redis.Nil) {
+		log.Println("trending cache", err.Error())
+	}
+
+	trending, err := h.Service.Trending().Trending(ctx, req)
+	if err != nil {
+		c.JSON(trendingErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	response = models.Trending{
+		Window: trending.Window,
+		Posts:  []*models.TrendingPost{},
+	}
+	if !trending.ComputedAt.IsZero() {
+		response.ComputedAt = trending.ComputedAt.Format(time.RFC3339)
+	}
+	for _, post := range trending.Posts {
+		response.Posts = append(response.Posts, &models.TrendingPost{
+			Post:  h.postsResponse([]*entity.Post{post.Post})[0],
+			Score: post.Score,
+		})
+	}
+	response.Categories = trendingTopics(trending.Categories)
+	response.Sciences = trendingTopics(trending.Sciences)
+
+	if err := h.redisStorage.Set(ctx, key, response, h.Config.Trending.CacheTTL); err != nil {
+		log.Println("trending cache", err.Error())
+	}
+
+	h.trendingResponse(ctx, c, &response)
+}
+
+// trendingResponse locks the paid posts the caller has no access to and
+// writes the response.
+func (h *HandlerV1) trendingResponse(ctx context.Context, c *gin.Context, response *models.Trending) {
+	posts := make([]*models.Post, 0, len(response.Posts))
+	for _, post := range response.Posts {
+		posts = append(posts, post.Post)
+	}
+	if err := h.lockPaidPosts(ctx, c, posts...); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func trendingTopics(topics []*entity.TrendingTopic) []*models.TrendingTopic {
+	var response []*models.TrendingTopic
+	for _, topic := range topics {
+		response = append(response, &models.TrendingTopic{
+			Id:    topic.Value,
+			Name:  topic.Name,
+			Score: topic.Score,
+		})
+	}
+	return response
+}
+
+func trendingErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrorInvalidWindow):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,747 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"univer/api/models"
+	"univer/internal/entity"
+	"univer/internal/pkg/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Security 		BearerAuth
+// @Summary 		Create University
+// @Description 	This API for create a new university
+// @Tags 			university
+// @Produce 		json
+// @Accept 			json
+// @Param 			university body models.UniversityReq true "Create University Model"
+// @Success			201 {object} models.University
+// @Failure 		400 {object} models.Error
+// @Failure 		401 {object} models.Error
+// @Failure 		403 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		409 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/university [POST]
+func (h *HandlerV1) CreateUniversity(c *gin.Context) {
+	var body models.UniversityReq
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	if !h.adminOnly(c) {
+		return
+	}
+
+	err := c.ShouldBindJSON(&body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	university, err := h.Service.University().CreateUniversity(ctx, &entity.University{
+		Name: body.Name,
+	})
+	if err != nil {
+		c.JSON(universityErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.University{
+		ID:   university.Id,
+		Name: university.Name,
+	})
+}
+
+// @Security 		BearerAuth
+// @Summary 		Update University
+// @Description 	This API for renaming a university
+// @Tags 			university
+// @Produce 		json
+// @Accept 			json
+// @Param 			university body models.University true "Update University Model"
+// @Success			200 {object} models.University
+// @Failure 		400 {object} models.Error
+// @Failure 		401 {object} models.Error
+// @Failure 		403 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		409 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/university [PUT]
+func (h *HandlerV1) UpdateUniversity(c *gin.Context) {
+	var body models.University
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	if !h.adminOnly(c) {
+		return
+	}
+
+	err := c.ShouldBindJSON(&body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	university, err := h.Service.University().UpdateUniversity(ctx, &entity.University{
+		Id:   body.ID,
+		Name: body.Name,
+	})
+	if err != nil {
+		c.JSON(universityErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, models.University{
+		ID:   university.Id,
+		Name: university.Name,
+	})
+}
+
+// @Security 		BearerAuth
+// @Summary 		Delete University
+// @Description 	This API for delete a university with id. Universities that still have faculties, posts or users cannot be deleted.
+// @Tags 			university
+// @Produce 		json
+// @Param 			id path string true "University ID"
+// @Success			200 {object} models.Response
+// @Failure 		401 {object} models.Error
+// @Failure 		403 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		409 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/university/{id} [DELETE]
+func (h *HandlerV1) DeleteUniversity(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	if !h.adminOnly(c) {
+		return
+	}
+
+	err := h.Service.University().DeleteUniversity(ctx, &entity.DeleteReq{
+		Id: c.Param("id"),
+	})
+	if err != nil {
+		c.JSON(universityErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Response: "University has been deleted successfully",
+	})
+}
+
+// @Security 		BearerAuth
+// @Summary 		Get University
+// @Description 	This API for getting a university with id
+// @Tags 			university
+// @Produce 		json
+// @Param 			id path string true "University ID"
+// @Success			200 {object} models.University
+// @Failure 		404 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/university/{id} [GET]
+func (h *HandlerV1) GetUniversity(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	university, err := h.Service.University().GetUniversity(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(universityErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, models.University{
+		ID:   university.Id,
+		Name: university.Name,
+	})
+}
+
+// @Security 		BearerAuth
+// @Summary 		List Universities
+// @Description 	This API for getting universities. Page with page and limit, or leave page out and follow next_cursor and prev_cursor.
+// @Tags 			university
+// @Produce 		json
+// @Param 			page query uint64 false "Page"
+// @Param 			limit query uint64 true "Limit"
+// @Param 			cursor query string false "Cursor from a previous response"
+// @Param 			count query bool false "Set to true for total_count when paging by cursor"
+// @Success			200 {object} models.ListUniversity
+// @Failure 		400 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/universities [GET]
+func (h *HandlerV1) ListUniversities(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	req, ok := listRequest(c, nil)
+	if !ok {
+		return
+	}
+	list, err := h.Service.University().ListUniversity(ctx, req)
+	if err != nil {
+		c.JSON(listErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	universities := []models.University{}
+	for _, university := range list.University {
+		universities = append(universities, models.University{
+			ID:   university.Id,
+			Name: university.Name,
+		})
+	}
+
+	c.JSON(http.StatusOK, models.ListUniversity{
+		Universities: universities,
+		Total:        uint64(list.TotalCount),
+		NextCursor:   encodeCursor(list.NextCursor),
+		PrevCursor:   encodeCursor(list.PrevCursor),
+	})
+}
+
+// @Security 		BearerAuth
+// @Summary 		Create Faculty
+// @Description 	This API for create a new faculty of a university
+// @Tags 			university
+// @Produce 		json
+// @Accept 			json
+// @Param 			faculty body models.FacultyReq true "Create Faculty Model"
+// @Success			201 {object} models.Faculty
+// @Failure 		400 {object} models.Error
+// @Failure 		401 {object} models.Error
+// @Failure 		403 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		409 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/faculty [POST]
+func (h *HandlerV1) CreateFaculty(c *gin.Context) {
+	var body models.FacultyReq
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	if !h.adminOnly(c) {
+		return
+	}
+
+	err := c.ShouldBindJSON(&body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	faculty, err := h.Service.University().CreateFaculty(ctx, &entity.Faculty{
+		UniversityId: body.UniversityId,
+		Name:         body.Name,
+	})
+	if err != nil {
+		c.JSON(universityErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.Faculty{
+		ID:           faculty.Id,
+		UniversityId: faculty.UniversityId,
+		Name:         faculty.Name,
+	})
+}
+
+// @Security 		BearerAuth
+// @Summary 		Update Faculty
+// @Description 	This API for renaming a faculty
+// @Tags 			university
+// @Produce 		json
+// @Accept 			json
+// @Param 			faculty body models.Faculty true "Update Faculty Model"
+// @Success			200 {object} models.Faculty
+// @Failure 		400 {object} models.Error
+// @Failure 		401 {object} models.Error
+// @Failure 		403 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		409 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/faculty [PUT]
+func (h *HandlerV1) UpdateFaculty(c *gin.Context) {
+	var body models.Faculty
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	if !h.adminOnly(c) {
+		return
+	}
+
+	err := c.ShouldBindJSON(&body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	faculty, err := h.Service.University().UpdateFaculty(ctx, &entity.Faculty{
+		Id:   body.ID,
+		Name: body.Name,
+	})
+	if err != nil {
+		c.JSON(universityErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Faculty{
+		ID:           faculty.Id,
+		UniversityId: faculty.UniversityId,
+		Name:         faculty.Name,
+	})
+}
+
+// @Security 		BearerAuth
+// @Summary 		Delete Faculty
+// @Description 	This API for delete a faculty with id. Faculties that still have courses or posts cannot be deleted.
+// @Tags 			university
+// @Produce 		json
+// @Param 			id path string true "Faculty ID"
+// @Success			200 {object} models.Response
+// @Failure 		401 {object} models.Error
+// @Failure 		403 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		409 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/faculty/{id} [DELETE]
+func (h *HandlerV1) DeleteFaculty(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	if !h.adminOnly(c) {
+		return
+	}
+
+	err := h.Service.University().DeleteFaculty(ctx, &entity.DeleteReq{
+		Id: c.Param("id"),
+	})
+	if err != nil {
+		c.JSON(universityErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Response: "Faculty has been deleted successfully",
+	})
+}
+
+// @Security 		BearerAuth
+// @Summary 		Get Faculty
+// @Description 	This API for getting a faculty with id
+// @Tags 			university
+// @Produce 		json
+// @Param 			id path string true "Faculty ID"
+// @Success			200 {object} models.Faculty
+// @Failure 		404 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/faculty/{id} [GET]
+func (h *HandlerV1) GetFaculty(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	faculty, err := h.Service.University().GetFaculty(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(universityErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Faculty{
+		ID:           faculty.Id,
+		UniversityId: faculty.UniversityId,
+		Name:         faculty.Name,
+	})
+}
+
+// @Security 		BearerAuth
+// @Summary 		List Faculties
+// @Description 	This API for getting the faculties of a university. Page with page and limit, or leave page out and follow next_cursor and prev_cursor.
+// @Tags 			university
+// @Produce 		json
+// @Param 			id path string true "University ID"
+// @Param 			page query uint64 false "Page"
+// @Param 			limit query uint64 true "Limit"
+// @Param 			cursor query string false "Cursor from a previous response"
+// @Param 			count query bool false "Set to true for total_count when paging by cursor"
+// @Success			200 {object} models.ListFaculty
+// @Failure 		400 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/university/{id}/faculties [GET]
+func (h *HandlerV1) ListUniversityFaculties(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	req, ok := listRequest(c, nil)
+	if !ok {
+		return
+	}
+	list, err := h.Service.University().ListFaculty(ctx, c.Param("id"), req)
+	if err != nil {
+		c.JSON(listErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	faculties := []models.Faculty{}
+	for _, faculty := range list.Faculty {
+		faculties = append(faculties, models.Faculty{
+			ID:           faculty.Id,
+			UniversityId: faculty.UniversityId,
+			Name:         faculty.Name,
+		})
+	}
+
+	c.JSON(http.StatusOK, models.ListFaculty{
+		Faculties:  faculties,
+		Total:      uint64(list.TotalCount),
+		NextCursor: encodeCursor(list.NextCursor),
+		PrevCursor: encodeCursor(list.PrevCursor),
+	})
+}
+
+// @Security 		BearerAuth
+// @Summary 		Create Course
+// @Description 	This API for create a new course of a faculty, such as Economics in the 2nd year
+// @Tags 			university
+// @Produce 		json
+// @Accept 			json
+// @Param 			course body models.CourseReq true "Create Course Model"
+// @Success			201 {object} models.Course
+// @Failure 		400 {object} models.Error
+// @Failure 		401 {object} models.Error
+// @Failure 		403 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		409 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/course [POST]
+func (h *HandlerV1) CreateCourse(c *gin.Context) {
+	var body models.CourseReq
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	if !h.adminOnly(c) {
+		return
+	}
+
+	err := c.ShouldBindJSON(&body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	course, err := h.Service.University().CreateCourse(ctx, &entity.Course{
+		FacultyId: body.FacultyId,
+		Name:      body.Name,
+		Year:      body.Year,
+	})
+	if err != nil {
+		c.JSON(universityErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.Course{
+		ID:           course.Id,
+		FacultyId:    course.FacultyId,
+		UniversityId: course.UniversityId,
+		Name:         course.Name,
+		Year:         course.Year,
+	})
+}
+
+// @Security 		BearerAuth
+// @Summary 		Update Course
+// @Description 	This API for renaming a course or changing its year
+// @Tags 			university
+// @Produce 		json
+// @Accept 			json
+// @Param 			course body models.Course true "Update Course Model"
+// @Success			200 {object} models.Course
+// @Failure 		400 {object} models.Error
+// @Failure 		401 {object} models.Error
+// @Failure 		403 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		409 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/course [PUT]
+func (h *HandlerV1) UpdateCourse(c *gin.Context) {
+	var body models.Course
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	if !h.adminOnly(c) {
+		return
+	}
+
+	err := c.ShouldBindJSON(&body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	course, err := h.Service.University().UpdateCourse(ctx, &entity.Course{
+		Id:   body.ID,
+		Name: body.Name,
+		Year: body.Year,
+	})
+	if err != nil {
+		c.JSON(universityErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Course{
+		ID:           course.Id,
+		FacultyId:    course.FacultyId,
+		UniversityId: course.UniversityId,
+		Name:         course.Name,
+		Year:         course.Year,
+	})
+}
+
+// @Security 		BearerAuth
+// @Summary 		Delete Course
+// @Description 	This API for delete a course with id. Courses that still have posts cannot be deleted.
+// @Tags 			university
+// @Produce 		json
+// @Param 			id path string true "Course ID"
+// @Success			200 {object} models.Response
+// @Failure 		401 {object} models.Error
+// @Failure 		403 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		409 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/course/{id} [DELETE]
+func (h *HandlerV1) DeleteCourse(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	if !h.adminOnly(c) {
+		return
+	}
+
+	err := h.Service.University().DeleteCourse(ctx, &entity.DeleteReq{
+		Id: c.Param("id"),
+	})
+	if err != nil {
+		c.JSON(universityErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Response: "Course has been deleted successfully",
+	})
+}
+
+// @Security 		BearerAuth
+// @Summary 		Get Course
+// @Description 	This API for getting a course with id
+// @Tags 			university
+// @Produce 		json
+// @Param 			id path string true "Course ID"
+// @Success			200 {object} models.Course
+// @Failure 		404 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/course/{id} [GET]
+func (h *HandlerV1) GetCourse(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	course, err := h.Service.University().GetCourse(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(universityErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Course{
+		ID:           course.Id,
+		FacultyId:    course.FacultyId,
+		UniversityId: course.UniversityId,
+		Name:         course.Name,
+		Year:         course.Year,
+	})
+}
+
+// @Security 		BearerAuth
+// @Summary 		List Courses
+// @Description 	This API for getting the courses of a faculty. Page with page and limit, or leave page out and follow next_cursor and prev_cursor.
+// @Tags 			university
+// @Produce 		json
+// @Param 			id path string true "Faculty ID"
+// @Param 			page query uint64 false "Page"
+// @Param 			limit query uint64 true "Limit"
+// @Param 			cursor query string false "Cursor from a previous response"
+// @Param 			count query bool false "Set to true for total_count when paging by cursor"
+// @Success			200 {object} models.ListCourse
+// @Failure 		400 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/faculty/{id}/courses [GET]
+func (h *HandlerV1) ListFacultyCourses(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	req, ok := listRequest(c, nil)
+	if !ok {
+		return
+	}
+	list, err := h.Service.University().ListCourse(ctx, c.Param("id"), req)
+	if err != nil {
+		c.JSON(listErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	courses := []models.Course{}
+	for _, course := range list.Course {
+		courses = append(courses, models.Course{
+			ID:           course.Id,
+			FacultyId:    course.FacultyId,
+			UniversityId: course.UniversityId,
+			Name:         course.Name,
+			Year:         course.Year,
+		})
+	}
+
+	c.JSON(http.StatusOK, models.ListCourse{
+		Courses:    courses,
+		Total:      uint64(list.TotalCount),
+		NextCursor: encodeCursor(list.NextCursor),
+		PrevCursor: encodeCursor(list.PrevCursor),
+	})
+}
+
+// placementFilter adds the university_id, faculty_id and course_id a post
+// list asks for to filter, under the same names CreatePost takes them. On
+// failure the error response is already written.
+func placementFilter(c *gin.Context, filter map[string]string) bool {
+	for _, key := range []string{"university_id", "faculty_id", "course_id"} {
+		value := c.Query(key)
+		if value == "" || (key == "university_id" && value == "all") {
+			continue
+		}
+		if !validation.ValidateUUID(value) {
+			c.JSON(http.StatusBadRequest, models.Error{
+				Message: "invalid " + key,
+			})
+			return false
+		}
+		filter[key] = value
+	}
+
+	return true
+}
+
+// scopeToUniversity narrows a post list that names no university, faculty or
+// course to the caller's own university, if they set one, and the posts that
+// belong to no university. university_id=all lists the posts of every
+// university.
+func (h *HandlerV1) scopeToUniversity(ctx context.Context, c *gin.Context, filter map[string]string) {
+	if c.Query("university_id") == "all" {
+		return
+	}
+	for _, key := range []string{"university_id", "faculty_id", "course_id"} {
+		if _, ok := filter[key]; ok {
+			return
+		}
+	}
+
+	userId, statusCode := GetIdFromToken(c.Request, &h.Config)
+	if statusCode != 0 {
+		return
+	}
+	user, err := h.Service.User().GetUser(ctx, &entity.GetReq{
+		Filter: map[string]string{
+			"id": userId,
+		},
+	})
+	if err != nil {
+		log.Println("feed university", userId, err.Error())
+		return
+	}
+	if user.UniversityId != "" {
+		filter["scope_university_id"] = user.UniversityId
+	}
+}
+
+func universityErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrorNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entity.ErrorConflict), errors.Is(err, entity.ErrorUniversityInUse),
+		errors.Is(err, entity.ErrorFacultyInUse), errors.Is(err, entity.ErrorCourseInUse):
+		return http.StatusConflict
+	case errors.Is(err, entity.ErrorPlacement):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
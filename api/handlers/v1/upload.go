@@ -0,0 +1,502 @@
+package v1
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"univer/api/models"
+	"univer/internal/entity"
+	"univer/internal/pkg/filetype"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+)
+
+const uploadSessionPrefix = "upload:"
+
+// @Security  		BearerAuth
+// @Summary   		Init Upload
+// @Description 	Api for starting a resumable upload of a large post file. The file is then sent in chunk_size pieces with PATCH /v1/upload/{id}.
+// @Tags 			upload
+// @Accept 			json
+// @Produce 		json
+// @Param 			upload body models.UploadInit true "Upload Model"
+// @Success 		201 {object} models.UploadStatus
+// @Failure 		400 {object} models.Error
+// @Failure 		401 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/upload [POST]
+func (h *HandlerV1) InitUpload(c *gin.Context) {
+	var body models.UploadInit
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	userId, statusCode := GetIdFromToken(c.Request, &h.Config)
+	if statusCode != 0 {
+		c.JSON(http.StatusUnauthorized, models.Error{
+			Message: models.TokenInvalidMessage,
+		})
+		return
+	}
+
+	err := c.ShouldBindJSON(&body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	ext := strings.ToLower(filepath.Ext(body.FileName))
+	if !postFileExtensions[ext] {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: "Only .pdf, .doc, .docx, .ppt, .pptx, .xls, .xlsx, .xlsm and .zip format files are accepted",
+		})
+		return
+	}
+	if body.Size <= 0 || body.Size > h.Config.Upload.MaxSize {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: fmt.Sprintf("File size must be between 1 byte and %d MB", h.Config.Upload.MaxSize>>20),
+		})
+		return
+	}
+
+	// the post is checked now so a bad tag, subject, category or placement
+	// does not surface only after the whole file is uploaded
+	err = h.Service.Post().CheckPost(ctx, &entity.Post{
+		Science:    body.Science,
+		CategoryId: body.CategoryId,
+		Tags:       body.Tags,
+
+		UniversityId: body.UniversityId,
+		FacultyId:    body.FacultyId,
+		CourseId:     body.CourseId,
+	})
+	if err != nil {
+		c.JSON(tagErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	id := uuid.New().String()
+	objectName := id + ext
+
+	core := minio.Core{Client: h.MinIO}
+	uploadId, err := core.NewMultipartUpload(ctx, h.Config.Minio.FileUploadBucketName, objectName, minio.PutObjectOptions{
+		ContentType: filetype.ContentType(ext),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	hashState, err := sha256.New().(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	session := &models.UploadSession{
+		Id:         id,
+		UserId:     userId,
+		FileName:   body.FileName,
+		ObjectName: objectName,
+		UploadId:   uploadId,
+		Size:       body.Size,
+		ChunkSize:  h.Config.Upload.ChunkSize,
+		HashState:  hashState,
+		Post:       body,
+		ExpiresAt:  time.Now().Add(h.Config.Upload.SessionTTL),
+	}
+	if err := h.saveUploadSession(ctx, session); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, uploadStatus(session))
+}
+
+// @Security  		BearerAuth
+// @Summary   		Upload Chunk
+// @Description 	Api for sending the next chunk of a resumable upload. Upload-Offset must match the offset returned by the previous call, and every chunk but the last must be exactly chunk_size bytes.
+// @Tags 			upload
+// @Accept 			application/octet-stream
+// @Produce 		json
+// @Param 			id path string true "Upload ID"
+// @Param 			Upload-Offset header int true "Offset of the chunk in the file"
+// @Success 		200 {object} models.UploadStatus
+// @Failure 		400 {object} models.Error
+// @Failure 		401 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		409 {object} models.UploadStatus
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/upload/{id} [PATCH]
+func (h *HandlerV1) UploadChunk(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	session, ok := h.uploadSession(ctx, c)
+	if !ok {
+		return
+	}
+
+	offset, err := strconv.ParseInt(c.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: "Upload-Offset header is required",
+		})
+		return
+	}
+	if offset != session.Offset {
+		c.JSON(http.StatusConflict, uploadStatus(session))
+		return
+	}
+
+	length := min(session.ChunkSize, session.Size-session.Offset)
+	if c.Request.ContentLength != length {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: fmt.Sprintf("Chunk at offset %d must be exactly %d bytes", offset, length),
+		})
+		return
+	}
+
+	// the hash state is only saved along with the offset, so a retried chunk
+	// is hashed on top of the same state as the failed attempt
+	hash := sha256.New()
+	if err := hash.(encoding.BinaryUnmarshaler).UnmarshalBinary(session.HashState); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	// parts are numbered by their offset, so a retried chunk overwrites the
+	// part it was meant for
+	core := minio.Core{Client: h.MinIO}
+	partNumber := int(offset/session.ChunkSize) + 1
+	chunk := io.TeeReader(http.MaxBytesReader(c.Writer, c.Request.Body, length), hash)
+	part, err := core.PutObjectPart(ctx, h.Config.Minio.FileUploadBucketName, session.ObjectName, session.UploadId, partNumber,
+		chunk, length, minio.PutObjectPartOptions{})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	session.Parts = append(session.Parts, models.UploadPart{
+		Number: part.PartNumber,
+		ETag:   part.ETag,
+	})
+	session.Offset += length
+	session.HashState, err = hash.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+	if err := h.saveUploadSession(ctx, session); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.Header("Upload-Offset", strconv.FormatInt(session.Offset, 10))
+	c.JSON(http.StatusOK, uploadStatus(session))
+}
+
+// @Security  		BearerAuth
+// @Summary   		Upload Status
+// @Description 	Api for getting the offset to resume an upload from
+// @Tags 			upload
+// @Produce 		json
+// @Param 			id path string true "Upload ID"
+// @Success 		200 {object} models.UploadStatus
+// @Failure 		401 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/upload/{id} [GET]
+func (h *HandlerV1) GetUploadStatus(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	session, ok := h.uploadSession(ctx, c)
+	if !ok {
+		return
+	}
+
+	c.Header("Upload-Offset", strconv.FormatInt(session.Offset, 10))
+	c.JSON(http.StatusOK, uploadStatus(session))
+}
+
+// @Security  		BearerAuth
+// @Summary   		Complete Upload
+// @Description 	Api for finishing a resumable upload and creating the post
+// @Tags 			upload
+// @Produce 		json
+// @Param 			id path string true "Upload ID"
+// @Success 		201 {object} models.PostCreateResponse
+// @Failure 		400 {object} models.Error
+// @Failure 		401 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		409 {object} models.UploadStatus
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/upload/{id}/complete [POST]
+func (h *HandlerV1) CompleteUpload(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	session, ok := h.uploadSession(ctx, c)
+	if !ok {
+		return
+	}
+	if session.Offset != session.Size {
+		c.JSON(http.StatusConflict, uploadStatus(session))
+		return
+	}
+
+	parts := make([]minio.CompletePart, 0, len(session.Parts))
+	for _, part := range session.Parts {
+		parts = append(parts, minio.CompletePart{
+			PartNumber: part.Number,
+			ETag:       part.ETag,
+		})
+	}
+
+	ext := filepath.Ext(session.ObjectName)
+	core := minio.Core{Client: h.MinIO}
+	_, err := core.CompleteMultipartUpload(ctx, h.Config.Minio.FileUploadBucketName, session.ObjectName, session.UploadId, parts, minio.PutObjectOptions{
+		ContentType: filetype.ContentType(ext),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	contentType, err := h.validateObject(ctx, session.ObjectName, session.Size)
+	if err != nil {
+		if removeErr := h.MinIO.RemoveObject(ctx, h.Config.Minio.FileUploadBucketName, session.ObjectName, minio.RemoveObjectOptions{}); removeErr != nil {
+			log.Println(removeErr.Error())
+		}
+		if delErr := h.redisStorage.Del(ctx, uploadSessionPrefix+session.Id); delErr != nil {
+			log.Println(delErr.Error())
+		}
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	hash := sha256.New()
+	if err := hash.(encoding.BinaryUnmarshaler).UnmarshalBinary(session.HashState); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+	fileHash := hex.EncodeToString(hash.Sum(nil))
+
+	stored, duplicate, err := h.acquirePostFile(ctx, &entity.File{
+		Hash:        fileHash,
+		Path:        session.ObjectName,
+		Size:        session.Size,
+		ContentType: contentType,
+	}, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	role, _ := GetRoleFromToken(c.Request, &h.Config)
+	post := &entity.Post{
+		Id:         session.Id,
+		UserId:     session.UserId,
+		Theme:      session.Post.Theme,
+		Path:       stored.Path,
+		FileHash:   fileHash,
+		Science:    session.Post.Science,
+		CategoryId: session.Post.CategoryId,
+		Tags:       session.Post.Tags,
+
+		UniversityId: session.Post.UniversityId,
+		FacultyId:    session.Post.FacultyId,
+		CourseId:     session.Post.CourseId,
+	}
+	if session.Post.Price > 0 && role == "prouser" {
+		post.PriceStatus = true
+		post.Price = session.Post.Price
+	}
+
+	post, err = h.Service.Post().CreatePost(ctx, post)
+	if err != nil {
+		h.releasePostFile(ctx, fileHash)
+		if delErr := h.redisStorage.Del(ctx, uploadSessionPrefix+session.Id); delErr != nil {
+			log.Println(delErr.Error())
+		}
+		c.JSON(tagErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	if err := h.redisStorage.Del(ctx, uploadSessionPrefix+session.Id); err != nil {
+		log.Println(err.Error())
+	}
+
+	h.startPostProcessing(ctx, post.Id, post.Version, stored.Path, duplicate)
+
+	c.JSON(http.StatusCreated, postCreateResponse(post.Id, duplicate))
+}
+
+// @Security  		BearerAuth
+// @Summary   		Cancel Upload
+// @Description 	Api for aborting a resumable upload and dropping its chunks
+// @Tags 			upload
+// @Produce 		json
+// @Param 			id path string true "Upload ID"
+// @Success 		200 {object} bool
+// @Failure 		401 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/upload/{id} [DELETE]
+func (h *HandlerV1) CancelUpload(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	session, ok := h.uploadSession(ctx, c)
+	if !ok {
+		return
+	}
+
+	core := minio.Core{Client: h.MinIO}
+	err := core.AbortMultipartUpload(ctx, h.Config.Minio.FileUploadBucketName, session.ObjectName, session.UploadId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	if err := h.redisStorage.Del(ctx, uploadSessionPrefix+session.Id); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, true)
+}
+
+// uploadSession loads the upload named in the path and checks that it belongs
+// to the caller. On failure the error response is already written.
+func (h *HandlerV1) uploadSession(ctx context.Context, c *gin.Context) (*models.UploadSession, bool) {
+	userId, statusCode := GetIdFromToken(c.Request, &h.Config)
+	if statusCode != 0 {
+		c.JSON(http.StatusUnauthorized, models.Error{
+			Message: models.TokenInvalidMessage,
+		})
+		return nil, false
+	}
+
+	data, err := h.redisStorage.Get(ctx, uploadSessionPrefix+c.Param("id"))
+	if errors.Is(err, redis.Nil) {
+		c.JSON(http.StatusNotFound, models.Error{
+			Message: models.NotFoundMessage,
+		})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return nil, false
+	}
+
+	var session models.UploadSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return nil, false
+	}
+	if session.UserId != userId {
+		c.JSON(http.StatusNotFound, models.Error{
+			Message: models.NotFoundMessage,
+		})
+		return nil, false
+	}
+
+	return &session, true
+}
+
+func (h *HandlerV1) saveUploadSession(ctx context.Context, session *models.UploadSession) error {
+	return h.redisStorage.Set(ctx, uploadSessionPrefix+session.Id, session, time.Until(session.ExpiresAt))
+}
+
+// validateObject checks the content of an uploaded post file against its
+// extension, the same way CreatePost checks small uploads, and returns the
+// content type it detected.
+func (h *HandlerV1) validateObject(ctx context.Context, objectName string, size int64) (string, error) {
+	object, err := h.MinIO.GetObject(ctx, h.Config.Minio.FileUploadBucketName, objectName, minio.GetObjectOptions{})
+	if err != nil {
+		return "", err
+	}
+	defer object.Close()
+
+	return filetype.Validate(object, size, filepath.Ext(objectName))
+}
+
+func uploadStatus(session *models.UploadSession) models.UploadStatus {
+	return models.UploadStatus{
+		Id:        session.Id,
+		Offset:    session.Offset,
+		Size:      session.Size,
+		ChunkSize: session.ChunkSize,
+		ExpiresAt: session.ExpiresAt,
+	}
+}
@@ -2,11 +2,9 @@ package v1
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"net/http"
 	"path/filepath"
-	"strconv"
 	"strings"
 	"univer/api/models"
 	"univer/internal/entity"
@@ -305,7 +303,7 @@ func (h *HandlerV1) GetUser(c *gin.Context) {
 	} else if validation.ValidateUUID(userID) {
 		filter["id"] = userID
 	} else {
-		filter["username"] = userID
+		filter["username_normalized"] = userID
 	}
 
 	response, err := h.Service.User().GetUser(ctx, &entity.GetReq{
@@ -320,14 +318,15 @@ func (h *HandlerV1) GetUser(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, models.UserResponse{
-		Id:          userID,
-		UserName:    response.UserName,
-		Email:       response.Email,
-		PhoneNumber: response.PhoneNumber,
-		Bio:         response.Bio,
-		ImageUrl:    response.ImageUrl,
-		Refresh:     response.RefreshToken,
-		Role:        response.Role,
+		Id:           userID,
+		UserName:     response.UserName,
+		Email:        response.Email,
+		PhoneNumber:  response.PhoneNumber,
+		Bio:          response.Bio,
+		ImageUrl:     response.ImageUrl,
+		Refresh:      response.RefreshToken,
+		Role:         response.Role,
+		UniversityId: response.UniversityId,
 	})
 }
 
@@ -377,12 +376,15 @@ func (h *HandlerV1) GetDelUser(c *gin.Context) {
 
 // @Security  		BearerAuth
 // @Summary   		List User
-// @Description 	Api for getting list user
+// @Description 	Api for getting list user. Page with page and limit, or leave page out and follow next_cursor and prev_cursor.
 // @Tags 			users
 // @Accept 			json
 // @Produce 		json
-// @Param 			page query string true "Page"
+// @Param 			page query string false "Page"
 // @Param 			limit query string true "Limit"
+// @Param 			cursor query string false "Cursor from a previous response"
+// @Param 			count query bool false "Set to true for total_count when paging by cursor"
+// @Param 			username query string false "Username prefix, in Latin or Cyrillic"
 // @Success 		200 {object} models.ListUser
 // @Failure 		404 {object} models.Error
 // @Failure 		401 {object} models.Error
@@ -392,36 +394,20 @@ func (h *HandlerV1) GetDelUser(c *gin.Context) {
 func (h *HandlerV1) ListUsers(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
 	defer cancel()
-	page := c.Query("page")
-	limit := c.Query("limit")
-	pageInt, err := strconv.Atoi(page)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{
-			Message: err.Error(),
-		})
-		log.Println(err.Error())
-		return
-	}
-	limitInt, err := strconv.Atoi(limit)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{
-			Message: err.Error(),
-		})
-		log.Println(err.Error())
-		return
-	}
 
-	offset := (pageInt - 1) * limitInt
 	filter := map[string]string{
 		"role": "user",
 	}
-	listUsers, err := h.Service.User().ListUser(ctx, &entity.ListReq{
-		Offset: offset,
-		Limit:  limitInt,
-		Filter: filter,
-	})
+	if username := c.Query("username"); username != "" {
+		filter["username"] = username
+	}
+	req, ok := listRequest(c, filter)
+	if !ok {
+		return
+	}
+	listUsers, err := h.Service.User().ListUser(ctx, req)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.Error{
+		c.JSON(listErrorStatus(err), models.Error{
 			Message: err.Error(),
 		})
 		log.Println(err.Error())
@@ -443,8 +429,10 @@ func (h *HandlerV1) ListUsers(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, models.ListUser{
-		User:  users,
-		Total: uint64(listUsers.TotalCount),
+		User:       users,
+		Total:      uint64(listUsers.TotalCount),
+		NextCursor: encodeCursor(listUsers.NextCursor),
+		PrevCursor: encodeCursor(listUsers.PrevCursor),
 	})
 }
 
@@ -531,7 +519,7 @@ func (h *HandlerV1) UpdateProfile(c *gin.Context) {
 		return
 	}
 
-	minioURL := fmt.Sprintf("http://localhost:9000/%s/%s", h.Config.Minio.ImageUrlUploadBucketName, objectName)
+	minioURL := h.publicObjectURL(h.Config.Minio.ImageUrlUploadBucketName, objectName)
 
 	_, err = h.Service.User().UpdateProfile(ctx, &entity.UpdateProfile{
 		Id:       userId,
@@ -628,3 +616,66 @@ func (h *HandlerV1) UpdateToPremium(c *gin.Context) {
 		Response: "Your profile has changed succesfully",
 	})
 }
+
+// @Security        BearerAuth
+// @Summary         Set User University
+// @Description     Api for setting the university of the caller. Their post feed is scoped to it. Leave university_id empty to clear it.
+// @Tags            users
+// @Accept          json
+// @Produce         json
+// @Param           university body models.UserUniversity true "University"
+// @Success 		200 {object} models.Response
+// @Failure 		400 {object} models.Error
+// @Failure 		401 {object} models.Error
+// @Failure 		404 {object} models.Error
+// @Failure 		500 {object} models.Error
+// @Router 			/v1/user/university [PUT]
+func (h *HandlerV1) UpdateUserUniversity(c *gin.Context) {
+	var body models.UserUniversity
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Context.Timeout)
+	defer cancel()
+
+	userId, statusCode := GetIdFromToken(c.Request, &h.Config)
+	if statusCode != 0 {
+		c.JSON(http.StatusUnauthorized, models.Error{
+			Message: models.TokenInvalidMessage,
+		})
+		return
+	}
+
+	err := c.ShouldBindJSON(&body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	if body.UniversityId != "" {
+		if _, err := h.Service.University().GetUniversity(ctx, body.UniversityId); err != nil {
+			c.JSON(universityErrorStatus(err), models.Error{
+				Message: err.Error(),
+			})
+			log.Println(err.Error())
+			return
+		}
+	}
+
+	_, err = h.Service.User().UpdateUniversity(ctx, &entity.UpdateUniversity{
+		Id:           userId,
+		UniversityId: body.UniversityId,
+	})
+	if err != nil {
+		c.JSON(universityErrorStatus(err), models.Error{
+			Message: err.Error(),
+		})
+		log.Println(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Response: "Your university has changed successfully",
+	})
+}
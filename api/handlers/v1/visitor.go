@@ -0,0 +1,30 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+	"univer/internal/pkg/visitor"
+
+	"github.com/gin-gonic/gin"
+)
+
+const visitorCookie = "univer_visitor"
+
+// visitorId identifies a guest by their signed visitor cookie. A guest
+// without one gets an id derived from their address and user agent, which a
+// new cookie then keeps for longer than the fingerprint lasts.
+func (h *HandlerV1) visitorId(c *gin.Context) string {
+	if value, err := c.Cookie(visitorCookie); err == nil {
+		if id, ok := visitor.Verify(h.Config.Visitor.Secret, value); ok {
+			return id
+		}
+	}
+
+	id := visitor.Fingerprint(h.Config.Visitor.Secret, c.ClientIP(), c.Request.UserAgent(),
+		time.Now(), h.Config.Visitor.FingerprintTTL)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(visitorCookie, visitor.Sign(h.Config.Visitor.Secret, id),
+		int(h.Config.Visitor.CookieTTL.Seconds()), "/", "", c.Request.TLS != nil, true)
+
+	return id
+}
@@ -14,5 +14,7 @@ type (
 	ListCategory struct {
 		Categories []Category `json:"categories"`
 		Total      uint64     `json:"total_count"`
+		NextCursor string     `json:"next_cursor,omitempty"`
+		PrevCursor string     `json:"prev_cursor,omitempty"`
 	}
 )
\ No newline at end of file
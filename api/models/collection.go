@@ -0,0 +1,34 @@
+package models
+
+type CollectionReq struct {
+	Name        string `json:"name" binding:"required,max=200"`
+	Description string `json:"description" binding:"max=2000"`
+	Public      bool   `json:"public"`
+}
+
+type CollectionPostReq struct {
+	PostId string `json:"post_id" binding:"required"`
+}
+
+type CollectionReorderReq struct {
+	PostIds []string `json:"post_ids" binding:"required"`
+}
+
+type Collection struct {
+	Id          string  `json:"id"`
+	UserId      string  `json:"user_id"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Public      bool    `json:"public"`
+	PostsCount  int     `json:"posts_count"`
+	Posts       []*Post `json:"posts,omitempty"`
+	CreatedAt   string  `json:"created_at"`
+	UpdatedAt   string  `json:"updated_at"`
+}
+
+type ListCollection struct {
+	Collections []*Collection `json:"collections"`
+	TotalCount  int           `json:"total_count"`
+	NextCursor  string        `json:"next_cursor,omitempty"`
+	PrevCursor  string        `json:"prev_cursor,omitempty"`
+}
@@ -23,6 +23,8 @@ type CommentUpdate struct{
 type  ListComment struct{
 	Comment []*Comment
 	TotalCount int
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
 }
 
 type Like struct{
@@ -0,0 +1,17 @@
+package models
+
+type DownloadDay struct {
+	Date      string `json:"date"`
+	Downloads int64  `json:"downloads"`
+}
+
+// DownloadStats are a post's downloads: all of them, how many students made
+// them, and per day since since, days without downloads left out.
+type DownloadStats struct {
+	PostId         string         `json:"post_id"`
+	Downloads      int64          `json:"downloads"`
+	GuestDownloads int64          `json:"guest_downloads"`
+	Downloaders    int64          `json:"downloaders"`
+	Since          string         `json:"since"`
+	Days           []*DownloadDay `json:"days"`
+}
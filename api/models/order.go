@@ -0,0 +1,24 @@
+package models
+
+type Order struct {
+	Id        string  `json:"id"`
+	UserId    string  `json:"user_id"`
+	PostId    string  `json:"post_id"`
+	Price     float64 `json:"price"`
+	Status    string  `json:"status"`
+	CreatedAt string  `json:"created_at"`
+}
+
+// PaymentCallback is the payment provider's report on an order. Status is
+// paid or failed; Amount is what was paid.
+type PaymentCallback struct {
+	OrderId       string  `json:"order_id"`
+	TransactionId string  `json:"transaction_id"`
+	Status        string  `json:"status"`
+	Amount        float64 `json:"amount"`
+}
+
+type ListOrder struct {
+	Orders     []*Order `json:"orders"`
+	TotalCount int      `json:"total_count"`
+}
@@ -7,18 +7,47 @@ type Post struct {
 	UserId      string
 	Theme       string
 	Path        string
+	PdfPath     string
+	PreviewUrls []string `json:"preview_urls"`
+	Pages       int
 	Views       int
+	UserViews   int
+	GuestViews  int
+	Downloads   int
+	Comments    int
+	RatingAvg   float64
+	RatingCount int
 	Science     string
+	SubjectId   string
+	Tags        []string
 	CategoryId  string
 	Price       float64
 	PriceStatus bool
+
+	UniversityId string `json:",omitempty"`
+	FacultyId    string `json:",omitempty"`
+	CourseId     string `json:",omitempty"`
+
+	Locked   bool
+	Headline string `json:"headline,omitempty"`
+}
+
+type PostCreateResponse struct {
+	Id          string `json:"id"`
+	DuplicateOf string `json:"duplicate_of,omitempty"`
+	Warning     string `json:"warning,omitempty"`
 }
 
 type PostCreate struct {
-	Theme      string  `json:"theme"`
-	Science    string  `json:"science"`
-	CategoryId string  `json:"category_id"`
-	Price      float64 `json:"price"`
+	Theme      string   `json:"theme"`
+	Science    string   `json:"science"`
+	CategoryId string   `json:"category_id"`
+	Price      float64  `json:"price"`
+	Tags       []string `json:"tags"`
+
+	UniversityId string `json:"university_id"`
+	FacultyId    string `json:"faculty_id"`
+	CourseId     string `json:"course_id"`
 }
 
 type File struct {
@@ -31,12 +60,21 @@ type PostUpdateReq struct {
 	Science    string    `json:"science" binding:"required"`
 	CategoryId string    `json:"category_id" binding:"required"`
 	Price      float64   `json:"price,omitempty"`
+	Tags       []string  `json:"tags,omitempty"`
+
+	// UniversityId, FacultyId and CourseId replace the placement of the post;
+	// leaving them out clears it.
+	UniversityId string `json:"university_id,omitempty" binding:"omitempty,uuid"`
+	FacultyId    string `json:"faculty_id,omitempty" binding:"omitempty,uuid"`
+	CourseId     string `json:"course_id,omitempty" binding:"omitempty,uuid"`
 }
 
 
 type ListPost struct {
 	Post       []*Post
 	TotalCount int
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
 }
 
 type GetAll struct {
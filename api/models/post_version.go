@@ -0,0 +1,20 @@
+package models
+
+type PostVersion struct {
+	Version     int      `json:"version"`
+	PdfPath     string   `json:"pdf_path"`
+	PreviewUrls []string `json:"preview_urls"`
+	CreatedAt   string   `json:"created_at"`
+}
+
+type ListPostVersion struct {
+	Current  int            `json:"current"`
+	Versions []*PostVersion `json:"versions"`
+}
+
+type PostFileResponse struct {
+	Id          string `json:"id"`
+	Version     int    `json:"version"`
+	DuplicateOf string `json:"duplicate_of,omitempty"`
+	Warning     string `json:"warning,omitempty"`
+}
@@ -0,0 +1,22 @@
+package models
+
+type RatingReq struct {
+	Stars  int    `json:"stars" binding:"required,min=1,max=5"`
+	Review string `json:"review" binding:"max=2000"`
+}
+
+type Rating struct {
+	PostId    string `json:"post_id"`
+	UserId    string `json:"user_id"`
+	Stars     int    `json:"stars"`
+	Review    string `json:"review"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type ListRating struct {
+	Ratings    []*Rating `json:"ratings"`
+	TotalCount int       `json:"total_count"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+	PrevCursor string    `json:"prev_cursor,omitempty"`
+}
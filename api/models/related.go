@@ -0,0 +1,11 @@
+package models
+
+// RelatedPost is a post viewed by the students who viewed another one
+// (source "views"), or one of the same subject or category filled in when
+// there are too few of those (source "subject" or "category").
+type RelatedPost struct {
+	Post    *Post   `json:"post"`
+	Source  string  `json:"source"`
+	Score   float64 `json:"score,omitempty"`
+	CoViews int     `json:"co_views,omitempty"`
+}
@@ -0,0 +1,42 @@
+package models
+
+type SearchResult struct {
+	Post       []*Post
+	TotalCount int
+	NextCursor string        `json:"next_cursor,omitempty"`
+	PrevCursor string        `json:"prev_cursor,omitempty"`
+	Facets     *SearchFacets `json:"facets"`
+}
+
+type SearchFacets struct {
+	Science  []*FacetValue `json:"science"`
+	Category []*FacetValue `json:"category"`
+	Free     int           `json:"free"`
+	Paid     int           `json:"paid"`
+	Price    []*PriceRange `json:"price"`
+}
+
+// FacetValue is a filter value with the number of results it keeps. Value is
+// what the filter takes, Name is what the client shows.
+type FacetValue struct {
+	Value string `json:"value"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// PriceRange is passed back as priceMin and priceMax; a zero Max has no upper
+// bound.
+type PriceRange struct {
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Count int     `json:"count"`
+}
+
+type Suggestion struct {
+	Text string `json:"text"`
+	Kind string `json:"kind"`
+}
+
+type ListSuggestion struct {
+	Suggestions []*Suggestion `json:"suggestions"`
+}
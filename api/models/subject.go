@@ -0,0 +1,26 @@
+package models
+
+type (
+	SubjectReq struct {
+		Name string `json:"subject_name" binding:"required,max=50"`
+	}
+
+	Subject struct {
+		ID   string `json:"subject_id"`
+		Name string `json:"subject_name" binding:"required,max=50"`
+	}
+
+	ListSubject struct {
+		Subjects   []Subject `json:"subjects"`
+		Total      uint64    `json:"total_count"`
+		NextCursor string    `json:"next_cursor,omitempty"`
+		PrevCursor string    `json:"prev_cursor,omitempty"`
+	}
+
+	SubjectMapping struct {
+		Science     string `json:"science"`
+		SubjectId   string `json:"subject_id,omitempty"`
+		SubjectName string `json:"subject_name,omitempty"`
+		Posts       int    `json:"posts"`
+	}
+)
@@ -0,0 +1,27 @@
+package models
+
+type Tag struct {
+	Slug     string   `json:"slug"`
+	Name     string   `json:"name"`
+	Posts    int      `json:"posts"`
+	Synonyms []string `json:"synonyms,omitempty"`
+}
+
+type ListTag struct {
+	Tags       []*Tag `json:"tags"`
+	TotalCount int    `json:"total_count"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+type TagRenameReq struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type TagMergeReq struct {
+	Into string `json:"into" binding:"required"`
+}
+
+type TagSynonymReq struct {
+	Synonym string `json:"synonym" binding:"required"`
+}
@@ -0,0 +1,21 @@
+package models
+
+type TrendingPost struct {
+	Post  *Post   `json:"post"`
+	Score float64 `json:"score"`
+}
+
+// TrendingTopic is a category or subject by id.
+type TrendingTopic struct {
+	Id    string  `json:"id"`
+	Name  string  `json:"name"`
+	Score float64 `json:"score"`
+}
+
+type Trending struct {
+	Window     string           `json:"window"`
+	Posts      []*TrendingPost  `json:"posts"`
+	Categories []*TrendingTopic `json:"categories,omitempty"`
+	Sciences   []*TrendingTopic `json:"sciences,omitempty"`
+	ComputedAt string           `json:"computed_at,omitempty"`
+}
@@ -0,0 +1,58 @@
+package models
+
+type (
+	UniversityReq struct {
+		Name string `json:"university_name" binding:"required,max=100"`
+	}
+
+	University struct {
+		ID   string `json:"university_id"`
+		Name string `json:"university_name" binding:"required,max=100"`
+	}
+
+	ListUniversity struct {
+		Universities []University `json:"universities"`
+		Total        uint64       `json:"total_count"`
+		NextCursor   string       `json:"next_cursor,omitempty"`
+		PrevCursor   string       `json:"prev_cursor,omitempty"`
+	}
+
+	FacultyReq struct {
+		UniversityId string `json:"university_id" binding:"required,uuid"`
+		Name         string `json:"faculty_name" binding:"required,max=100"`
+	}
+
+	Faculty struct {
+		ID           string `json:"faculty_id"`
+		UniversityId string `json:"university_id"`
+		Name         string `json:"faculty_name" binding:"required,max=100"`
+	}
+
+	ListFaculty struct {
+		Faculties  []Faculty `json:"faculties"`
+		Total      uint64    `json:"total_count"`
+		NextCursor string    `json:"next_cursor,omitempty"`
+		PrevCursor string    `json:"prev_cursor,omitempty"`
+	}
+
+	CourseReq struct {
+		FacultyId string `json:"faculty_id" binding:"required,uuid"`
+		Name      string `json:"course_name" binding:"required,max=100"`
+		Year      int    `json:"year" binding:"min=0,max=10"`
+	}
+
+	Course struct {
+		ID           string `json:"course_id"`
+		FacultyId    string `json:"faculty_id"`
+		UniversityId string `json:"university_id"`
+		Name         string `json:"course_name" binding:"required,max=100"`
+		Year         int    `json:"year" binding:"min=0,max=10"`
+	}
+
+	ListCourse struct {
+		Courses    []Course `json:"courses"`
+		Total      uint64   `json:"total_count"`
+		NextCursor string   `json:"next_cursor,omitempty"`
+		PrevCursor string   `json:"prev_cursor,omitempty"`
+	}
+)
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+type UploadInit struct {
+	FileName   string   `json:"file_name" binding:"required"`
+	Size       int64    `json:"size" binding:"required"`
+	Theme      string   `json:"theme" binding:"required"`
+	Science    string   `json:"science" binding:"required"`
+	CategoryId string   `json:"category_id" binding:"required"`
+	Price      float64  `json:"price"`
+	Tags       []string `json:"tags"`
+
+	UniversityId string `json:"university_id" binding:"omitempty,uuid"`
+	FacultyId    string `json:"faculty_id" binding:"omitempty,uuid"`
+	CourseId     string `json:"course_id" binding:"omitempty,uuid"`
+}
+
+type UploadStatus struct {
+	Id        string    `json:"id"`
+	Offset    int64     `json:"offset"`
+	Size      int64     `json:"size"`
+	ChunkSize int64     `json:"chunk_size"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// UploadSession is the state of a resumable upload kept in Redis. Every chunk
+// is one part of the MinIO multipart upload behind it.
+type UploadSession struct {
+	Id         string       `json:"id"`
+	UserId     string       `json:"user_id"`
+	FileName   string       `json:"file_name"`
+	ObjectName string       `json:"object_name"`
+	UploadId   string       `json:"upload_id"`
+	Size       int64        `json:"size"`
+	Offset     int64        `json:"offset"`
+	ChunkSize  int64        `json:"chunk_size"`
+	Parts      []UploadPart `json:"parts"`
+	HashState  []byte       `json:"hash_state"` // sha256 of the chunks received so far
+	Post       UploadInit   `json:"post"`
+	ExpiresAt  time.Time    `json:"expires_at"`
+}
+
+type UploadPart struct {
+	Number int    `json:"number"`
+	ETag   string `json:"etag"`
+}
@@ -31,15 +31,16 @@ type Response struct {
 }
 
 type UserResponse struct {
-	Id          string `json:"id"`
-	UserName    string `json:"username"`
-	Email       string `json:"email"`
-	PhoneNumber string `json:"phone_number"`
-	Bio         string `json:"bio"`
-	ImageUrl    string `json:"image_url"`
-	Role        string `json:"role"`
-	Refresh     string `json:"refresh_token"`
-	Access      string `json:"access_token"`
+	Id           string `json:"id"`
+	UserName     string `json:"username"`
+	Email        string `json:"email"`
+	PhoneNumber  string `json:"phone_number"`
+	Bio          string `json:"bio"`
+	ImageUrl     string `json:"image_url"`
+	Role         string `json:"role"`
+	UniversityId string `json:"university_id,omitempty"`
+	Refresh      string `json:"refresh_token"`
+	Access       string `json:"access_token"`
 }
 
 
@@ -48,8 +49,10 @@ type CreateResponse struct {
 }
 
 type ListUser struct {
-	User  []UserResponse `json:"user"`
-	Total uint64 `json:"totcal_count"`
+	User       []UserResponse `json:"user"`
+	Total      uint64         `json:"totcal_count"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+	PrevCursor string         `json:"prev_cursor,omitempty"`
 }
 
 type UpdatePasswordReq struct {
@@ -67,3 +70,7 @@ type GoogleUser struct {
 	PictureUrl    string `json:"picture"`
 	Locale        string `json:"locale"`
 }
+
+type UserUniversity struct {
+	UniversityId string `json:"university_id" binding:"omitempty,uuid"`
+}
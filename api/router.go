@@ -11,6 +11,9 @@ import (
 	redisrepo "univer/internal/infrastructure/repository/redisdb"
 
 	"univer/internal/pkg/config"
+	"univer/internal/pkg/converter"
+	"univer/internal/pkg/extract"
+	"univer/internal/pkg/preview"
 	"univer/internal/pkg/token"
 
 	"github.com/casbin/casbin/v2"
@@ -31,6 +34,9 @@ type RouteOption struct {
 	RefreshToken   token.JWTHandler
 	Service        clientService.ServiceClient
 	MinIO          *minio.Client
+	Converter      converter.Converter
+	Preview        preview.Renderer
+	Extractor      extract.Extractor
 }
 
 // NewRoute
@@ -39,9 +45,14 @@ type RouteOption struct {
 // @securityDefinitions.apikey BearerAuth
 // @in 			header
 // @name 		Authorization
-func NewRoute(option RouteOption) *gin.Engine {
+func NewRoute(option RouteOption) (*gin.Engine, error) {
 	router := gin.New()
 
+	// ClientIP only follows X-Forwarded-For from these proxies
+	if err := router.SetTrustedProxies(option.Config.Server.TrustedProxies); err != nil {
+		return nil, err
+	}
+
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
 
@@ -54,6 +65,9 @@ func NewRoute(option RouteOption) *gin.Engine {
 		Enforcer:       option.Enforcer,
 		Service:        option.Service,
 		MinIO:          option.MinIO,
+		Converter:      option.Converter,
+		Preview:        option.Preview,
+		Extractor:      option.Extractor,
 	})
 
 	corsConfig := cors.DefaultConfig()
@@ -96,9 +110,61 @@ func NewRoute(option RouteOption) *gin.Engine {
 	apiV1.PUT("/post", HandlerV1.UpdatePost)
 	apiV1.DELETE("/post/:id", HandlerV1.DeletePost)
 	apiV1.GET("/post/:id", HandlerV1.GetPost)
+	apiV1.GET("/post/:id/related", HandlerV1.RelatedPosts)
 	apiV1.GET("/del/post/:id", HandlerV1.GetDelPost)
 	apiV1.GET("/posts", HandlerV1.ListPost)
+	apiV1.GET("/posts/trending", HandlerV1.TrendingPosts)
 	apiV1.GET("/user/posts", HandlerV1.GetAllPostByUserId)
+	apiV1.GET("/post/:id/download", HandlerV1.DownloadPost)
+	apiV1.GET("/post/:id/downloads", HandlerV1.PostDownloadStats)
+	apiV1.PUT("/post/:id/file", HandlerV1.UpdatePostFile)
+	apiV1.GET("/post/:id/versions", HandlerV1.ListPostVersions)
+	apiV1.GET("/post/:id/versions/:version/download", HandlerV1.DownloadPostVersion)
+	apiV1.POST("/post/:id/versions/:version/rollback", HandlerV1.RollbackPost)
+	apiV1.POST("/post/convert", HandlerV1.ConvertFile)
+
+	// rating
+	apiV1.POST("/post/:id/rating", HandlerV1.RatePost)
+	apiV1.GET("/post/:id/rating", HandlerV1.GetPostRating)
+	apiV1.DELETE("/post/:id/rating", HandlerV1.DeletePostRating)
+	apiV1.GET("/post/:id/ratings", HandlerV1.ListPostRatings)
+
+	// bookmark
+	apiV1.POST("/post/:id/bookmark", HandlerV1.BookmarkPost)
+	apiV1.DELETE("/post/:id/bookmark", HandlerV1.DeleteBookmark)
+	apiV1.GET("/user/bookmarks", HandlerV1.ListBookmarks)
+
+	// tag
+	apiV1.GET("/tags", HandlerV1.ListTags)
+	apiV1.GET("/tags/suggest", HandlerV1.SuggestTags)
+	apiV1.GET("/tags/:slug", HandlerV1.GetTag)
+	apiV1.GET("/tags/:slug/posts", HandlerV1.ListTagPosts)
+	apiV1.PUT("/tags/:slug", HandlerV1.RenameTag)
+	apiV1.POST("/tags/:slug/merge", HandlerV1.MergeTag)
+	apiV1.POST("/tags/:slug/synonyms", HandlerV1.AddTagSynonym)
+	apiV1.DELETE("/tags/:slug/synonyms/:synonym", HandlerV1.DeleteTagSynonym)
+
+	// collection
+	apiV1.POST("/collection", HandlerV1.CreateCollection)
+	apiV1.PUT("/collection/:id", HandlerV1.UpdateCollection)
+	apiV1.DELETE("/collection/:id", HandlerV1.DeleteCollection)
+	apiV1.GET("/collection/:id", HandlerV1.GetCollection)
+	apiV1.GET("/user/collections", HandlerV1.ListUserCollections)
+	apiV1.POST("/collection/:id/posts", HandlerV1.AddCollectionPost)
+	apiV1.PUT("/collection/:id/posts", HandlerV1.ReorderCollection)
+	apiV1.DELETE("/collection/:id/posts/:post_id", HandlerV1.RemoveCollectionPost)
+
+	// resumable upload
+	apiV1.POST("/upload", HandlerV1.InitUpload)
+	apiV1.PATCH("/upload/:id", HandlerV1.UploadChunk)
+	apiV1.GET("/upload/:id", HandlerV1.GetUploadStatus)
+	apiV1.DELETE("/upload/:id", HandlerV1.CancelUpload)
+	apiV1.POST("/upload/:id/complete", HandlerV1.CompleteUpload)
+
+	// order
+	apiV1.POST("/post/:id/purchase", HandlerV1.PurchasePost)
+	apiV1.GET("/user/orders", HandlerV1.ListOrders)
+	apiV1.POST("/payments/callback", HandlerV1.PaymentCallback)
 
 	// category
 	apiV1.POST("/category", HandlerV1.CreateCategory)
@@ -107,8 +173,35 @@ func NewRoute(option RouteOption) *gin.Engine {
 	apiV1.GET("/category/:id", HandlerV1.GetCategory)
 	apiV1.GET("/categories", HandlerV1.ListCategory)
 
+	// subject
+	apiV1.POST("/subject", HandlerV1.CreateSubject)
+	apiV1.PUT("/subject", HandlerV1.UpdateSubject)
+	apiV1.DELETE("/subject/:id", HandlerV1.DeleteSubject)
+	apiV1.GET("/subject/:id", HandlerV1.GetSubject)
+	apiV1.GET("/subjects", HandlerV1.ListSubjects)
+	apiV1.GET("/subjects/mapping", HandlerV1.SubjectMapping)
+
+	// university
+	apiV1.POST("/university", HandlerV1.CreateUniversity)
+	apiV1.PUT("/university", HandlerV1.UpdateUniversity)
+	apiV1.DELETE("/university/:id", HandlerV1.DeleteUniversity)
+	apiV1.GET("/university/:id", HandlerV1.GetUniversity)
+	apiV1.GET("/universities", HandlerV1.ListUniversities)
+	apiV1.GET("/university/:id/faculties", HandlerV1.ListUniversityFaculties)
+	apiV1.POST("/faculty", HandlerV1.CreateFaculty)
+	apiV1.PUT("/faculty", HandlerV1.UpdateFaculty)
+	apiV1.DELETE("/faculty/:id", HandlerV1.DeleteFaculty)
+	apiV1.GET("/faculty/:id", HandlerV1.GetFaculty)
+	apiV1.GET("/faculty/:id/courses", HandlerV1.ListFacultyCourses)
+	apiV1.POST("/course", HandlerV1.CreateCourse)
+	apiV1.PUT("/course", HandlerV1.UpdateCourse)
+	apiV1.DELETE("/course/:id", HandlerV1.DeleteCourse)
+	apiV1.GET("/course/:id", HandlerV1.GetCourse)
+	apiV1.PUT("/user/university", HandlerV1.UpdateUserUniversity)
+
 	//search
 	apiV1.GET("/search", HandlerV1.Search)
+	apiV1.GET("/search/suggest", HandlerV1.SuggestSearch)
 
 	//google
 	apiV1.GET("/google/callback", HandlerV1.GoogleCallback)
@@ -128,5 +221,5 @@ func NewRoute(option RouteOption) *gin.Engine {
 	url := ginSwagger.URL("swagger/doc.json")
 	apiV1.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, url))
 
-	return router
+	return router, nil
 }
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
@@ -9,9 +10,12 @@ import (
 	repo "univer/internal/infrastructure/repository/postgres"
 	redisrepo "univer/internal/infrastructure/repository/redisdb"
 	"univer/internal/pkg/config"
+	"univer/internal/pkg/converter"
+	"univer/internal/pkg/extract"
 	"univer/internal/pkg/logger"
 	minIOBucket "univer/internal/pkg/minio"
 	"univer/internal/pkg/otlp"
+	"univer/internal/pkg/preview"
 	storage "univer/internal/pkg/storage"
 	"univer/internal/usecase"
 
@@ -37,7 +41,22 @@ type App struct {
 	Post         usecase.Post
 	Category     usecase.Category
 	Comment      usecase.Comment
+	Order        usecase.Order
+	File         usecase.File
+	PostVersion  usecase.PostVersion
+	Rating       usecase.Rating
+	Collection   usecase.Collection
+	Bookmark     usecase.Bookmark
+	Tag          usecase.Tag
+	Subject      usecase.Subject
+	University   usecase.University
+	Trending     usecase.Trending
+	Related      usecase.Related
 	minIO        *minio.Client
+	converter    converter.Converter
+	preview      preview.Renderer
+	extractor    extract.Extractor
+	stopJobs     context.CancelFunc
 }
 
 func NewApp(cfg config.Config) (*App, error) {
@@ -73,6 +92,26 @@ func NewApp(cfg config.Config) (*App, error) {
 		return nil, err
 	}
 
+	// document converter
+	var documentConverter converter.Converter = converter.NewLibreOffice(cfg.Converter.Binary, cfg.Converter.Timeout)
+	if cfg.Converter.Driver == "fake" {
+		documentConverter = &converter.Fake{}
+	}
+	// every conversion is a LibreOffice process, so only a few run at once
+	documentConverter = converter.NewLimited(documentConverter, cfg.Converter.Concurrency)
+
+	// document preview renderer
+	var previewRenderer preview.Renderer = preview.NewPoppler(cfg.Preview.Binary, cfg.Preview.DPI, cfg.Preview.Timeout)
+	if cfg.Preview.Driver == "fake" {
+		previewRenderer = &preview.Fake{}
+	}
+
+	// document text extractor
+	var textExtractor extract.Extractor = extract.NewPdfToText(cfg.Extract.Binary, cfg.Extract.Timeout)
+	if cfg.Extract.Driver == "fake" {
+		textExtractor = &extract.Fake{}
+	}
+
 	// init db
 	db, err := storage.New(&cfg)
 	if err != nil {
@@ -88,12 +127,47 @@ func NewApp(cfg config.Config) (*App, error) {
 	servicecomment := repo.NewCommentRepo(db)
 	commentRepo := usecase.NewCommentService(contextTimeout, servicecomment)
 
-	servicepost := repo.NewPostRepo(db)
-	postRepo := usecase.NewPostService(contextTimeout, servicepost)
+	servicetag := repo.NewTagRepo(db)
+	tagRepo := usecase.NewTagService(contextTimeout, servicetag)
+
+	servicesubject := repo.NewSubjectRepo(db)
+	subjectRepo := usecase.NewSubjectService(contextTimeout, servicesubject)
+
+	serviceuniversity := repo.NewUniversityRepo(db)
+	universityRepo := usecase.NewUniversityService(contextTimeout, serviceuniversity)
+
+	viewBuffer := redisrepo.NewViewBuffer(redisdb)
 
 	servicecategory := repo.NewCategoryRepo(db)
 	categoryRepo := usecase.NewCategoryService(contextTimeout, servicecategory)
 
+	servicepost := repo.NewPostRepo(db)
+	postRepo := usecase.NewPostService(contextTimeout, servicepost, servicetag, servicesubject, servicecategory, serviceuniversity, viewBuffer)
+
+	serviceorder := repo.NewOrderRepo(db)
+	orderRepo := usecase.NewOrderService(contextTimeout, serviceorder, servicepost)
+
+	servicefile := repo.NewFileRepo(db)
+	fileRepo := usecase.NewFileService(contextTimeout, servicefile)
+
+	servicepostversion := repo.NewPostVersionRepo(db)
+	postVersionRepo := usecase.NewPostVersionService(contextTimeout, servicepostversion, servicepost)
+
+	servicerating := repo.NewRatingRepo(db)
+	ratingRepo := usecase.NewRatingService(contextTimeout, servicerating, servicepost)
+
+	servicecollection := repo.NewCollectionRepo(db)
+	collectionRepo := usecase.NewCollectionService(contextTimeout, servicecollection, servicepost)
+
+	servicebookmark := repo.NewBookmarkRepo(db)
+	bookmarkRepo := usecase.NewBookmarkService(contextTimeout, servicebookmark, servicepost)
+
+	servicetrending := repo.NewTrendingRepo(db)
+	trendingRepo := usecase.NewTrendingService(contextTimeout, servicetrending, servicepost)
+
+	servicerelated := repo.NewRelatedRepo(db)
+	relatedRepo := usecase.NewRelatedService(contextTimeout, servicerelated, servicepost)
+
 	return &App{
 		Config:       cfg,
 		Logger:       logger,
@@ -105,19 +179,33 @@ func NewApp(cfg config.Config) (*App, error) {
 		Post:         postRepo,
 		Category:     categoryRepo,
 		Comment:      &commentRepo,
+		Order:        orderRepo,
+		File:         fileRepo,
+		PostVersion:  postVersionRepo,
+		Rating:       ratingRepo,
+		Collection:   collectionRepo,
+		Bookmark:     bookmarkRepo,
+		Tag:          tagRepo,
+		Subject:      subjectRepo,
+		University:   universityRepo,
+		Trending:     trendingRepo,
+		Related:      relatedRepo,
 		minIO:        minioClient,
+		converter:    documentConverter,
+		preview:      previewRenderer,
+		extractor:    textExtractor,
 	}, nil
 }
 
 func (a *App) Run() error {
 
-	service := clientService.New(a.User, a.Post, a.Comment, a.Category)
+	service := clientService.New(a.User, a.Post, a.Comment, a.Category, a.Order, a.File, a.PostVersion, a.Rating, a.Collection, a.Bookmark, a.Tag, a.Subject, a.University, a.Trending, a.Related)
 
 	// initialize cache
 	cache := redisrepo.NewCache(a.RedisDB)
 
 	// api init
-	handler := api.NewRoute(api.RouteOption{
+	handler, err := api.NewRoute(api.RouteOption{
 		Config:         a.Config,
 		Logger:         a.Logger,
 		ContextTimeout: a.Config.Context.Timeout,
@@ -125,19 +213,30 @@ func (a *App) Run() error {
 		Enforcer:       a.Enforcer,
 		Service:        service,
 		MinIO:          a.minIO,
+		Converter:      a.converter,
+		Preview:        a.preview,
+		Extractor:      a.extractor,
 	})
+	if err != nil {
+		return fmt.Errorf("error while initializing router: %v", err)
+	}
 
-	err := minIOBucket.MinIOBucket(a.Config.Minio.FileUploadBucketName, a.minIO)
+	err = minIOBucket.MinIOBucket(a.Config.Minio.FileUploadBucketName, false, a.minIO)
 	if err != nil {
 		pp.Println(a.Config.Minio.FileUploadBucketName)
 		pp.Println("minIOda file bucket da xatolik bor")
 		return err
 	}
-	err = minIOBucket.MinIOBucket(a.Config.Minio.ImageUrlUploadBucketName, a.minIO)
+	err = minIOBucket.MinIOBucket(a.Config.Minio.ImageUrlUploadBucketName, true, a.minIO)
 	if err != nil {
 		pp.Println("minIO da image bucketda xatolik bor ")
 		return err
 	}
+	err = minIOBucket.MinIOBucket(a.Config.Minio.PreviewBucketName, true, a.minIO)
+	if err != nil {
+		pp.Println("minIO da preview bucketda xatolik bor")
+		return err
+	}
 
 	err = a.Enforcer.LoadPolicy()
 	if err != nil {
@@ -148,6 +247,26 @@ func (a *App) Run() error {
 	roleManager.AddMatchingFunc("keyMatch", util.KeyMatch)
 	roleManager.AddMatchingFunc("keyMatch3", util.KeyMatch3)
 
+	// background jobs
+	jobs, stopJobs := context.WithCancel(context.Background())
+	a.stopJobs = stopJobs
+	go a.every(jobs, "flush views", a.Config.Views.FlushInterval, a.Post.FlushViews)
+	go a.every(jobs, "compute trending", a.Config.Trending.Interval, func(ctx context.Context) error {
+		return a.Trending.Compute(ctx, a.Config.Trending.Limit)
+	})
+	go a.every(jobs, "compute related posts", a.Config.Related.Interval, func(ctx context.Context) error {
+		return a.Related.Compute(ctx, a.Config.Related.Lookback, a.Config.Related.Limit)
+	})
+	go a.every(jobs, "abort stale uploads", a.Config.Upload.SweepInterval, func(ctx context.Context) error {
+		// a session lives for SessionTTL from its start, anything older can
+		// no longer be completed
+		aborted, err := minIOBucket.AbortStaleUploads(ctx, a.minIO, a.Config.Minio.FileUploadBucketName, a.Config.Upload.SessionTTL)
+		if aborted > 0 {
+			a.Logger.Info("aborted stale uploads", zap.Int("count", aborted))
+		}
+		return err
+	})
+
 	// server init
 	a.server, err = api.NewServer(&a.Config, handler)
 	if err != nil {
@@ -158,6 +277,11 @@ func (a *App) Run() error {
 }
 
 func (a *App) Stop() {
+	// background jobs
+	if a.stopJobs != nil {
+		a.stopJobs()
+	}
+
 	// database connection
 	a.DB.Close()
 
@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// Bookmark is a post a user saved for later.
+type Bookmark struct {
+	UserId    string
+	PostId    string
+	CreatedAt time.Time
+}
+
+type BookmarkListRes struct {
+	Bookmark   []*Bookmark
+	TotalCount int
+	NextCursor *Cursor
+	PrevCursor *Cursor
+}
@@ -17,4 +17,6 @@ type UpdateCategory struct {
 type ListCategoryRes struct{
 	Category []*Category
 	Totalcount int
+	NextCursor *Cursor
+	PrevCursor *Cursor
 }
@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+// MaxCollectionPosts caps the posts of a collection, which is always returned
+// whole.
+const MaxCollectionPosts = 500
+
+// Collection is a named, ordered list of posts kept by a user. Public
+// collections can be opened by anyone who has the link.
+type Collection struct {
+	Id          string
+	UserId      string
+	Name        string
+	Description string
+	Public      bool
+	Posts       int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type CollectionListRes struct {
+	Collection []*Collection
+	TotalCount int
+	NextCursor *Cursor
+	PrevCursor *Cursor
+}
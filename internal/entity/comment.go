@@ -21,6 +21,8 @@ type CommentUpdateReq struct {
 type CommentListRes struct{
 	Comment []*Comment
 	TotalCount int
+	NextCursor *Cursor
+	PrevCursor *Cursor
 }
 
 type Like struct{
@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// Cursor marks the row a keyset page starts from. Sort names the order it
+// was made for and Key holds the leading sort value of orders that are not by
+// creation time alone, such as the views of a post or the rank of a search
+// result.
+type Cursor struct {
+	Sort      string
+	Key       *float64
+	CreatedAt time.Time
+	Id        string
+
+	// Before asks for the page that ends right before the row instead of the
+	// one that starts after it.
+	Before bool
+}
@@ -0,0 +1,32 @@
+package entity
+
+import "time"
+
+// Download is a student or guest being handed a post's file. Version is 0
+// for the current file and Format is "pdf" for the converted PDF. A guest's
+// download is Anonymous and its UserId is the guest's visitor id.
+type Download struct {
+	UserId    string
+	PostId    string
+	Version   int
+	Format    string
+	Anonymous bool
+	CreatedAt time.Time
+}
+
+// DownloadStats are a post's downloads: all of them, how many of them guests
+// made, how many students made them, and per day since Since, days without
+// downloads left out.
+type DownloadStats struct {
+	PostId         string
+	Downloads      int64
+	GuestDownloads int64
+	Downloaders    int64
+	Since          time.Time
+	Days           []*DownloadDay
+}
+
+type DownloadDay struct {
+	Date      time.Time
+	Downloads int64
+}
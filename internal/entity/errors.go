@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 )
@@ -8,6 +9,36 @@ import (
 var (
 	ErrorConflict = NewErrConflict("object")
 	ErrorNotFound = NewErrNotFound("object")
+
+	ErrorFreePost  = errors.New("post is free")
+	ErrorOwnPost   = errors.New("you cannot purchase your own post")
+	ErrorPurchased = NewErrConflict("purchase")
+	ErrorOwnRating = errors.New("you cannot rate your own post")
+
+	ErrorPaymentAmount = errors.New("paid amount does not match the order price")
+	ErrorOrderClosed   = errors.New("order is already paid or failed")
+
+	ErrorCollectionFull = errors.New("collection is full")
+	ErrorReorder        = errors.New("post ids must list every post of the collection once")
+
+	ErrorInvalidTag  = errors.New("a tag must have letters or digits and at most 50 characters")
+	ErrorTooManyTags = errors.New("too many tags")
+	ErrorTagExists   = NewErrConflict("tag")
+	ErrorMergeItself = errors.New("cannot merge a tag into itself")
+
+	ErrorUnknownSubject = errors.New("science must be one of the subjects")
+	ErrorSubjectInUse   = errors.New("subject still has posts")
+
+	ErrorUnknownCategory = errors.New("category_id must be one of the categories")
+
+	ErrorUniversityInUse = errors.New("university still has faculties, posts or users")
+	ErrorFacultyInUse    = errors.New("faculty still has courses or posts")
+	ErrorCourseInUse     = errors.New("course still has posts")
+	ErrorPlacement       = errors.New("university, faculty or course not found or they do not belong together")
+
+	ErrorInvalidWindow = errors.New("window must be 24h, 7d or 30d")
+
+	ErrorInvalidCursor = errors.New("cursor does not belong to this list")
 )
 
 // error not found
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// File is an uploaded blob shared by every post with the same content.
+type File struct {
+	Hash        string
+	Path        string
+	Size        int64
+	ContentType string
+	RefCount    int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
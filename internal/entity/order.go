@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+// An order is pending until the payment provider confirms it paid or failed;
+// only a paid order gives access to the post.
+const (
+	OrderStatusPending = "pending"
+	OrderStatusPaid    = "paid"
+	OrderStatusFailed  = "failed"
+)
+
+type Order struct {
+	Id        string
+	UserId    string
+	PostId    string
+	Price     float64
+	Status    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// TransactionId is the payment provider's id of the confirmed payment.
+	TransactionId string
+}
+
+type OrderListRes struct {
+	Order      []*Order
+	TotalCount int
+}
@@ -7,30 +7,101 @@ type Post struct {
 	UserId      string
 	Theme       string
 	Path        string
+	PdfPath     string
+	Previews    []string
+	FileHash    string
+	Version     int
+	Pages       int
 	Views       int
+	GuestViews  int // the part of Views made by guests
+	Downloads   int
+	Comments    int
+	RatingAvg   float64
+	RatingCount int
+	RatingScore float64
 	Science     string
+	SubjectId   string
+	Tags        []string
 	CategoryId  string
+
+	// UniversityId, FacultyId and CourseId place the post; any of them may
+	// be empty.
+	UniversityId string
+	FacultyId    string
+	CourseId     string
+
 	PriceStatus bool
 	Price       float64
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+
+	// Headline is the highlighted match of a search result and Rank its
+	// relevance.
+	Headline string
+	Rank     float64
 }
 
+// Orders of post lists. Rating orders by the Bayesian average of the stars;
+// relevance only applies to searches.
+const (
+	SortNewest    = "newest"
+	SortOldest    = "oldest"
+	SortViews     = "views"
+	SortComments  = "comments"
+	SortPriceAsc  = "price_asc"
+	SortPriceDesc = "price_desc"
+	SortRating    = "rating"
+	SortRelevance = "relevance"
+)
+
 type PostUpdateReq struct {
 	Id          string
 	Theme       string
-	Path        string
 	Science     string
+	SubjectId   string
 	CategoryId  string
 	PriceStatus bool
 	Price       float64
 	UpdatedAt   time.Time
+
+	UniversityId string
+	FacultyId    string
+	CourseId     string
+
+	// Tags replace the tags of the post; nil leaves them as they are.
+	Tags []string
 }
 
 type PostListRes struct {
 	Post       []*Post
 	TotalCount int
+	NextCursor *Cursor
+	PrevCursor *Cursor
 }
+
+// SearchFacets counts search results per filter value.
+type SearchFacets struct {
+	Science  []*FacetValue
+	Category []*FacetValue
+	Free     int
+	Paid     int
+	Price    []*PriceRange
+}
+
+type FacetValue struct {
+	Value string
+	Name  string
+	Count int
+}
+
+// PriceRange counts paid posts priced from Min up to Max; a zero Max has no
+// upper bound.
+type PriceRange struct {
+	Min   float64
+	Max   float64
+	Count int
+}
+
 type Search struct {
 	Theme string
 }
@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// PostContent is the text extracted from the current file of a post.
+type PostContent struct {
+	PostId    string
+	Version   int // the version of the post the text was extracted from
+	Text      string
+	Pages     int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
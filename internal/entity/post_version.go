@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// PostVersion is a previous revision of a post's file.
+type PostVersion struct {
+	Id        string
+	PostId    string
+	Version   int
+	Path      string
+	FileHash  string
+	PdfPath   string
+	Previews  []string
+	CreatedAt time.Time
+}
@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// Rating is the stars and optional review a user gave a post.
+type Rating struct {
+	PostId    string
+	UserId    string
+	Stars     int
+	Review    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type RatingListRes struct {
+	Rating     []*Rating
+	TotalCount int
+	NextCursor *Cursor
+	PrevCursor *Cursor
+}
@@ -0,0 +1,21 @@
+package entity
+
+// Where a related post comes from: viewed by the students who viewed the
+// post, or filled in from the same subject or category when there are too
+// few of those.
+const (
+	RelatedViews    = "views"
+	RelatedSubject  = "subject"
+	RelatedCategory = "category"
+)
+
+// RelatedPost is a post to show next to another one. Score is the cosine
+// similarity of the two posts' viewers, a download counting as a view, and
+// CoViews the number of students who viewed both; filled in posts have
+// neither.
+type RelatedPost struct {
+	Post    *Post
+	Source  string
+	Score   float64
+	CoViews int
+}
@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+// Subject is what a post is about, such as mathematics or history. Posts
+// pick one from the subjects admins keep instead of typing it freely.
+type Subject struct {
+	Id        string
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type ListSubjectRes struct {
+	Subject    []*Subject
+	TotalCount int
+	NextCursor *Cursor
+	PrevCursor *Cursor
+}
+
+// SubjectMapping reports which subject a free-text science was mapped to
+// when subjects were introduced. SubjectId is empty when it was not mapped.
+type SubjectMapping struct {
+	Science     string
+	SubjectId   string
+	SubjectName string
+	Posts       int
+}
@@ -0,0 +1,14 @@
+package entity
+
+const (
+	SuggestionTheme    = "theme"
+	SuggestionScience  = "science"
+	SuggestionCategory = "category"
+)
+
+// Suggestion is a completion offered while typing a search query.
+type Suggestion struct {
+	Text  string
+	Kind  string
+	Score float64
+}
@@ -0,0 +1,25 @@
+package entity
+
+import "time"
+
+// MaxPostTags caps the tags of a post.
+const MaxPostTags = 10
+
+// Tag is a normalized label posts can be found by. Synonyms are other slugs
+// that lead to the same tag.
+type Tag struct {
+	Id        string
+	Slug      string
+	Name      string
+	Posts     int
+	Synonyms  []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type TagListRes struct {
+	Tag        []*Tag
+	TotalCount int
+	NextCursor *Cursor
+	PrevCursor *Cursor
+}
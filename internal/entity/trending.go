@@ -0,0 +1,55 @@
+package entity
+
+import "time"
+
+// TrendingWindow is a period trending posts are computed over. A view or
+// download counts half as much every HalfLife, so recent ones weigh most.
+type TrendingWindow struct {
+	Name     string
+	Span     time.Duration
+	HalfLife time.Duration
+}
+
+var TrendingWindows = []TrendingWindow{
+	{Name: "24h", Span: 24 * time.Hour, HalfLife: 6 * time.Hour},
+	{Name: "7d", Span: 7 * 24 * time.Hour, HalfLife: 36 * time.Hour},
+	{Name: "30d", Span: 30 * 24 * time.Hour, HalfLife: 5 * 24 * time.Hour},
+}
+
+// Scopes of trending posts: every post, or the posts of one category or
+// subject.
+const (
+	TrendingAll      = "all"
+	TrendingCategory = "category"
+	TrendingScience  = "science"
+)
+
+type TrendingReq struct {
+	Window string
+	Scope  string
+	// Value is the category or subject id of a scoped list.
+	Value string
+	Limit int
+}
+
+type TrendingPost struct {
+	Post  *Post
+	Score float64
+}
+
+// TrendingTopic is a category or subject and the decayed views and downloads
+// of its posts.
+type TrendingTopic struct {
+	Kind  string
+	Value string
+	Name  string
+	Score float64
+}
+
+type Trending struct {
+	Window     string
+	Posts      []*TrendingPost
+	Categories []*TrendingTopic
+	Sciences   []*TrendingTopic
+	ComputedAt time.Time
+}
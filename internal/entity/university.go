@@ -0,0 +1,53 @@
+package entity
+
+import "time"
+
+// University, Faculty and Course place a post where it was made: a course
+// belongs to a faculty and a faculty to a university.
+type University struct {
+	Id        string
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type Faculty struct {
+	Id           string
+	UniversityId string
+	Name         string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// Course is a programme in a year of study. Year is 0 for a course not tied
+// to one.
+type Course struct {
+	Id           string
+	FacultyId    string
+	UniversityId string
+	Name         string
+	Year         int
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type UniversityListRes struct {
+	University []*University
+	TotalCount int
+	NextCursor *Cursor
+	PrevCursor *Cursor
+}
+
+type FacultyListRes struct {
+	Faculty    []*Faculty
+	TotalCount int
+	NextCursor *Cursor
+	PrevCursor *Cursor
+}
+
+type CourseListRes struct {
+	Course     []*Course
+	TotalCount int
+	NextCursor *Cursor
+	PrevCursor *Cursor
+}
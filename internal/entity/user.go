@@ -12,6 +12,7 @@ type User struct {
 	ImageUrl     string
 	RefreshToken string
 	Role         string
+	UniversityId string
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 }
@@ -36,11 +37,20 @@ type Response struct {
 type ListUserRes struct {
 	User       []*User
 	TotalCount int64
+	NextCursor *Cursor
+	PrevCursor *Cursor
 }
 type ListReq struct{
    Limit int
    Offset int
    Filter map[string]string
+
+   // Cursor pages by keyset instead of Offset when set.
+   Cursor *Cursor
+   // SkipCount leaves the total count of the list out.
+   SkipCount bool
+   // Sort names the order of lists that can be sorted.
+   Sort string
 }
 type DeleteReq struct{
 	Id string
@@ -50,6 +60,13 @@ type DeleteReq struct{
 type GetReq struct {
 	Filter map[string]string
 }
+// UpdateUniversity sets the university of a user; an empty UniversityId
+// clears it.
+type UpdateUniversity struct {
+	Id           string
+	UniversityId string
+}
+
 type UpdateProfile struct {
 	Id string
 	ImageUrl  string
@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// View is a student opening a post. A guest's view is Anonymous and has
+// their visitor id for UserId.
+type View struct {
+	UserId    string
+	PostId    string
+	Anonymous bool
+	CreatedAt time.Time
+}
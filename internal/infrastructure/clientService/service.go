@@ -8,6 +8,17 @@ type ServiceClient interface {
 	Category() usecase.Category
 	Comment()   usecase.Comment
 	Post() usecase.Post
+	Order() usecase.Order
+	File() usecase.File
+	PostVersion() usecase.PostVersion
+	Rating() usecase.Rating
+	Collection() usecase.Collection
+	Bookmark() usecase.Bookmark
+	Tag() usecase.Tag
+	Subject() usecase.Subject
+	University() usecase.University
+	Trending() usecase.Trending
+	Related() usecase.Related
 }
 
 type serviceClient struct{
@@ -15,14 +26,36 @@ type serviceClient struct{
 	post usecase.Post
 	comment usecase.Comment
 	category usecase.Category
+	order usecase.Order
+	file usecase.File
+	postVersion usecase.PostVersion
+	rating usecase.Rating
+	collection usecase.Collection
+	bookmark usecase.Bookmark
+	tag usecase.Tag
+	subject usecase.Subject
+	university usecase.University
+	trending usecase.Trending
+	related usecase.Related
 }
 
-func New(user usecase.User, post usecase.Post, comment usecase.Comment, category usecase.Category)ServiceClient{
+func New(user usecase.User, post usecase.Post, comment usecase.Comment, category usecase.Category, order usecase.Order, file usecase.File, postVersion usecase.PostVersion, rating usecase.Rating, collection usecase.Collection, bookmark usecase.Bookmark, tag usecase.Tag, subject usecase.Subject, university usecase.University, trending usecase.Trending, related usecase.Related)ServiceClient{
 	return &serviceClient{
 		user: user,
 		post: post,
 		category: category,
 		comment: comment,
+		order: order,
+		file: file,
+		postVersion: postVersion,
+		rating: rating,
+		collection: collection,
+		bookmark: bookmark,
+		tag: tag,
+		subject: subject,
+		university: university,
+		trending: trending,
+		related: related,
 	}
 }
 
@@ -38,3 +71,36 @@ func (s *serviceClient)Comment() usecase.Comment{
 func (s *serviceClient)Post() usecase.Post{
 	return s.post
 }
+func (s *serviceClient)Order() usecase.Order{
+	return s.order
+}
+func (s *serviceClient)File() usecase.File{
+	return s.file
+}
+func (s *serviceClient)PostVersion() usecase.PostVersion{
+	return s.postVersion
+}
+func (s *serviceClient)Rating() usecase.Rating{
+	return s.rating
+}
+func (s *serviceClient)Collection() usecase.Collection{
+	return s.collection
+}
+func (s *serviceClient)Bookmark() usecase.Bookmark{
+	return s.bookmark
+}
+func (s *serviceClient)Tag() usecase.Tag{
+	return s.tag
+}
+func (s *serviceClient)Subject() usecase.Subject{
+	return s.subject
+}
+func (s *serviceClient)University() usecase.University{
+	return s.university
+}
+func (s *serviceClient)Trending() usecase.Trending{
+	return s.trending
+}
+func (s *serviceClient)Related() usecase.Related{
+	return s.related
+}
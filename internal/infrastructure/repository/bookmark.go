@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+	"univer/internal/entity"
+)
+
+type Bookmark interface {
+	AddBookmark(ctx context.Context, bookmark *entity.Bookmark) error
+	DeleteBookmark(ctx context.Context, userId, postId string) error
+	ListBookmark(ctx context.Context, req *entity.ListReq) (*entity.BookmarkListRes, error)
+}
@@ -12,5 +12,5 @@ type Category interface{
 	UpdateCategory(ctx context.Context, category *entity.UpdateCategory) (*entity.UpdateCategory, error)
 	DeleteCategory(ctx context.Context, req *entity.DeleteReq) error
 	GetCategory(ctx context.Context, params map[string]string) (*entity.Category, error)
-	ListCategory(ctx context.Context, limit int, offset int) (*entity.ListCategoryRes, error)
+	ListCategory(ctx context.Context, req *entity.ListReq) (*entity.ListCategoryRes, error)
 }
\ No newline at end of file
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"univer/internal/entity"
+)
+
+type Collection interface {
+	CreateCollection(ctx context.Context, collection *entity.Collection) (*entity.Collection, error)
+	UpdateCollection(ctx context.Context, collection *entity.Collection) (*entity.Collection, error)
+	DeleteCollection(ctx context.Context, req *entity.DeleteReq) error
+	GetCollection(ctx context.Context, id string) (*entity.Collection, error)
+	ListCollection(ctx context.Context, req *entity.ListReq) (*entity.CollectionListRes, error)
+	AddCollectionPost(ctx context.Context, collectionId, postId string) error
+	RemoveCollectionPost(ctx context.Context, collectionId, postId string) error
+	ListCollectionPosts(ctx context.Context, collectionId string) ([]string, error)
+	ReorderCollectionPosts(ctx context.Context, collectionId string, postIds []string) error
+}
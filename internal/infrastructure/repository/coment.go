@@ -10,7 +10,7 @@ type Comment interface {
 	UpdateComment(ctx context.Context, category *entity.CommentUpdateReq) (*entity.CommentUpdateReq, error)
 	DeleteComment(ctx context.Context, req *entity.DeleteReq) error
 	GetComment(ctx context.Context, params map[string]string) (*entity.Comment, error)
-	ListComment(ctx context.Context, limit int, offset int, params map[string]string) (*entity.CommentListRes, error)
+	ListComment(ctx context.Context, req *entity.ListReq) (*entity.CommentListRes, error)
 	UpdateLike(ctx context.Context, req *entity.Like) (bool, error)
 	UpdateCommentLike(ctx context.Context, id string, status bool) (bool, error)
 	UpdateCommentDislike(ctx context.Context, id string, status bool) (bool, error)
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+	"univer/internal/entity"
+)
+
+type File interface {
+	GetFile(ctx context.Context, hash string) (*entity.File, error)
+	AcquireFile(ctx context.Context, file *entity.File) (*entity.File, error)
+	ReleaseFile(ctx context.Context, hash string) (*entity.File, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"univer/internal/entity"
+)
+
+type Order interface {
+	CreateOrder(ctx context.Context, order *entity.Order) (*entity.Order, error)
+	GetOrder(ctx context.Context, params map[string]string) (*entity.Order, error)
+	ListOrder(ctx context.Context, limit int, offset int, filter map[string]string) (*entity.OrderListRes, error)
+	CheckPurchase(ctx context.Context, userId string, postIds []string) (map[string]bool, error)
+	CloseOrder(ctx context.Context, order *entity.Order) error
+}
@@ -2,17 +2,24 @@ package repository
 
 import (
 	"context"
+	"time"
 	"univer/internal/entity"
 )
 
 type Post interface {
-	CreatePost(ctx context.Context, post *entity.Post) (*entity.Post, error)
-	UpdatePost(ctx context.Context, post *entity.PostUpdateReq) (*entity.PostUpdateReq, error)
+	CreatePost(ctx context.Context, post *entity.Post, tagIds []string) (*entity.Post, error)
+	UpdatePost(ctx context.Context, post *entity.PostUpdateReq, tagIds []string) (*entity.PostUpdateReq, error)
 	DeletePost(ctx context.Context, req *entity.DeleteReq) error
 	GetPost(ctx context.Context, params map[string]string) (*entity.Post, error)
-	ListPost(ctx context.Context, limit int, offset int, filter map[string]string) (*entity.PostListRes, error)
+	GetPosts(ctx context.Context, ids []string) ([]*entity.Post, error)
+	ListPost(ctx context.Context, req *entity.ListReq) (*entity.PostListRes, error)
 	Search(ctx context.Context, req *entity.ListReq)(*entity.PostListRes, error)
-	CheckUnique(ctx context.Context, UserId, PostId string) (bool, error)
-	CreateViews(ctx context.Context, userId, postId string) (bool, error)
-	UpdateViews(ctx context.Context, postId string)(bool, error)
+	SearchFacets(ctx context.Context, req *entity.ListReq) (*entity.SearchFacets, error)
+	CreateViews(ctx context.Context, views []*entity.View) (int64, error)
+	CreateDownload(ctx context.Context, download *entity.Download) error
+	DownloadStats(ctx context.Context, postId string, since time.Time) (*entity.DownloadStats, error)
+	UpdatePdfPath(ctx context.Context, postId string, version int, pdfPath string) error
+	UpdatePreviews(ctx context.Context, postId string, version int, previews []string) error
+	UpdateContent(ctx context.Context, content *entity.PostContent) error
+	Suggest(ctx context.Context, query string, limit int) ([]*entity.Suggestion, error)
 }
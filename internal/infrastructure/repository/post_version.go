@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+	"univer/internal/entity"
+)
+
+type PostVersion interface {
+	ReviseFile(ctx context.Context, archived *entity.PostVersion, post *entity.Post) error
+	GetPostVersion(ctx context.Context, postId string, version int) (*entity.PostVersion, error)
+	ListPostVersion(ctx context.Context, postId string) ([]*entity.PostVersion, error)
+}
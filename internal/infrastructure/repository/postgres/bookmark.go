@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"univer/internal/entity"
+	"univer/internal/pkg/otlp"
+	postgres "univer/internal/pkg/storage"
+
+	"github.com/Masterminds/squirrel"
+)
+
+const (
+	bookmarkServiceTableName   = "bookmarks"
+	serviceNameBookmarkService = "bookmarkServiceRepo"
+	spanNameBookmarkService    = "bookmarkSpanRepo"
+)
+
+type bookmarkRepo struct {
+	tableName string
+	db        *postgres.PostgresDB
+}
+
+func NewBookmarkRepo(db *postgres.PostgresDB) *bookmarkRepo {
+	return &bookmarkRepo{
+		tableName: bookmarkServiceTableName,
+		db:        db,
+	}
+}
+
+// AddBookmark saves a post for the user. Saving it again keeps the time it
+// was first saved.
+func (p bookmarkRepo) AddBookmark(ctx context.Context, bookmark *entity.Bookmark) error {
+	ctx, span := otlp.Start(ctx, serviceNameBookmarkService, spanNameBookmarkService+"AddBookmark")
+	defer span.End()
+
+	data := map[string]any{
+		"user_id":    bookmark.UserId,
+		"post_id":    bookmark.PostId,
+		"created_at": bookmark.CreatedAt,
+	}
+	query, args, err := p.db.Sq.Builder.Insert(p.tableName).
+		SetMap(data).
+		Suffix("ON CONFLICT (user_id, post_id) DO NOTHING").
+		ToSql()
+	if err != nil {
+		return p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "create"))
+	}
+
+	_, err = p.db.Exec(ctx, query, args...)
+	if err != nil {
+		return p.db.Error(err)
+	}
+
+	return nil
+}
+
+func (p bookmarkRepo) DeleteBookmark(ctx context.Context, userId, postId string) error {
+	ctx, span := otlp.Start(ctx, serviceNameBookmarkService, spanNameBookmarkService+"DeleteBookmark")
+	defer span.End()
+
+	query, args, err := p.db.Sq.Builder.Delete(p.tableName).
+		Where(p.db.Sq.Equal("user_id", userId)).
+		Where(p.db.Sq.Equal("post_id", postId)).
+		ToSql()
+	if err != nil {
+		return p.db.ErrSQLBuild(err, p.tableName+" delete")
+	}
+
+	commandTag, err := p.db.Exec(ctx, query, args...)
+	if err != nil {
+		return p.db.Error(err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return entity.ErrorNotFound
+	}
+
+	return nil
+}
+
+// ListBookmark returns the bookmarks of req.Filter["user_id"], most recently
+// saved first. Bookmarks of deleted posts are left out.
+func (p bookmarkRepo) ListBookmark(ctx context.Context, req *entity.ListReq) (*entity.BookmarkListRes, error) {
+	ctx, span := otlp.Start(ctx, serviceNameBookmarkService, spanNameBookmarkService+"ListBookmark")
+	defer span.End()
+
+	var bookmarks entity.BookmarkListRes
+
+	where := squirrel.And{
+		p.db.Sq.Equal("bookmarks.user_id", req.Filter["user_id"]),
+		squirrel.Expr("posts.deleted_at IS NULL"),
+	}
+
+	order := keyset{createdAt: "bookmarks.created_at", id: "bookmarks.post_id", desc: true}
+	queryBuilder, err := order.page(p.db.Sq.Builder.
+		Select(
+			"bookmarks.user_id",
+			"bookmarks.post_id",
+			"bookmarks.created_at",
+		).From(p.tableName).
+		Join(postServiceTableName+" on posts.id = bookmarks.post_id").
+		Where(where), req)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "list"))
+	}
+
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, p.db.Error(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bookmark entity.Bookmark
+		if err = rows.Scan(
+			&bookmark.UserId,
+			&bookmark.PostId,
+			&bookmark.CreatedAt,
+		); err != nil {
+			return nil, p.db.Error(err)
+		}
+
+		bookmarks.Bookmark = append(bookmarks.Bookmark, &bookmark)
+	}
+	bookmarks.Bookmark, bookmarks.NextCursor, bookmarks.PrevCursor = cursors(order, bookmarks.Bookmark, req, func(bookmark *entity.Bookmark) *entity.Cursor {
+		return &entity.Cursor{
+			CreatedAt: bookmark.CreatedAt,
+			Id:        bookmark.PostId,
+		}
+	})
+	if req.SkipCount {
+		return &bookmarks, nil
+	}
+
+	query, args, err = p.db.Sq.Builder.Select("COUNT(*)").
+		From(p.tableName).
+		Join(postServiceTableName + " on posts.id = bookmarks.post_id").
+		Where(where).
+		ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "list"))
+	}
+
+	if err := p.db.QueryRow(ctx, query, args...).Scan(&bookmarks.TotalCount); err != nil {
+		return nil, p.db.Error(err)
+	}
+
+	return &bookmarks, nil
+}
@@ -87,7 +87,7 @@ func (p categoryRepo) UpdateCategory(ctx context.Context, category *entity.Updat
 	}
 
 	if commandTag.RowsAffected() == 0 {
-		return nil, p.db.Error(fmt.Errorf("no sql rows"))
+		return nil, entity.ErrorNotFound
 	}
 
 	return category, nil
@@ -117,7 +117,7 @@ func (p categoryRepo) DeleteCategory(ctx context.Context, req *entity.DeleteReq)
 	}
 
 	if commandTag.RowsAffected() == 0 {
-		return p.db.Error(fmt.Errorf("no sql rows"))
+		return entity.ErrorNotFound
 	}
 
 	return nil
@@ -165,7 +165,7 @@ func (p categoryRepo)GetCategory(ctx context.Context, params map[string]string)
 	return &category, nil
 }
 
-func (p categoryRepo) ListCategory(ctx context.Context, limit int, offset int) (*entity.ListCategoryRes, error) {
+func (p categoryRepo) ListCategory(ctx context.Context, req *entity.ListReq) (*entity.ListCategoryRes, error) {
 
 	ctx, span := otlp.Start(ctx, serviceNameCategoryService, spanNameCategoryService+"ListCategory")
 	defer span.End()
@@ -173,14 +173,12 @@ func (p categoryRepo) ListCategory(ctx context.Context, limit int, offset int) (
 	var (
 		categories entity.ListCategoryRes
 	)
-	queryBuilder := p.categorySelectQueryPrefix()
-
-	if limit != 0 {
-		queryBuilder = queryBuilder.Limit(uint64(limit)).Offset(uint64(offset))
+	order := keyset{createdAt: "created_at", id: "id"}
+	queryBuilder, err := order.page(p.categorySelectQueryPrefix().Where("deleted_at IS NULL"), req)
+	if err != nil {
+		return nil, err
 	}
 
-	queryBuilder = queryBuilder.Where("deleted_at IS NULL").OrderBy("created_at")
-
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
 		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "list"))
@@ -207,6 +205,15 @@ func (p categoryRepo) ListCategory(ctx context.Context, limit int, offset int) (
 
 		categories.Category = append(categories.Category, &category)
 	}
+	categories.Category, categories.NextCursor, categories.PrevCursor = cursors(order, categories.Category, req, func(category *entity.Category) *entity.Cursor {
+		return &entity.Cursor{
+			CreatedAt: category.CreatedAt,
+			Id:        category.Id,
+		}
+	})
+	if req.SkipCount {
+		return &categories, nil
+	}
 
 	var count uint64
 
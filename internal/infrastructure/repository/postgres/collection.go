@@ -0,0 +1,332 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"univer/internal/entity"
+	"univer/internal/pkg/otlp"
+	postgres "univer/internal/pkg/storage"
+
+	"github.com/Masterminds/squirrel"
+)
+
+const (
+	collectionServiceTableName   = "collections"
+	collectionPostsTableName     = "collection_posts"
+	serviceNameCollectionService = "collectionServiceRepo"
+	spanNameCollectionService    = "collectionSpanRepo"
+)
+
+type collectionRepo struct {
+	tableName string
+	db        *postgres.PostgresDB
+}
+
+func NewCollectionRepo(db *postgres.PostgresDB) *collectionRepo {
+	return &collectionRepo{
+		tableName: collectionServiceTableName,
+		db:        db,
+	}
+}
+
+// collectionsSelectQueryPrefix selects collections with the number of their
+// posts that are not deleted.
+func (p *collectionRepo) collectionsSelectQueryPrefix() squirrel.SelectBuilder {
+	return p.db.Sq.Builder.
+		Select(
+			"collections.id",
+			"collections.user_id",
+			"collections.name",
+			"collections.description",
+			"collections.public",
+			"(SELECT count(*) FROM collection_posts JOIN posts ON posts.id = collection_posts.post_id WHERE collection_posts.collection_id = collections.id AND posts.deleted_at IS NULL)",
+			"collections.created_at",
+			"collections.updated_at",
+		).From(p.tableName)
+}
+
+func (p collectionRepo) CreateCollection(ctx context.Context, collection *entity.Collection) (*entity.Collection, error) {
+	ctx, span := otlp.Start(ctx, serviceNameCollectionService, spanNameCollectionService+"CreateCollection")
+	defer span.End()
+
+	data := map[string]any{
+		"id":          collection.Id,
+		"user_id":     collection.UserId,
+		"name":        collection.Name,
+		"description": collection.Description,
+		"public":      collection.Public,
+		"created_at":  collection.CreatedAt,
+		"updated_at":  collection.UpdatedAt,
+	}
+	query, args, err := p.db.Sq.Builder.Insert(p.tableName).SetMap(data).ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "create"))
+	}
+
+	_, err = p.db.Exec(ctx, query, args...)
+	if err != nil {
+		return nil, p.db.Error(err)
+	}
+
+	return collection, nil
+}
+
+func (p collectionRepo) UpdateCollection(ctx context.Context, collection *entity.Collection) (*entity.Collection, error) {
+	ctx, span := otlp.Start(ctx, serviceNameCollectionService, spanNameCollectionService+"UpdateCollection")
+	defer span.End()
+
+	clauses := map[string]any{
+		"name":        collection.Name,
+		"description": collection.Description,
+		"public":      collection.Public,
+		"updated_at":  collection.UpdatedAt,
+	}
+	query, args, err := p.db.Sq.Builder.
+		Update(p.tableName).
+		SetMap(clauses).
+		Where(p.db.Sq.Equal("id", collection.Id)).
+		Where("deleted_at IS NULL").
+		ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "update"))
+	}
+
+	commandTag, err := p.db.Exec(ctx, query, args...)
+	if err != nil {
+		return nil, p.db.Error(err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return nil, entity.ErrorNotFound
+	}
+
+	return collection, nil
+}
+
+func (p collectionRepo) DeleteCollection(ctx context.Context, req *entity.DeleteReq) error {
+	ctx, span := otlp.Start(ctx, serviceNameCollectionService, spanNameCollectionService+"DeleteCollection")
+	defer span.End()
+
+	query, args, err := p.db.Sq.Builder.
+		Update(p.tableName).
+		Set("deleted_at", req.DeletedAt).
+		Where(p.db.Sq.Equal("id", req.Id)).
+		Where("deleted_at IS NULL").
+		ToSql()
+	if err != nil {
+		return p.db.ErrSQLBuild(err, p.tableName+" delete")
+	}
+
+	commandTag, err := p.db.Exec(ctx, query, args...)
+	if err != nil {
+		return p.db.Error(err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return entity.ErrorNotFound
+	}
+
+	return nil
+}
+
+func (p collectionRepo) GetCollection(ctx context.Context, id string) (*entity.Collection, error) {
+	ctx, span := otlp.Start(ctx, serviceNameCollectionService, spanNameCollectionService+"GetCollection")
+	defer span.End()
+
+	query, args, err := p.collectionsSelectQueryPrefix().
+		Where(p.db.Sq.Equal("collections.id", id)).
+		Where("collections.deleted_at IS NULL").
+		ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "get"))
+	}
+
+	var collection entity.Collection
+	if err = p.db.QueryRow(ctx, query, args...).Scan(
+		&collection.Id,
+		&collection.UserId,
+		&collection.Name,
+		&collection.Description,
+		&collection.Public,
+		&collection.Posts,
+		&collection.CreatedAt,
+		&collection.UpdatedAt,
+	); err != nil {
+		return nil, p.db.Error(err)
+	}
+
+	return &collection, nil
+}
+
+// ListCollection returns the collections of req.Filter["user_id"], newest
+// first. With req.Filter["public"] set only public ones are listed.
+func (p collectionRepo) ListCollection(ctx context.Context, req *entity.ListReq) (*entity.CollectionListRes, error) {
+	ctx, span := otlp.Start(ctx, serviceNameCollectionService, spanNameCollectionService+"ListCollection")
+	defer span.End()
+
+	var collections entity.CollectionListRes
+
+	where := squirrel.And{
+		p.db.Sq.Equal("collections.user_id", req.Filter["user_id"]),
+		squirrel.Expr("collections.deleted_at IS NULL"),
+	}
+	if _, ok := req.Filter["public"]; ok {
+		where = append(where, squirrel.Expr("collections.public"))
+	}
+
+	order := keyset{createdAt: "collections.created_at", id: "collections.id", desc: true}
+	queryBuilder, err := order.page(p.collectionsSelectQueryPrefix().Where(where), req)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "list"))
+	}
+
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, p.db.Error(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var collection entity.Collection
+		if err = rows.Scan(
+			&collection.Id,
+			&collection.UserId,
+			&collection.Name,
+			&collection.Description,
+			&collection.Public,
+			&collection.Posts,
+			&collection.CreatedAt,
+			&collection.UpdatedAt,
+		); err != nil {
+			return nil, p.db.Error(err)
+		}
+
+		collections.Collection = append(collections.Collection, &collection)
+	}
+	collections.Collection, collections.NextCursor, collections.PrevCursor = cursors(order, collections.Collection, req, func(collection *entity.Collection) *entity.Cursor {
+		return &entity.Cursor{
+			CreatedAt: collection.CreatedAt,
+			Id:        collection.Id,
+		}
+	})
+	if req.SkipCount {
+		return &collections, nil
+	}
+
+	query, args, err = p.db.Sq.Builder.Select("COUNT(*)").
+		From(p.tableName).
+		Where(where).
+		ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "list"))
+	}
+
+	if err := p.db.QueryRow(ctx, query, args...).Scan(&collections.TotalCount); err != nil {
+		return nil, p.db.Error(err)
+	}
+
+	return &collections, nil
+}
+
+// AddCollectionPost puts a post at the end of a collection. Adding a post
+// that is there already keeps its place.
+func (p collectionRepo) AddCollectionPost(ctx context.Context, collectionId, postId string) error {
+	ctx, span := otlp.Start(ctx, serviceNameCollectionService, spanNameCollectionService+"AddCollectionPost")
+	defer span.End()
+
+	query := `
+		INSERT INTO collection_posts (collection_id, post_id, position)
+		SELECT $1, $2, coalesce(max(position), 0) + 1 FROM collection_posts WHERE collection_id = $1
+		ON CONFLICT (collection_id, post_id) DO NOTHING`
+
+	_, err := p.db.Exec(ctx, query, collectionId, postId)
+	if err != nil {
+		return p.db.Error(err)
+	}
+
+	return nil
+}
+
+func (p collectionRepo) RemoveCollectionPost(ctx context.Context, collectionId, postId string) error {
+	ctx, span := otlp.Start(ctx, serviceNameCollectionService, spanNameCollectionService+"RemoveCollectionPost")
+	defer span.End()
+
+	query, args, err := p.db.Sq.Builder.Delete(collectionPostsTableName).
+		Where(p.db.Sq.Equal("collection_id", collectionId)).
+		Where(p.db.Sq.Equal("post_id", postId)).
+		ToSql()
+	if err != nil {
+		return p.db.ErrSQLBuild(err, collectionPostsTableName+" delete")
+	}
+
+	commandTag, err := p.db.Exec(ctx, query, args...)
+	if err != nil {
+		return p.db.Error(err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return entity.ErrorNotFound
+	}
+
+	return nil
+}
+
+// ListCollectionPosts returns the ids of the posts of a collection in their
+// order. Deleted posts are left out.
+func (p collectionRepo) ListCollectionPosts(ctx context.Context, collectionId string) ([]string, error) {
+	ctx, span := otlp.Start(ctx, serviceNameCollectionService, spanNameCollectionService+"ListCollectionPosts")
+	defer span.End()
+
+	query, args, err := p.db.Sq.Builder.
+		Select("collection_posts.post_id").
+		From(collectionPostsTableName).
+		Join(postServiceTableName+" on posts.id = collection_posts.post_id").
+		Where(p.db.Sq.Equal("collection_posts.collection_id", collectionId)).
+		Where("posts.deleted_at IS NULL").
+		OrderBy("collection_posts.position", "collection_posts.created_at").
+		ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, collectionPostsTableName+" list")
+	}
+
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, p.db.Error(err)
+	}
+	defer rows.Close()
+
+	var postIds []string
+	for rows.Next() {
+		var postId string
+		if err = rows.Scan(&postId); err != nil {
+			return nil, p.db.Error(err)
+		}
+		postIds = append(postIds, postId)
+	}
+
+	return postIds, rows.Err()
+}
+
+// ReorderCollectionPosts moves the posts of a collection into the order of
+// postIds.
+func (p collectionRepo) ReorderCollectionPosts(ctx context.Context, collectionId string, postIds []string) error {
+	ctx, span := otlp.Start(ctx, serviceNameCollectionService, spanNameCollectionService+"ReorderCollectionPosts")
+	defer span.End()
+
+	query := `
+		UPDATE collection_posts SET position = ordered.position
+		FROM unnest($2::uuid[]) WITH ORDINALITY AS ordered (post_id, position)
+		WHERE collection_posts.collection_id = $1 AND collection_posts.post_id = ordered.post_id`
+
+	_, err := p.db.Exec(ctx, query, collectionId, postIds)
+	if err != nil {
+		return p.db.Error(err)
+	}
+
+	return nil
+}
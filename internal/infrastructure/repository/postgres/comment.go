@@ -96,7 +96,7 @@ func (p commentRepo) UpdateComment(ctx context.Context, category *entity.Comment
 	}
 
 	if commandTag.RowsAffected() == 0 {
-		return nil, p.db.Error(fmt.Errorf("no sql rows"))
+		return nil, entity.ErrorNotFound
 	}
 
 	return category, nil
@@ -126,7 +126,7 @@ func (p commentRepo) DeleteComment(ctx context.Context, req *entity.DeleteReq) e
 	}
 
 	if commandTag.RowsAffected() == 0 {
-		return p.db.Error(fmt.Errorf("no sql rows"))
+		return entity.ErrorNotFound
 	}
 
 	return nil
@@ -174,7 +174,7 @@ func (p commentRepo) GetComment(ctx context.Context, params map[string]string) (
 	return &comment, nil
 }
 
-func (p commentRepo) ListComment(ctx context.Context, limit int, offset int, params map[string]string) (*entity.CommentListRes, error) {
+func (p commentRepo) ListComment(ctx context.Context, req *entity.ListReq) (*entity.CommentListRes, error) {
 
 	ctx, span := otlp.Start(ctx, serviceNameCommentService, spanNameCommentService+"ListComment")
 	defer span.End()
@@ -184,11 +184,7 @@ func (p commentRepo) ListComment(ctx context.Context, limit int, offset int, par
 	)
 	queryBuilder := p.comentSelectQueryPrefix()
 
-	if limit != 0 {
-		queryBuilder = queryBuilder.Limit(uint64(limit)).Offset(uint64(offset))
-	}
-
-	for key, value := range params {
+	for key, value := range req.Filter {
 		if key == "owner_id" {
 			queryBuilder = queryBuilder.Where(p.db.Sq.Equal(key, value))
 		}
@@ -197,7 +193,11 @@ func (p commentRepo) ListComment(ctx context.Context, limit int, offset int, par
 		}
 	}
 
-	queryBuilder = queryBuilder.Where("deleted_at IS NULL").OrderBy("created_at")
+	order := keyset{createdAt: "created_at", id: "id"}
+	queryBuilder, err := order.page(queryBuilder.Where("deleted_at IS NULL"), req)
+	if err != nil {
+		return nil, err
+	}
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
@@ -228,6 +228,15 @@ func (p commentRepo) ListComment(ctx context.Context, limit int, offset int, par
 
 		comments.Comment = append(comments.Comment, &comment)
 	}
+	comments.Comment, comments.NextCursor, comments.PrevCursor = cursors(order, comments.Comment, req, func(comment *entity.Comment) *entity.Cursor {
+		return &entity.Cursor{
+			CreatedAt: comment.CreatedAt,
+			Id:        comment.Id,
+		}
+	})
+	if req.SkipCount {
+		return &comments, nil
+	}
 
 	var count uint64
 
@@ -273,7 +282,7 @@ func (p commentRepo) UpdateLike(ctx context.Context, req *entity.Like) (bool, er
 	}
 
 	if commandTag.RowsAffected() == 0 {
-		return false, p.db.Error(fmt.Errorf("no sql rows"))
+		return false, entity.ErrorNotFound
 	}
 
 	return true, nil
@@ -377,7 +386,7 @@ func (p commentRepo) DeleteLike(ctx context.Context, req *entity.Like) error {
 	}
 
 	if commandTag.RowsAffected() == 0 {
-		return p.db.Error(fmt.Errorf("no sql rows"))
+		return entity.ErrorNotFound
 	}
 
 	return nil
@@ -400,7 +409,7 @@ func (p commentRepo) UpdateCommentLike(ctx context.Context, Id string, status bo
 	}
 
 	if commandTag.RowsAffected() == 0 {
-		return false, p.db.Error(fmt.Errorf("no sql rows"))
+		return false, entity.ErrorNotFound
 	}
 
 	return true, nil
@@ -423,7 +432,7 @@ func (p commentRepo) UpdateCommentDislike(ctx context.Context, Id string, status
 	}
 
 	if commandTag.RowsAffected() == 0 {
-		return false, p.db.Error(fmt.Errorf("no sql rows"))
+		return false, entity.ErrorNotFound
 	}
 
 	return true, nil
@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"univer/internal/entity"
+	"univer/internal/pkg/otlp"
+	postgres "univer/internal/pkg/storage"
+)
+
+const (
+	fileServiceTableName   = "files"
+	serviceNameFileService = "fileServiceRepo"
+	spanNameFileService    = "fileSpanRepo"
+)
+
+type fileRepo struct {
+	tableName string
+	db        *postgres.PostgresDB
+}
+
+func NewFileRepo(db *postgres.PostgresDB) *fileRepo {
+	return &fileRepo{
+		tableName: fileServiceTableName,
+		db:        db,
+	}
+}
+
+func (p fileRepo) GetFile(ctx context.Context, hash string) (*entity.File, error) {
+	ctx, span := otlp.Start(ctx, serviceNameFileService, spanNameFileService+"GetFile")
+	defer span.End()
+
+	var file entity.File
+
+	query, args, err := p.db.Sq.Builder.
+		Select(
+			"hash",
+			"path",
+			"size",
+			"content_type",
+			"ref_count",
+			"created_at",
+			"updated_at",
+		).From(p.tableName).
+		Where(p.db.Sq.Equal("hash", hash)).
+		ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "get"))
+	}
+
+	if err = p.db.QueryRow(ctx, query, args...).Scan(
+		&file.Hash,
+		&file.Path,
+		&file.Size,
+		&file.ContentType,
+		&file.RefCount,
+		&file.CreatedAt,
+		&file.UpdatedAt,
+	); err != nil {
+		return nil, p.db.Error(err)
+	}
+
+	return &file, nil
+}
+
+// AcquireFile stores the blob on first use and adds a reference to it. When
+// the hash is already known the stored row wins, so the returned path may
+// differ from file.Path.
+func (p fileRepo) AcquireFile(ctx context.Context, file *entity.File) (*entity.File, error) {
+	ctx, span := otlp.Start(ctx, serviceNameFileService, spanNameFileService+"AcquireFile")
+	defer span.End()
+
+	data := map[string]any{
+		"hash":         file.Hash,
+		"path":         file.Path,
+		"size":         file.Size,
+		"content_type": file.ContentType,
+		"ref_count":    1,
+		"created_at":   file.CreatedAt,
+		"updated_at":   file.UpdatedAt,
+	}
+	query, args, err := p.db.Sq.Builder.Insert(p.tableName).SetMap(data).
+		Suffix("ON CONFLICT (hash) DO UPDATE SET ref_count = files.ref_count + 1, updated_at = EXCLUDED.updated_at").
+		Suffix("RETURNING path, ref_count, created_at").
+		ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "acquire"))
+	}
+
+	acquired := *file
+	if err = p.db.QueryRow(ctx, query, args...).Scan(
+		&acquired.Path,
+		&acquired.RefCount,
+		&acquired.CreatedAt,
+	); err != nil {
+		return nil, p.db.Error(err)
+	}
+
+	return &acquired, nil
+}
+
+// ReleaseFile drops a reference to the blob. The row is deleted together with
+// the last reference and returned, so the caller can remove the object; while
+// other references remain it returns nil.
+func (p fileRepo) ReleaseFile(ctx context.Context, hash string) (*entity.File, error) {
+	ctx, span := otlp.Start(ctx, serviceNameFileService, spanNameFileService+"ReleaseFile")
+	defer span.End()
+
+	var refCount int
+	query := `UPDATE files SET ref_count = ref_count - 1, updated_at = now() WHERE hash = $1 RETURNING ref_count`
+	if err := p.db.QueryRow(ctx, query, hash).Scan(&refCount); err != nil {
+		return nil, p.db.Error(err)
+	}
+	if refCount > 0 {
+		return nil, nil
+	}
+
+	var file entity.File
+	query = `DELETE FROM files WHERE hash = $1 AND ref_count <= 0 RETURNING hash, path, size, content_type, ref_count, created_at, updated_at`
+	if err := p.db.QueryRow(ctx, query, hash).Scan(
+		&file.Hash,
+		&file.Path,
+		&file.Size,
+		&file.ContentType,
+		&file.RefCount,
+		&file.CreatedAt,
+		&file.UpdatedAt,
+	); err != nil {
+		// a concurrent upload took a new reference in between
+		if p.db.Error(err) == entity.ErrorNotFound {
+			return nil, nil
+		}
+		return nil, p.db.Error(err)
+	}
+
+	return &file, nil
+}
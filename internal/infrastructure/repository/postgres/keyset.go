@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"univer/internal/entity"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// keyset pages a list by its sort order instead of an offset, so rows added
+// while a client pages do not shift the pages. Rows are ordered by key, when
+// set, then created_at and id, all in the same direction, which lets a cursor
+// be compared with a single row comparison. name tells the sort orders of a
+// list apart, so a cursor is only used with the order it was made for.
+type keyset struct {
+	name      string
+	key       string
+	createdAt string
+	id        string
+	desc      bool
+}
+
+// page limits queryBuilder to the page req asks for. One row more than the
+// limit is read to tell whether another page follows; cursors trims it.
+func (k keyset) page(queryBuilder squirrel.SelectBuilder, req *entity.ListReq) (squirrel.SelectBuilder, error) {
+	backward := req.Cursor != nil && req.Cursor.Before
+	if req.Cursor != nil {
+		after, err := k.after(req.Cursor)
+		if err != nil {
+			return queryBuilder, err
+		}
+		queryBuilder = queryBuilder.Where(after)
+	}
+	queryBuilder = queryBuilder.OrderBy(k.order(backward)...)
+	if req.Limit != 0 {
+		queryBuilder = queryBuilder.Limit(uint64(req.Limit) + 1)
+		if req.Cursor == nil {
+			queryBuilder = queryBuilder.Offset(uint64(req.Offset))
+		}
+	}
+
+	return queryBuilder, nil
+}
+
+// after matches the rows that come after cursor in the direction it pages.
+func (k keyset) after(cursor *entity.Cursor) (squirrel.Sqlizer, error) {
+	if cursor.Sort != k.name || (k.key != "") != (cursor.Key != nil) {
+		return nil, entity.ErrorInvalidCursor
+	}
+
+	operator := ">"
+	if k.desc != cursor.Before {
+		operator = "<"
+	}
+	columns := []string{k.createdAt, k.id}
+	args := []interface{}{cursor.CreatedAt, cursor.Id}
+	if k.key != "" {
+		columns = append([]string{k.key}, columns...)
+		args = append([]interface{}{*cursor.Key}, args...)
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+	return squirrel.Expr(fmt.Sprintf("(%s) %s (%s)", strings.Join(columns, ", "), operator, placeholders), args...), nil
+}
+
+// order returns the ORDER BY terms, reversed when paging backward.
+func (k keyset) order(backward bool) []string {
+	direction := " ASC"
+	if k.desc != backward {
+		direction = " DESC"
+	}
+	var terms []string
+	if k.key != "" {
+		terms = append(terms, k.key+direction)
+	}
+	return append(terms, k.createdAt+direction, k.id+direction)
+}
+
+// cursors trims the extra row read by k.page, restores the order of a
+// backward page and returns the cursors of the pages around it. cursor builds
+// the cursor of a row.
+func cursors[T any](k keyset, rows []T, req *entity.ListReq, cursor func(T) *entity.Cursor) ([]T, *entity.Cursor, *entity.Cursor) {
+	more := req.Limit != 0 && len(rows) > req.Limit
+	if more {
+		rows = rows[:req.Limit]
+	}
+	backward := req.Cursor != nil && req.Cursor.Before
+	if backward {
+		slices.Reverse(rows)
+	}
+	if len(rows) == 0 {
+		return rows, nil, nil
+	}
+
+	hasNext, hasPrev := more, req.Cursor != nil || req.Offset > 0
+	if backward {
+		hasNext, hasPrev = true, more
+	}
+
+	var next, prev *entity.Cursor
+	if hasNext {
+		next = cursor(rows[len(rows)-1])
+		next.Sort = k.name
+	}
+	if hasPrev {
+		prev = cursor(rows[0])
+		prev.Sort = k.name
+		prev.Before = true
+	}
+
+	return rows, next, prev
+}
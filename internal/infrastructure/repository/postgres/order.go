@@ -0,0 +1,230 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"univer/internal/entity"
+	"univer/internal/pkg/otlp"
+	postgres "univer/internal/pkg/storage"
+
+	"github.com/Masterminds/squirrel"
+)
+
+const (
+	orderServiceTableName   = "orders"
+	serviceNameOrderService = "orderServiceRepo"
+	spanNameOrderService    = "orderSpanRepo"
+)
+
+type orderRepo struct {
+	tableName string
+	db        *postgres.PostgresDB
+}
+
+func NewOrderRepo(db *postgres.PostgresDB) *orderRepo {
+	return &orderRepo{
+		tableName: orderServiceTableName,
+		db:        db,
+	}
+}
+
+func (p *orderRepo) ordersSelectQueryPrefix() squirrel.SelectBuilder {
+	return p.db.Sq.Builder.
+		Select(
+			"id",
+			"user_id",
+			"post_id",
+			"price",
+			"status",
+			"transaction_id",
+			"created_at",
+			"updated_at",
+		).From(p.tableName)
+}
+
+func (p orderRepo) CreateOrder(ctx context.Context, order *entity.Order) (*entity.Order, error) {
+	ctx, span := otlp.Start(ctx, serviceNameOrderService, spanNameOrderService+"CreateOrder")
+	defer span.End()
+
+	data := map[string]any{
+		"id":         order.Id,
+		"user_id":    order.UserId,
+		"post_id":    order.PostId,
+		"price":      order.Price,
+		"status":     order.Status,
+		"created_at": order.CreatedAt,
+		"updated_at": order.UpdatedAt,
+	}
+	query, args, err := p.db.Sq.Builder.Insert(p.tableName).SetMap(data).ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "create"))
+	}
+
+	_, err = p.db.Exec(ctx, query, args...)
+	if err != nil {
+		return nil, p.db.Error(err)
+	}
+
+	return order, nil
+}
+
+func (p orderRepo) GetOrder(ctx context.Context, params map[string]string) (*entity.Order, error) {
+	ctx, span := otlp.Start(ctx, serviceNameOrderService, spanNameOrderService+"GetOrder")
+	defer span.End()
+
+	var order entity.Order
+
+	queryBuilder := p.ordersSelectQueryPrefix()
+
+	for key, value := range params {
+		if key == "id" || key == "user_id" || key == "post_id" || key == "status" {
+			queryBuilder = queryBuilder.Where(p.db.Sq.Equal(key, value))
+		}
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "get"))
+	}
+
+	if err = p.db.QueryRow(ctx, query, args...).Scan(
+		&order.Id,
+		&order.UserId,
+		&order.PostId,
+		&order.Price,
+		&order.Status,
+		&order.TransactionId,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+	); err != nil {
+		return nil, p.db.Error(err)
+	}
+
+	return &order, nil
+}
+
+func (p orderRepo) ListOrder(ctx context.Context, limit int, offset int, filter map[string]string) (*entity.OrderListRes, error) {
+	ctx, span := otlp.Start(ctx, serviceNameOrderService, spanNameOrderService+"ListOrder")
+	defer span.End()
+
+	var orders entity.OrderListRes
+
+	queryBuilder := p.ordersSelectQueryPrefix()
+	countBuilder := p.db.Sq.Builder.Select("COUNT(*)").From(p.tableName)
+
+	if limit != 0 {
+		queryBuilder = queryBuilder.Limit(uint64(limit)).Offset(uint64(offset))
+	}
+	for key, value := range filter {
+		if key == "user_id" || key == "post_id" || key == "status" {
+			queryBuilder = queryBuilder.Where(p.db.Sq.Equal(key, value))
+			countBuilder = countBuilder.Where(p.db.Sq.Equal(key, value))
+		}
+	}
+
+	queryBuilder = queryBuilder.OrderBy("created_at DESC")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "list"))
+	}
+
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, p.db.Error(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var order entity.Order
+
+		if err = rows.Scan(
+			&order.Id,
+			&order.UserId,
+			&order.PostId,
+			&order.Price,
+			&order.Status,
+			&order.TransactionId,
+			&order.CreatedAt,
+			&order.UpdatedAt,
+		); err != nil {
+			return nil, p.db.Error(err)
+		}
+
+		orders.Order = append(orders.Order, &order)
+	}
+
+	query, args, err = countBuilder.ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "list"))
+	}
+
+	if err := p.db.QueryRow(ctx, query, args...).Scan(&orders.TotalCount); err != nil {
+		return nil, p.db.Error(err)
+	}
+
+	return &orders, nil
+}
+
+// CheckPurchase reports which of postIds the user holds a paid order for.
+func (p orderRepo) CheckPurchase(ctx context.Context, userId string, postIds []string) (map[string]bool, error) {
+	ctx, span := otlp.Start(ctx, serviceNameOrderService, spanNameOrderService+"CheckPurchase")
+	defer span.End()
+
+	purchased := make(map[string]bool, len(postIds))
+	if len(postIds) == 0 {
+		return purchased, nil
+	}
+
+	query, args, err := p.db.Sq.Builder.Select("post_id").
+		From(p.tableName).
+		Where(squirrel.Eq{"user_id": userId, "post_id": postIds, "status": entity.OrderStatusPaid}).
+		ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "checkPurchase"))
+	}
+
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, p.db.Error(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postId string
+		if err = rows.Scan(&postId); err != nil {
+			return nil, p.db.Error(err)
+		}
+		purchased[postId] = true
+	}
+
+	return purchased, rows.Err()
+}
+
+// CloseOrder moves a pending order to order.Status, paid or failed, with the
+// provider's transaction id. An order that is no longer pending is not found.
+func (p orderRepo) CloseOrder(ctx context.Context, order *entity.Order) error {
+	ctx, span := otlp.Start(ctx, serviceNameOrderService, spanNameOrderService+"CloseOrder")
+	defer span.End()
+
+	query, args, err := p.db.Sq.Builder.Update(p.tableName).
+		Set("status", order.Status).
+		Set("transaction_id", order.TransactionId).
+		Set("updated_at", order.UpdatedAt).
+		Where(p.db.Sq.Equal("id", order.Id)).
+		Where(p.db.Sq.Equal("status", entity.OrderStatusPending)).
+		ToSql()
+	if err != nil {
+		return p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "close"))
+	}
+
+	commandTag, err := p.db.Exec(ctx, query, args...)
+	if err != nil {
+		return p.db.Error(err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return entity.ErrorNotFound
+	}
+
+	return nil
+}
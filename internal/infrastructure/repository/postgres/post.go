@@ -3,21 +3,53 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 	"univer/internal/entity"
 	"univer/internal/pkg/otlp"
+	"univer/internal/pkg/search"
 	postgres "univer/internal/pkg/storage"
+	"univer/internal/pkg/translit"
 
 	"github.com/Masterminds/squirrel"
 )
 
 const (
 	viewsTableName          = "views"
+	downloadsTableName      = "downloads"
+	postContentsTableName   = "post_contents"
 	postServiceTableName    = "posts"
 	serviceNamePostsService = "postServiceRepo"
 	spanNamePostsService    = "postSpanRepo"
+
+	// facetLimit caps the values returned for the science and category facets
+	facetLimit = 20
 )
 
+// priceRangeBounds split paid posts into the ranges of the price facet.
+var priceRangeBounds = []float64{10000, 50000, 100000, 500000}
+
+// postPlacements are the filters that narrow posts to a university, faculty
+// or course.
+var postPlacements = map[string]bool{
+	"university_id": true,
+	"faculty_id":    true,
+	"course_id":     true,
+}
+
+// universityScopeWhere keeps the posts of a university together with the
+// posts that belong to none, for feeds scoped to the reader's university.
+const universityScopeWhere = "(university_id = ? OR university_id IS NULL)"
+
+// nullId stores an empty optional id as NULL.
+func nullId(id string) any {
+	if id == "" {
+		return nil
+	}
+	return id
+}
+
 type postRepo struct {
 	tableName string
 	db        *postgres.PostgresDB
@@ -37,8 +69,24 @@ func (p *postRepo) postsSelectQueryPrefix() squirrel.SelectBuilder {
 			"user_id",
 			"theme",
 			"path",
+			"pdf_path",
+			"previews",
+			"file_hash",
+			"version",
+			"pages",
 			"views",
+			"guest_views",
+			"downloads",
+			"comments_count",
+			"rating_avg",
+			"rating_count",
+			"rating_score",
 			"science",
+			"coalesce(subject_id::text, '') AS subject_id",
+			"coalesce(university_id::text, '') AS university_id",
+			"coalesce(faculty_id::text, '') AS faculty_id",
+			"coalesce(course_id::text, '') AS course_id",
+			postTagsColumn("posts"),
 			"category_id",
 			"price_status",
 			"price",
@@ -47,49 +95,76 @@ func (p *postRepo) postsSelectQueryPrefix() squirrel.SelectBuilder {
 		).From(p.tableName)
 }
 
-func (p postRepo) CreatePost(ctx context.Context, post *entity.Post) (*entity.Post, error) {
+// CreatePost stores a post together with the tags in tagIds.
+func (p postRepo) CreatePost(ctx context.Context, post *entity.Post, tagIds []string) (*entity.Post, error) {
 	ctx, span := otlp.Start(ctx, serviceNamePostsService, spanNamePostsService+"CreatePost")
 	defer span.End()
 
 	data := map[string]any{
-		"id":           post.Id,
-		"user_id":      post.UserId,
-		"theme":        post.Theme,
-		"path":         post.Path,
-		"views":        post.Views,
-		"science":      post.Science,
-		"category_id":  post.CategoryId,
-		"price_status": post.PriceStatus,
-		"price":        post.Price,
-		"created_at":   post.CreatedAt,
-		"updated_at":   post.UpdatedAt,
+		"id":            post.Id,
+		"user_id":       post.UserId,
+		"theme":         post.Theme,
+		"path":          post.Path,
+		"file_hash":     post.FileHash,
+		"version":       post.Version,
+		"views":         post.Views,
+		"science":       post.Science,
+		"subject_id":    post.SubjectId,
+		"university_id": nullId(post.UniversityId),
+		"faculty_id":    nullId(post.FacultyId),
+		"course_id":     nullId(post.CourseId),
+		"category_id":   post.CategoryId,
+		"price_status":  post.PriceStatus,
+		"price":         post.Price,
+		"created_at":    post.CreatedAt,
+		"updated_at":    post.UpdatedAt,
 	}
 	query, args, err := p.db.Sq.Builder.Insert(p.tableName).SetMap(data).ToSql()
 	if err != nil {
 		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "create"))
 	}
 
-	_, err = p.db.Exec(ctx, query, args...)
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		return nil, p.db.Error(err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, query, args...)
 	if err != nil {
 		return nil, p.db.Error(err)
 	}
+	if len(tagIds) != 0 {
+		if _, err = tx.Exec(ctx, setPostTagsQuery, post.Id, tagIds); err != nil {
+			return nil, p.db.Error(err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, p.db.Error(err)
+	}
 
 	return post, nil
 }
 
-func (p postRepo) UpdatePost(ctx context.Context, post *entity.PostUpdateReq) (*entity.PostUpdateReq, error) {
+// UpdatePost updates a post and replaces its tags with tagIds; nil tagIds
+// leaves the tags as they are.
+func (p postRepo) UpdatePost(ctx context.Context, post *entity.PostUpdateReq, tagIds []string) (*entity.PostUpdateReq, error) {
 
 	ctx, span := otlp.Start(ctx, serviceNamePostsService, spanNamePostsService+"UpdatePost")
 	defer span.End()
 
 	clauses := map[string]any{
-		"theme":        post.Theme,
-		"path":         post.Path,
-		"science":      post.Science,
-		"category_id":  post.CategoryId,
-		"price_status": post.PriceStatus,
-		"price":        post.Price,
-		"updated_at":   post.UpdatedAt,
+		"theme":         post.Theme,
+		"science":       post.Science,
+		"subject_id":    post.SubjectId,
+		"university_id": nullId(post.UniversityId),
+		"faculty_id":    nullId(post.FacultyId),
+		"course_id":     nullId(post.CourseId),
+		"category_id":   post.CategoryId,
+		"price_status":  post.PriceStatus,
+		"price":         post.Price,
+		"updated_at":    post.UpdatedAt,
 	}
 	sqlStr, args, err := p.db.Sq.Builder.
 		Update(p.tableName).
@@ -101,13 +176,29 @@ func (p postRepo) UpdatePost(ctx context.Context, post *entity.PostUpdateReq) (*
 		return nil, p.db.ErrSQLBuild(err, p.tableName+" update")
 	}
 
-	commandTag, err := p.db.Exec(ctx, sqlStr, args...)
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		return nil, p.db.Error(err)
+	}
+	defer tx.Rollback(ctx)
+
+	commandTag, err := tx.Exec(ctx, sqlStr, args...)
 	if err != nil {
 		return nil, p.db.Error(err)
 	}
 
 	if commandTag.RowsAffected() == 0 {
-		return nil, p.db.Error(fmt.Errorf("no sql rows"))
+		return nil, entity.ErrorNotFound
+	}
+
+	if tagIds != nil {
+		if _, err = tx.Exec(ctx, setPostTagsQuery, post.Id, tagIds); err != nil {
+			return nil, p.db.Error(err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, p.db.Error(err)
 	}
 
 	return post, nil
@@ -137,7 +228,7 @@ func (p postRepo) DeletePost(ctx context.Context, req *entity.DeleteReq) error {
 	}
 
 	if commandTag.RowsAffected() == 0 {
-		return p.db.Error(fmt.Errorf("no sql rows"))
+		return entity.ErrorNotFound
 	}
 
 	return nil
@@ -157,6 +248,9 @@ func (p postRepo) GetPost(ctx context.Context, params map[string]string) (*entit
 	for key, value := range params {
 		if key == "id" {
 			queryBuilder = queryBuilder.Where(p.db.Sq.Equal(key, value))
+		} else if key == "file_hash" {
+			// oldest post sharing the file
+			queryBuilder = queryBuilder.Where(p.db.Sq.Equal(key, value)).OrderBy("created_at").Limit(1)
 		} else if key == "del" {
 			cnt++
 		}
@@ -174,8 +268,24 @@ func (p postRepo) GetPost(ctx context.Context, params map[string]string) (*entit
 		&post.UserId,
 		&post.Theme,
 		&post.Path,
+		&post.PdfPath,
+		&post.Previews,
+		&post.FileHash,
+		&post.Version,
+		&post.Pages,
 		&post.Views,
+		&post.GuestViews,
+		&post.Downloads,
+		&post.Comments,
+		&post.RatingAvg,
+		&post.RatingCount,
+		&post.RatingScore,
 		&post.Science,
+		&post.SubjectId,
+		&post.UniversityId,
+		&post.FacultyId,
+		&post.CourseId,
+		&post.Tags,
 		&post.CategoryId,
 		&post.PriceStatus,
 		&post.Price,
@@ -188,7 +298,73 @@ func (p postRepo) GetPost(ctx context.Context, params map[string]string) (*entit
 	return &post, nil
 }
 
-func (p postRepo) ListPost(ctx context.Context, limit int, offset int, filter map[string]string) (*entity.PostListRes, error) {
+// GetPosts returns the posts with the given ids that are not deleted, in no
+// particular order.
+func (p postRepo) GetPosts(ctx context.Context, ids []string) ([]*entity.Post, error) {
+	ctx, span := otlp.Start(ctx, serviceNamePostsService, spanNamePostsService+"GetPosts")
+	defer span.End()
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := p.postsSelectQueryPrefix().
+		Where(p.db.Sq.Equal("id", ids)).
+		Where("deleted_at is null").
+		ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "get"))
+	}
+
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, p.db.Error(err)
+	}
+	defer rows.Close()
+
+	var posts []*entity.Post
+	for rows.Next() {
+		var post entity.Post
+
+		if err = rows.Scan(
+			&post.Id,
+			&post.UserId,
+			&post.Theme,
+			&post.Path,
+			&post.PdfPath,
+			&post.Previews,
+			&post.FileHash,
+			&post.Version,
+			&post.Pages,
+			&post.Views,
+			&post.GuestViews,
+			&post.Downloads,
+			&post.Comments,
+			&post.RatingAvg,
+			&post.RatingCount,
+			&post.RatingScore,
+			&post.Science,
+			&post.SubjectId,
+			&post.UniversityId,
+			&post.FacultyId,
+			&post.CourseId,
+			&post.Tags,
+			&post.CategoryId,
+			&post.PriceStatus,
+			&post.Price,
+			&post.CreatedAt,
+			&post.UpdatedAt,
+		); err != nil {
+			return nil, p.db.Error(err)
+		}
+
+		posts = append(posts, &post)
+	}
+
+	return posts, rows.Err()
+}
+
+func (p postRepo) ListPost(ctx context.Context, req *entity.ListReq) (*entity.PostListRes, error) {
 
 	ctx, span := otlp.Start(ctx, serviceNamePostsService, spanNamePostsService+"ListPost")
 	defer span.End()
@@ -198,10 +374,7 @@ func (p postRepo) ListPost(ctx context.Context, limit int, offset int, filter ma
 	)
 	queryBuilder := p.postsSelectQueryPrefix()
 
-	if limit != 0 {
-		queryBuilder = queryBuilder.Limit(uint64(limit)).Offset(uint64(offset))
-	}
-	for key, value := range filter {
+	for key, value := range req.Filter {
 		if key == "user_id" {
 			queryBuilder = queryBuilder.Where(p.db.Sq.Equal(key, value))
 		}
@@ -209,9 +382,22 @@ func (p postRepo) ListPost(ctx context.Context, limit int, offset int, filter ma
 			queryBuilder = queryBuilder.Where(p.db.Sq.Equal(key, value))
 
 		}
+		if key == "tags" {
+			queryBuilder = queryBuilder.Where(postTagsWhere(value))
+		}
+		if postPlacements[key] {
+			queryBuilder = queryBuilder.Where(p.db.Sq.Equal(key, value))
+		}
+		if key == "scope_university_id" {
+			queryBuilder = queryBuilder.Where(universityScopeWhere, value)
+		}
 	}
 
-	queryBuilder = queryBuilder.Where("deleted_at IS NULL").OrderBy("created_at")
+	order := postOrder(req.Sort, "")
+	queryBuilder, err := order.page(queryBuilder.Where("deleted_at IS NULL"), req)
+	if err != nil {
+		return nil, err
+	}
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
@@ -223,17 +409,33 @@ func (p postRepo) ListPost(ctx context.Context, limit int, offset int, filter ma
 		return nil, p.db.Error(err)
 	}
 	defer rows.Close()
-	var post entity.Post
 
 	for rows.Next() {
+		var post entity.Post
 
 		if err = rows.Scan(
 			&post.Id,
 			&post.UserId,
 			&post.Theme,
 			&post.Path,
+			&post.PdfPath,
+			&post.Previews,
+			&post.FileHash,
+			&post.Version,
+			&post.Pages,
 			&post.Views,
+			&post.GuestViews,
+			&post.Downloads,
+			&post.Comments,
+			&post.RatingAvg,
+			&post.RatingCount,
+			&post.RatingScore,
 			&post.Science,
+			&post.SubjectId,
+			&post.UniversityId,
+			&post.FacultyId,
+			&post.CourseId,
+			&post.Tags,
 			&post.CategoryId,
 			&post.PriceStatus,
 			&post.Price,
@@ -245,19 +447,37 @@ func (p postRepo) ListPost(ctx context.Context, limit int, offset int, filter ma
 
 		posts.Post = append(posts.Post, &post)
 	}
+	posts.Post, posts.NextCursor, posts.PrevCursor = cursors(order, posts.Post, req, postCursor(order))
+	if req.SkipCount {
+		return &posts, nil
+	}
 
 	var count uint64
 
 	queryBuilder = p.db.Sq.Builder.Select("COUNT(*)").
 		From(p.tableName).
 		Where("deleted_at is null")
+	if userId, ok := req.Filter["user_id"]; ok {
+		queryBuilder = queryBuilder.Where(p.db.Sq.Equal("user_id", userId))
+	}
+	if tags, ok := req.Filter["tags"]; ok {
+		queryBuilder = queryBuilder.Where(postTagsWhere(tags))
+	}
+	for key, value := range req.Filter {
+		if postPlacements[key] {
+			queryBuilder = queryBuilder.Where(p.db.Sq.Equal(key, value))
+		}
+		if key == "scope_university_id" {
+			queryBuilder = queryBuilder.Where(universityScopeWhere, value)
+		}
+	}
 
-	query, _, err = queryBuilder.ToSql()
+	query, args, err = queryBuilder.ToSql()
 	if err != nil {
 		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "list"))
 	}
 
-	if err := p.db.QueryRow(ctx, query).Scan(&count); err != nil {
+	if err := p.db.QueryRow(ctx, query, args...).Scan(&count); err != nil {
 		posts.TotalCount = 0
 	}
 	posts.TotalCount = int(count)
@@ -265,45 +485,189 @@ func (p postRepo) ListPost(ctx context.Context, limit int, offset int, filter ma
 	return &posts, nil
 }
 
+// postTagsColumn selects the slugs of the tags of the posts in table.
+func postTagsColumn(table string) string {
+	return "ARRAY(SELECT tags.slug FROM post_tags JOIN tags ON tags.id = post_tags.tag_id WHERE post_tags.post_id = " + table + ".id ORDER BY tags.slug) AS tags"
+}
+
+// postTagsWhere matches the posts that have every tag of the comma separated
+// slugs, following synonyms.
+func postTagsWhere(slugs string) squirrel.And {
+	where := squirrel.And{}
+	for _, slug := range strings.Split(slugs, ",") {
+		where = append(where, squirrel.Expr("EXISTS (SELECT 1 FROM post_tags WHERE post_tags.post_id = posts.id AND post_tags.tag_id IN (?))", tagIds(slug)))
+	}
+
+	return where
+}
+
+// postOrder returns the keyset of a post sort with its columns prefixed by
+// table. Relevance needs a rank column; an unknown sort is newest first.
+func postOrder(sort, table string) keyset {
+	order := keyset{name: sort, createdAt: table + "created_at", id: table + "id", desc: true}
+	switch sort {
+	case entity.SortOldest:
+		order.desc = false
+	case entity.SortViews:
+		order.key = table + "views"
+	case entity.SortComments:
+		order.key = table + "comments_count"
+	case entity.SortPriceAsc:
+		order.key, order.desc = table+"price", false
+	case entity.SortPriceDesc:
+		order.key = table + "price"
+	case entity.SortRating:
+		order.key = table + "rating_score"
+	case entity.SortRelevance:
+		order.key = table + "rank"
+	default:
+		order.name = entity.SortNewest
+	}
+
+	return order
+}
+
+// postCursor returns the function building the cursor of a post in order.
+func postCursor(order keyset) func(*entity.Post) *entity.Cursor {
+	return func(post *entity.Post) *entity.Cursor {
+		cursor := &entity.Cursor{
+			CreatedAt: post.CreatedAt,
+			Id:        post.Id,
+		}
+
+		var key float64
+		switch order.name {
+		case entity.SortViews:
+			key = float64(post.Views)
+		case entity.SortComments:
+			key = float64(post.Comments)
+		case entity.SortPriceAsc, entity.SortPriceDesc:
+			key = post.Price
+		case entity.SortRating:
+			key = post.RatingScore
+		case entity.SortRelevance:
+			key = post.Rank
+		default:
+			return cursor
+		}
+		cursor.Key = &key
+
+		return cursor
+	}
+}
+
+// Search matches req.Filter["theme"] against the full-text index of posts and
+// orders the results by rank. Each result carries a highlighted snippet of
+// the text that matched. The query, the indexed text and the science and
+// category filters are compared in their transliterated form, so Latin and
+// Cyrillic spellings find the same posts. Plain word queries also match themes
+// and sciences by trigram similarity, which tolerates typos.
 func (p postRepo) Search(ctx context.Context, req *entity.ListReq) (*entity.PostListRes, error) {
 	ctx, span := otlp.Start(ctx, serviceNamePostsService, spanNamePostsService+"Search")
 	defer span.End()
 
-	terms := strings.Fields(req.Filter["theme"])
-	likeClause := "%" + strings.Join(terms, "%") + "%"
+	tsQuery := search.TSQuery(translit.Normalize(req.Filter["theme"]))
+	fuzzy := search.Plain(translit.Normalize(req.Filter["theme"]))
+	where := p.searchWhere(req.Filter)
+
 	queryBuilder := p.db.Sq.Builder.
-	Select(
-		"posts.id",
-		"posts.user_id",
-		"posts.theme",
-		"posts.path",
-		"posts.views",
-		"posts.science",
-		"category.name",
-		"posts.price_status",
-		"posts.price",
-		"posts.created_at",
-		"posts.updated_at",
-	).From(p.tableName).
-	Join(categoryServiceTableName + " on posts.category_id = category.id")
-	for key, value := range req.Filter{
-		if key == "theme"{
-			queryBuilder = queryBuilder.Where(p.db.Sq.ILike("posts." + key, likeClause))
-		}else if key == "category_id"{
-			queryBuilder = queryBuilder.Where(p.db.Sq.Equal("posts." + key, value))
-		}else if key == "science"{
-			queryBuilder = queryBuilder.Where(p.db.Sq.Equal("posts." + key, value))
-		}else if key == "price_status"{
-			queryBuilder = queryBuilder.Where(p.db.Sq.Equal("posts." + key, value))
+		Select(
+			"posts.id",
+			"posts.user_id",
+			"posts.theme",
+			"posts.path",
+			"posts.pdf_path",
+			"posts.previews",
+			"posts.file_hash",
+			"posts.version",
+			"posts.pages",
+			"posts.views",
+			"posts.guest_views",
+			"posts.downloads",
+			"posts.comments_count",
+			"posts.rating_avg",
+			"posts.rating_count",
+			"posts.rating_score",
+			"posts.science",
+			"coalesce(posts.subject_id::text, '') AS subject_id",
+			"coalesce(posts.university_id::text, '') AS university_id",
+			"coalesce(posts.faculty_id::text, '') AS faculty_id",
+			"coalesce(posts.course_id::text, '') AS course_id",
+			"category.name AS category_name",
+			"posts.price_status",
+			"posts.price",
+			"posts.created_at",
+			"posts.updated_at",
+		).From(p.tableName).
+		Join(categoryServiceTableName + " on posts.category_id = category.id").
+		Where(where)
+
+	// the rank is computed one level down so the page can be cut by it.
+	// Without a query there is nothing to rank and results are newest first
+	sort := req.Sort
+	if tsQuery != "" {
+		queryBuilder = queryBuilder.
+			Column("ts_rank(posts.search_vector, to_tsquery('simple', ?)) + greatest(word_similarity(?, uz_normalize(posts.theme)), word_similarity(?, uz_normalize(posts.science)), 0) AS rank", tsQuery, fuzzy, fuzzy)
+		if sort == "" {
+			sort = entity.SortRelevance
+		}
+	} else {
+		queryBuilder = queryBuilder.Column("0::real AS rank")
+		if sort == entity.SortRelevance {
+			sort = entity.SortNewest
 		}
 	}
-	queryBuilder = queryBuilder.Where("deleted_at is null")
-
+	order := postOrder(sort, "ranked.")
+	pageBuilder, err := order.page(p.db.Sq.Builder.Select("ranked.*").FromSelect(queryBuilder, "ranked"), req)
+	if err != nil {
+		return nil, err
+	}
 
-	if req.Limit != 0 {
-		queryBuilder = queryBuilder.Limit(uint64(req.Limit)).Offset(uint64(req.Offset))
+	// snippets are only built for the page being returned. They show the
+	// original text, so the words are marked as typed and, when the text is
+	// in Latin script, in their canonical form too
+	headline := squirrel.Expr("''")
+	if tsQuery != "" {
+		headline = squirrel.Expr("ts_headline('simple', concat_ws(' ', result.theme, result.science, left(post_contents.text, 20000)), to_tsquery('simple', ?) || to_tsquery('simple', ?), 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5')",
+			tsQuery, search.TSQuery(req.Filter["theme"]))
 	}
-	sqlStr, args, err := queryBuilder.ToSql()
+	outerBuilder := p.db.Sq.Builder.
+		Select(
+			"result.id",
+			"result.user_id",
+			"result.theme",
+			"result.path",
+			"result.pdf_path",
+			"result.previews",
+			"result.file_hash",
+			"result.version",
+			"result.pages",
+			"result.views",
+			"result.guest_views",
+			"result.downloads",
+			"result.comments_count",
+			"result.rating_avg",
+			"result.rating_count",
+			"result.rating_score",
+			"result.science",
+			"result.subject_id",
+			"result.university_id",
+			"result.faculty_id",
+			"result.course_id",
+			postTagsColumn("result"),
+			"result.category_name",
+			"result.price_status",
+			"result.price",
+			"result.created_at",
+			"result.updated_at",
+			"result.rank",
+		).
+		Column(headline).
+		FromSelect(pageBuilder, "result").
+		LeftJoin(postContentsTableName + " on post_contents.post_id = result.id")
+	outerBuilder = outerBuilder.OrderBy(postOrder(sort, "result.").order(req.Cursor != nil && req.Cursor.Before)...)
+
+	sqlStr, args, err := outerBuilder.ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("SQL build error: %w", err)
 	}
@@ -321,31 +685,54 @@ func (p postRepo) Search(ctx context.Context, req *entity.ListReq) (*entity.Post
 			&post.UserId,
 			&post.Theme,
 			&post.Path,
+			&post.PdfPath,
+			&post.Previews,
+			&post.FileHash,
+			&post.Version,
+			&post.Pages,
 			&post.Views,
+			&post.GuestViews,
+			&post.Downloads,
+			&post.Comments,
+			&post.RatingAvg,
+			&post.RatingCount,
+			&post.RatingScore,
 			&post.Science,
+			&post.SubjectId,
+			&post.UniversityId,
+			&post.FacultyId,
+			&post.CourseId,
+			&post.Tags,
 			&post.CategoryId,
 			&post.PriceStatus,
 			&post.Price,
 			&post.CreatedAt,
 			&post.UpdatedAt,
+			&post.Rank,
+			&post.Headline,
 		)
 		if err != nil {
 			return nil, p.db.Error(err)
 		}
 		posts.Post = append(posts.Post, &post)
 	}
+	posts.Post, posts.NextCursor, posts.PrevCursor = cursors(order, posts.Post, req, postCursor(order))
+	if req.SkipCount {
+		return &posts, nil
+	}
+
 	var count uint64
 
-	queryBuilder = p.db.Sq.Builder.Select("COUNT(*)").
+	query, args, err := p.db.Sq.Builder.Select("COUNT(*)").
 		From(p.tableName).
-		Where("deleted_at is null")
-
-	query, _, err := queryBuilder.ToSql()
+		Join(categoryServiceTableName + " on posts.category_id = category.id").
+		Where(where).
+		ToSql()
 	if err != nil {
 		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "list"))
 	}
 
-	if err := p.db.QueryRow(ctx, query).Scan(&count); err != nil {
+	if err := p.db.QueryRow(ctx, query, args...).Scan(&count); err != nil {
 		posts.TotalCount = 0
 	}
 	posts.TotalCount = int(count)
@@ -354,64 +741,452 @@ func (p postRepo) Search(ctx context.Context, req *entity.ListReq) (*entity.Post
 
 }
 
-func (p postRepo) CheckUnique(ctx context.Context, UserId, PostId string) (bool, error) {
-	ctx, span := otlp.Start(ctx, serviceNamePostsService, spanNamePostsService+"checkUnique")
+// CreateViews stores a batch of view events and counts them on their posts.
+// Views a student or guest already has of a post, and views of posts that are
+// gone, are left out, so storing the same batch twice counts nothing twice. It returns how many views were stored.
+func (p postRepo) CreateViews(ctx context.Context, views []*entity.View) (int64, error) {
+	ctx, span := otlp.Start(ctx, serviceNamePostsService, spanNamePostsService+"CreateViews")
 	defer span.End()
 
-	queryBuilder := p.db.Sq.Builder.Select("COUNT(1)").
-		From(viewsTableName).
-		Where(squirrel.Eq{"post_id": PostId, "user_id": UserId})
+	if len(views) == 0 {
+		return 0, nil
+	}
+
+	userIds := make([]string, 0, len(views))
+	postIds := make([]string, 0, len(views))
+	anonymous := make([]bool, 0, len(views))
+	createdAt := make([]time.Time, 0, len(views))
+	for _, view := range views {
+		userIds = append(userIds, view.UserId)
+		postIds = append(postIds, view.PostId)
+		anonymous = append(anonymous, view.Anonymous)
+		createdAt = append(createdAt, view.CreatedAt)
+	}
 
-	query, args, err := queryBuilder.ToSql()
+	query := `
+WITH events AS (
+	SELECT DISTINCT ON (user_id, post_id) user_id, post_id, anonymous, created_at
+	FROM unnest($1::uuid[], $2::uuid[], $3::bool[], $4::timestamptz[]) AS events (user_id, post_id, anonymous, created_at)
+	ORDER BY user_id, post_id, created_at
+), inserted AS (
+	INSERT INTO views (user_id, post_id, anonymous, created_at)
+	SELECT events.user_id, events.post_id, events.anonymous, events.created_at
+	FROM events
+	JOIN posts ON posts.id = events.post_id
+	ON CONFLICT (user_id, post_id) DO NOTHING
+	RETURNING post_id, anonymous
+), counted AS (
+	UPDATE posts SET views = posts.views + counted.views, guest_views = posts.guest_views + counted.guest_views
+	FROM (SELECT post_id, count(*) AS views, count(*) FILTER (WHERE anonymous) AS guest_views FROM inserted GROUP BY post_id) counted
+	WHERE posts.id = counted.post_id
+	RETURNING counted.views
+)
+SELECT coalesce(sum(views), 0)::bigint FROM counted`
+
+	var stored int64
+	if err := p.db.QueryRow(ctx, query, userIds, postIds, anonymous, createdAt).Scan(&stored); err != nil {
+		return 0, p.db.Error(err)
+	}
+
+	return stored, nil
+}
+
+// CreateDownload stores a download and counts it on its post. Further
+// downloads of the post by the same student or guest that UTC day are left
+// out.
+func (p postRepo) CreateDownload(ctx context.Context, download *entity.Download) error {
+	ctx, span := otlp.Start(ctx, serviceNamePostsService, spanNamePostsService+"CreateDownload")
+	defer span.End()
 
+	query, args, err := p.db.Sq.Builder.Insert(downloadsTableName).SetMap(map[string]any{
+		"user_id":    download.UserId,
+		"post_id":    download.PostId,
+		"version":    download.Version,
+		"format":     download.Format,
+		"anonymous":  download.Anonymous,
+		"created_at": download.CreatedAt,
+	}).Suffix("ON CONFLICT (user_id, post_id, day) DO NOTHING").ToSql()
 	if err != nil {
-		return false, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", likeTableName, "checkUnique"))
+		return p.db.ErrSQLBuild(err, downloadsTableName+" create")
 	}
 
-	var count int
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		return p.db.Error(err)
+	}
+	defer tx.Rollback(ctx)
 
-	if err = p.db.QueryRow(ctx, query, args...).Scan(&count); err != nil {
-		return false, p.db.Error(err)
+	commandTag, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return p.db.Error(err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return nil
+	}
+	commandTag, err = tx.Exec(ctx, "UPDATE posts SET downloads = downloads + 1 WHERE id = $1", download.PostId)
+	if err != nil {
+		return p.db.Error(err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return entity.ErrorNotFound
+	}
 
+	if err = tx.Commit(ctx); err != nil {
+		return p.db.Error(err)
 	}
-	if count != 0 {
-		return true, nil
+
+	return nil
+}
+
+// DownloadStats counts a post's downloads, those guests made and the students
+// who made them, and its downloads per UTC day since since.
+func (p postRepo) DownloadStats(ctx context.Context, postId string, since time.Time) (*entity.DownloadStats, error) {
+	ctx, span := otlp.Start(ctx, serviceNamePostsService, spanNamePostsService+"DownloadStats")
+	defer span.End()
+
+	stats := entity.DownloadStats{
+		PostId: postId,
+		Since:  since,
+		Days:   []*entity.DownloadDay{},
+	}
+
+	query, args, err := p.db.Sq.Builder.
+		Select("count(*)", "count(*) FILTER (WHERE anonymous)", "count(DISTINCT user_id) FILTER (WHERE NOT anonymous)").
+		From(downloadsTableName).
+		Where(p.db.Sq.Equal("post_id", postId)).
+		ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, downloadsTableName+" stats")
+	}
+	if err = p.db.QueryRow(ctx, query, args...).Scan(&stats.Downloads, &stats.GuestDownloads, &stats.Downloaders); err != nil {
+		return nil, p.db.Error(err)
+	}
+
+	query, args, err = p.db.Sq.Builder.
+		Select("(created_at AT TIME ZONE 'UTC')::date AS day", "count(*)").
+		From(downloadsTableName).
+		Where(p.db.Sq.Equal("post_id", postId)).
+		Where("created_at >= ?", since).
+		GroupBy("day").
+		OrderBy("day").
+		ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, downloadsTableName+" stats")
+	}
+
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, p.db.Error(err)
 	}
-	return false, nil
+	defer rows.Close()
+
+	for rows.Next() {
+		var day entity.DownloadDay
+		if err = rows.Scan(&day.Date, &day.Downloads); err != nil {
+			return nil, p.db.Error(err)
+		}
+		stats.Days = append(stats.Days, &day)
+	}
+
+	return &stats, rows.Err()
 }
 
-func (p postRepo) CreateViews(ctx context.Context, userId, postId string) (bool, error) {
-	ctx, span := otlp.Start(ctx, serviceNamePostsService, spanNamePostsService+"CreateViews")
+// UpdatePdfPath records the PDF rendition of a post's file. It only applies
+// while the post is still at version, a revision uploaded in the meantime
+// leaves no rows to update.
+func (p postRepo) UpdatePdfPath(ctx context.Context, postId string, version int, pdfPath string) error {
+	ctx, span := otlp.Start(ctx, serviceNamePostsService, spanNamePostsService+"UpdatePdfPath")
 	defer span.End()
-	clauses := map[string]any{
-		"user_id": userId,
-		"post_id": postId,
+
+	sqlStr, args, err := p.db.Sq.Builder.
+		Update(p.tableName).
+		Set("pdf_path", pdfPath).
+		Where(p.db.Sq.Equal("id", postId)).
+		Where(p.db.Sq.Equal("version", version)).
+		Where("deleted_at IS NULL").
+		ToSql()
+	if err != nil {
+		return p.db.ErrSQLBuild(err, p.tableName+" update pdf path")
+	}
+
+	commandTag, err := p.db.Exec(ctx, sqlStr, args...)
+	if err != nil {
+		return p.db.Error(err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return entity.ErrorNotFound
 	}
-	query, args, err := p.db.Sq.Builder.Insert(viewsTableName).SetMap(clauses).ToSql()
+
+	return nil
+}
+
+// UpdatePreviews records the thumbnails of a post's file, like UpdatePdfPath
+// only while the post is still at version.
+func (p postRepo) UpdatePreviews(ctx context.Context, postId string, version int, previews []string) error {
+	ctx, span := otlp.Start(ctx, serviceNamePostsService, spanNamePostsService+"UpdatePreviews")
+	defer span.End()
+
+	sqlStr, args, err := p.db.Sq.Builder.
+		Update(p.tableName).
+		Set("previews", previews).
+		Where(p.db.Sq.Equal("id", postId)).
+		Where(p.db.Sq.Equal("version", version)).
+		Where("deleted_at IS NULL").
+		ToSql()
 	if err != nil {
-		return false, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "create"))
+		return p.db.ErrSQLBuild(err, p.tableName+" update previews")
 	}
-	_, err = p.db.Exec(ctx, query, args...)
+
+	commandTag, err := p.db.Exec(ctx, sqlStr, args...)
 	if err != nil {
-		return false, p.db.Error(err)
+		return p.db.Error(err)
 	}
 
-	return true, nil
+	if commandTag.RowsAffected() == 0 {
+		return entity.ErrorNotFound
+	}
+
+	return nil
 }
-func (p postRepo) UpdateViews(ctx context.Context, postId string) (bool, error) {
-	ctx, span := otlp.Start(ctx, serviceNamePostsService, spanNamePostsService+"UpdateViews")
+
+// UpdateContent stores the text extracted from the current file of a post. A
+// trigger copies the page count to the post and reindexes it for search. Text
+// extracted from a file the post no longer is at is dropped.
+func (p postRepo) UpdateContent(ctx context.Context, content *entity.PostContent) error {
+	ctx, span := otlp.Start(ctx, serviceNamePostsService, spanNamePostsService+"UpdateContent")
 	defer span.End()
 
-	query := `UPDATE posts SET views = views + 1 WHERE id = $1`
+	query := `INSERT INTO post_contents (post_id, text, pages, created_at, updated_at)
+		SELECT id, $3::text, $4::int, $5::timestamptz, $6::timestamptz FROM posts
+		WHERE id = $1 AND version = $2 AND deleted_at IS NULL
+		ON CONFLICT (post_id) DO UPDATE SET text = EXCLUDED.text, pages = EXCLUDED.pages, updated_at = EXCLUDED.updated_at`
 
-	commandTag, err := p.db.Exec(ctx, query, postId)
+	commandTag, err := p.db.Exec(ctx, query, content.PostId, content.Version, content.Text, content.Pages, content.CreatedAt, content.UpdatedAt)
 	if err != nil {
-		return false, p.db.Error(err)
+		return p.db.Error(err)
 	}
 
 	if commandTag.RowsAffected() == 0 {
-		return false, p.db.Error(fmt.Errorf("no sql rows"))
+		return entity.ErrorNotFound
+	}
+
+	return nil
+}
+
+// searchWhere builds the conditions of a search for the query and filters in
+// filter. Filters named in skip are left out, which lets a facet count the
+// values its own filter would hide.
+func (p postRepo) searchWhere(filter map[string]string, skip ...string) squirrel.And {
+	tsQuery := search.TSQuery(translit.Normalize(filter["theme"]))
+	fuzzy := search.Plain(translit.Normalize(filter["theme"]))
+
+	where := squirrel.And{squirrel.Expr("posts.deleted_at IS NULL")}
+	for key, value := range filter {
+		if slices.Contains(skip, key) {
+			continue
+		}
+		if key == "category_id" || key == "price_status" || key == "subject_id" || postPlacements[key] {
+			where = append(where, p.db.Sq.Equal("posts."+key, value))
+		} else if key == "category" {
+			where = append(where, squirrel.Expr("uz_normalize(category.name) = ?", translit.Normalize(value)))
+		} else if key == "science" {
+			where = append(where, squirrel.Expr("uz_normalize(posts.science) = ?", translit.Normalize(value)))
+		} else if key == "price_min" {
+			where = append(where, squirrel.GtOrEq{"posts.price": value})
+		} else if key == "price_max" {
+			where = append(where, squirrel.Lt{"posts.price": value})
+		} else if key == "tags" {
+			where = append(where, postTagsWhere(value))
+		}
+	}
+	if tsQuery != "" && fuzzy != "" {
+		// misspelled words still find themes and sciences that look alike
+		where = append(where, squirrel.Or{
+			squirrel.Expr("posts.search_vector @@ to_tsquery('simple', ?)", tsQuery),
+			squirrel.Expr("? <% uz_normalize(posts.theme)", fuzzy),
+			squirrel.Expr("? <% uz_normalize(posts.science)", fuzzy),
+		})
+	} else if tsQuery != "" {
+		where = append(where, squirrel.Expr("posts.search_vector @@ to_tsquery('simple', ?)", tsQuery))
+	}
+
+	return where
+}
+
+// SearchFacets counts the results of a search per science, category, free or
+// paid and price range. Every facet ignores its own filter, so the values a
+// client could switch to keep their counts.
+func (p postRepo) SearchFacets(ctx context.Context, req *entity.ListReq) (*entity.SearchFacets, error) {
+	ctx, span := otlp.Start(ctx, serviceNamePostsService, spanNamePostsService+"SearchFacets")
+	defer span.End()
+
+	facets := entity.SearchFacets{}
+
+	// Latin and Cyrillic spellings of a science are counted together
+	science, err := p.facetValues(ctx, p.db.Sq.Builder.
+		Select("min(posts.science)", "min(posts.science)", "count(*)").
+		From(p.tableName).
+		Join(categoryServiceTableName+" on posts.category_id = category.id").
+		Where(p.searchWhere(req.Filter, "science", "subject_id")).
+		Where("posts.science <> ''").
+		GroupBy("uz_normalize(posts.science)").
+		OrderBy("count(*) DESC", "min(posts.science)").
+		Limit(facetLimit))
+	if err != nil {
+		return nil, err
+	}
+	facets.Science = science
+
+	category, err := p.facetValues(ctx, p.db.Sq.Builder.
+		Select("category.id", "category.name", "count(*)").
+		From(p.tableName).
+		Join(categoryServiceTableName+" on posts.category_id = category.id").
+		Where(p.searchWhere(req.Filter, "category", "category_id")).
+		GroupBy("category.id", "category.name").
+		OrderBy("count(*) DESC", "category.name").
+		Limit(facetLimit))
+	if err != nil {
+		return nil, err
+	}
+	facets.Category = category
+
+	queryBuilder := p.db.Sq.Builder.
+		Select("count(*) FILTER (WHERE NOT posts.price_status)", "count(*) FILTER (WHERE posts.price_status)").
+		From(p.tableName).
+		Join(categoryServiceTableName + " on posts.category_id = category.id").
+		Where(p.searchWhere(req.Filter, "price_status"))
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "facets"))
+	}
+	if err := p.db.QueryRow(ctx, query, args...).Scan(&facets.Free, &facets.Paid); err != nil {
+		return nil, p.db.Error(err)
+	}
+
+	// width_bucket numbers the ranges from 0, the last one has no upper bound
+	queryBuilder = p.db.Sq.Builder.
+		Select().
+		Column("width_bucket(posts.price, ?::float8[]) AS bucket", priceRangeBounds).
+		Column("count(*)").
+		From(p.tableName).
+		Join(categoryServiceTableName + " on posts.category_id = category.id").
+		Where(p.searchWhere(req.Filter, "price_min", "price_max")).
+		Where("posts.price_status").
+		GroupBy("bucket")
+	query, args, err = queryBuilder.ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "facets"))
+	}
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, p.db.Error(err)
+	}
+	defer rows.Close()
+
+	counts := make(map[int]int)
+	for rows.Next() {
+		var bucket, count int
+		if err := rows.Scan(&bucket, &count); err != nil {
+			return nil, p.db.Error(err)
+		}
+		counts[bucket] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, p.db.Error(err)
+	}
+
+	for i := 0; i <= len(priceRangeBounds); i++ {
+		priceRange := entity.PriceRange{Count: counts[i]}
+		if i > 0 {
+			priceRange.Min = priceRangeBounds[i-1]
+		}
+		if i < len(priceRangeBounds) {
+			priceRange.Max = priceRangeBounds[i]
+		}
+		facets.Price = append(facets.Price, &priceRange)
+	}
+
+	return &facets, nil
+}
+
+// facetValues reads value, name and count rows of a facet query.
+func (p postRepo) facetValues(ctx context.Context, queryBuilder squirrel.SelectBuilder) ([]*entity.FacetValue, error) {
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "facets"))
+	}
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, p.db.Error(err)
+	}
+	defer rows.Close()
+
+	values := []*entity.FacetValue{}
+	for rows.Next() {
+		var value entity.FacetValue
+		if err := rows.Scan(&value.Value, &value.Name, &value.Count); err != nil {
+			return nil, p.db.Error(err)
+		}
+		values = append(values, &value)
+	}
+
+	return values, rows.Err()
+}
+
+// Suggest completes a partly typed query with themes, sciences and category
+// names. Prefix matches come first, then similar spellings; ties go to the
+// more popular text.
+func (p postRepo) Suggest(ctx context.Context, query string, limit int) ([]*entity.Suggestion, error) {
+	ctx, span := otlp.Start(ctx, serviceNamePostsService, spanNamePostsService+"Suggest")
+	defer span.End()
+
+	normalized := translit.Normalize(strings.TrimSpace(query))
+	prefix := likeEscaper.Replace(normalized) + "%"
+
+	sqlStr := `
+		SELECT text, kind, score FROM (
+			SELECT theme AS text, 'theme' AS kind,
+				word_similarity($1, uz_normalize(theme)) + CASE WHEN uz_normalize(theme) LIKE $2 THEN 1 ELSE 0 END AS score,
+				sum(views) AS popularity
+			FROM posts
+			WHERE deleted_at IS NULL AND (uz_normalize(theme) LIKE $2 OR $1 <% uz_normalize(theme))
+			GROUP BY theme
+			UNION ALL
+			SELECT science, 'science',
+				word_similarity($1, uz_normalize(science)) + CASE WHEN uz_normalize(science) LIKE $2 THEN 1 ELSE 0 END,
+				count(*)
+			FROM posts
+			WHERE deleted_at IS NULL AND (uz_normalize(science) LIKE $2 OR $1 <% uz_normalize(science))
+			GROUP BY science
+			UNION ALL
+			SELECT name, 'category',
+				word_similarity($1, uz_normalize(name)) + CASE WHEN uz_normalize(name) LIKE $2 THEN 1 ELSE 0 END,
+				(SELECT count(*) FROM posts WHERE posts.category_id = category.id AND posts.deleted_at IS NULL)
+			FROM category
+			WHERE deleted_at IS NULL AND (uz_normalize(name) LIKE $2 OR $1 <% uz_normalize(name))
+		) suggestions
+		ORDER BY score DESC, popularity DESC, text
+		LIMIT $3`
+
+	rows, err := p.db.Query(ctx, sqlStr, normalized, prefix, limit)
+	if err != nil {
+		return nil, p.db.Error(err)
+	}
+	defer rows.Close()
+
+	var suggestions []*entity.Suggestion
+	for rows.Next() {
+		var suggestion entity.Suggestion
+		if err = rows.Scan(
+			&suggestion.Text,
+			&suggestion.Kind,
+			&suggestion.Score,
+		); err != nil {
+			return nil, p.db.Error(err)
+		}
+		suggestions = append(suggestions, &suggestion)
 	}
 
-	return true, nil
+	return suggestions, rows.Err()
 }
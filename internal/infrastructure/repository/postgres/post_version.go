@@ -0,0 +1,185 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"univer/internal/entity"
+	"univer/internal/pkg/otlp"
+	postgres "univer/internal/pkg/storage"
+
+	"github.com/Masterminds/squirrel"
+)
+
+const (
+	postVersionServiceTableName   = "post_versions"
+	serviceNamePostVersionService = "postVersionServiceRepo"
+	spanNamePostVersionService    = "postVersionSpanRepo"
+)
+
+type postVersionRepo struct {
+	tableName string
+	db        *postgres.PostgresDB
+}
+
+func NewPostVersionRepo(db *postgres.PostgresDB) *postVersionRepo {
+	return &postVersionRepo{
+		tableName: postVersionServiceTableName,
+		db:        db,
+	}
+}
+
+func (p *postVersionRepo) postVersionsSelectQueryPrefix() squirrel.SelectBuilder {
+	return p.db.Sq.Builder.
+		Select(
+			"id",
+			"post_id",
+			"version",
+			"path",
+			"file_hash",
+			"pdf_path",
+			"previews",
+			"created_at",
+		).From(p.tableName)
+}
+
+// ReviseFile archives the current file of a post as archived and points the
+// post at the file in post, in one transaction. The post must still be at
+// archived.Version; a concurrent revision archives the same version number
+// and fails with a conflict.
+func (p postVersionRepo) ReviseFile(ctx context.Context, archived *entity.PostVersion, post *entity.Post) error {
+	ctx, span := otlp.Start(ctx, serviceNamePostVersionService, spanNamePostVersionService+"ReviseFile")
+	defer span.End()
+
+	archivedPreviews := archived.Previews
+	if archivedPreviews == nil {
+		archivedPreviews = []string{}
+	}
+	previews := post.Previews
+	if previews == nil {
+		previews = []string{}
+	}
+
+	insertQuery, insertArgs, err := p.db.Sq.Builder.Insert(p.tableName).SetMap(map[string]any{
+		"id":         archived.Id,
+		"post_id":    archived.PostId,
+		"version":    archived.Version,
+		"path":       archived.Path,
+		"file_hash":  archived.FileHash,
+		"pdf_path":   archived.PdfPath,
+		"previews":   archivedPreviews,
+		"created_at": archived.CreatedAt,
+	}).ToSql()
+	if err != nil {
+		return p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "create"))
+	}
+
+	updateQuery, updateArgs, err := p.db.Sq.Builder.Update("posts").SetMap(map[string]any{
+		"path":       post.Path,
+		"file_hash":  post.FileHash,
+		"pdf_path":   post.PdfPath,
+		"previews":   previews,
+		"version":    post.Version,
+		"updated_at": post.UpdatedAt,
+	}).
+		Where(p.db.Sq.Equal("id", post.Id)).
+		Where(p.db.Sq.Equal("version", archived.Version)).
+		Where("deleted_at IS NULL").
+		ToSql()
+	if err != nil {
+		return p.db.ErrSQLBuild(err, "posts update file")
+	}
+
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		return p.db.Error(err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err = tx.Exec(ctx, insertQuery, insertArgs...); err != nil {
+		return p.db.Error(err)
+	}
+
+	commandTag, err := tx.Exec(ctx, updateQuery, updateArgs...)
+	if err != nil {
+		return p.db.Error(err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return entity.ErrorNotFound
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return p.db.Error(err)
+	}
+
+	return nil
+}
+
+func (p postVersionRepo) GetPostVersion(ctx context.Context, postId string, version int) (*entity.PostVersion, error) {
+	ctx, span := otlp.Start(ctx, serviceNamePostVersionService, spanNamePostVersionService+"GetPostVersion")
+	defer span.End()
+
+	var postVersion entity.PostVersion
+
+	query, args, err := p.postVersionsSelectQueryPrefix().
+		Where(squirrel.Eq{"post_id": postId, "version": version}).
+		ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "get"))
+	}
+
+	if err = p.db.QueryRow(ctx, query, args...).Scan(
+		&postVersion.Id,
+		&postVersion.PostId,
+		&postVersion.Version,
+		&postVersion.Path,
+		&postVersion.FileHash,
+		&postVersion.PdfPath,
+		&postVersion.Previews,
+		&postVersion.CreatedAt,
+	); err != nil {
+		return nil, p.db.Error(err)
+	}
+
+	return &postVersion, nil
+}
+
+func (p postVersionRepo) ListPostVersion(ctx context.Context, postId string) ([]*entity.PostVersion, error) {
+	ctx, span := otlp.Start(ctx, serviceNamePostVersionService, spanNamePostVersionService+"ListPostVersion")
+	defer span.End()
+
+	query, args, err := p.postVersionsSelectQueryPrefix().
+		Where(p.db.Sq.Equal("post_id", postId)).
+		OrderBy("version DESC").
+		ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "list"))
+	}
+
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, p.db.Error(err)
+	}
+	defer rows.Close()
+
+	var versions []*entity.PostVersion
+	for rows.Next() {
+		var postVersion entity.PostVersion
+
+		if err = rows.Scan(
+			&postVersion.Id,
+			&postVersion.PostId,
+			&postVersion.Version,
+			&postVersion.Path,
+			&postVersion.FileHash,
+			&postVersion.PdfPath,
+			&postVersion.Previews,
+			&postVersion.CreatedAt,
+		); err != nil {
+			return nil, p.db.Error(err)
+		}
+
+		versions = append(versions, &postVersion)
+	}
+
+	return versions, rows.Err()
+}
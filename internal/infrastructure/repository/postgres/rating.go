@@ -0,0 +1,186 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"univer/internal/entity"
+	"univer/internal/pkg/otlp"
+	postgres "univer/internal/pkg/storage"
+
+	"github.com/Masterminds/squirrel"
+)
+
+const (
+	ratingServiceTableName   = "ratings"
+	serviceNameRatingService = "ratingServiceRepo"
+	spanNameRatingService    = "ratingSpanRepo"
+)
+
+type ratingRepo struct {
+	tableName string
+	db        *postgres.PostgresDB
+}
+
+func NewRatingRepo(db *postgres.PostgresDB) *ratingRepo {
+	return &ratingRepo{
+		tableName: ratingServiceTableName,
+		db:        db,
+	}
+}
+
+func (p *ratingRepo) ratingsSelectQueryPrefix() squirrel.SelectBuilder {
+	return p.db.Sq.Builder.
+		Select(
+			"post_id",
+			"user_id",
+			"stars",
+			"review",
+			"created_at",
+			"updated_at",
+		).From(p.tableName)
+}
+
+// SaveRating creates the user's rating of a post or replaces the one they
+// gave before. The post's average is kept up to date by a trigger.
+func (p ratingRepo) SaveRating(ctx context.Context, rating *entity.Rating) (*entity.Rating, error) {
+	ctx, span := otlp.Start(ctx, serviceNameRatingService, spanNameRatingService+"SaveRating")
+	defer span.End()
+
+	data := map[string]any{
+		"post_id":    rating.PostId,
+		"user_id":    rating.UserId,
+		"stars":      rating.Stars,
+		"review":     rating.Review,
+		"created_at": rating.CreatedAt,
+		"updated_at": rating.UpdatedAt,
+	}
+	query, args, err := p.db.Sq.Builder.Insert(p.tableName).
+		SetMap(data).
+		Suffix("ON CONFLICT (post_id, user_id) DO UPDATE SET stars = EXCLUDED.stars, review = EXCLUDED.review, updated_at = EXCLUDED.updated_at RETURNING created_at").
+		ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "save"))
+	}
+
+	if err = p.db.QueryRow(ctx, query, args...).Scan(&rating.CreatedAt); err != nil {
+		return nil, p.db.Error(err)
+	}
+
+	return rating, nil
+}
+
+func (p ratingRepo) GetRating(ctx context.Context, postId, userId string) (*entity.Rating, error) {
+	ctx, span := otlp.Start(ctx, serviceNameRatingService, spanNameRatingService+"GetRating")
+	defer span.End()
+
+	query, args, err := p.ratingsSelectQueryPrefix().
+		Where(p.db.Sq.Equal("post_id", postId)).
+		Where(p.db.Sq.Equal("user_id", userId)).
+		ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "get"))
+	}
+
+	var rating entity.Rating
+	if err = p.db.QueryRow(ctx, query, args...).Scan(
+		&rating.PostId,
+		&rating.UserId,
+		&rating.Stars,
+		&rating.Review,
+		&rating.CreatedAt,
+		&rating.UpdatedAt,
+	); err != nil {
+		return nil, p.db.Error(err)
+	}
+
+	return &rating, nil
+}
+
+func (p ratingRepo) DeleteRating(ctx context.Context, postId, userId string) error {
+	ctx, span := otlp.Start(ctx, serviceNameRatingService, spanNameRatingService+"DeleteRating")
+	defer span.End()
+
+	query, args, err := p.db.Sq.Builder.Delete(p.tableName).
+		Where(p.db.Sq.Equal("post_id", postId)).
+		Where(p.db.Sq.Equal("user_id", userId)).
+		ToSql()
+	if err != nil {
+		return p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "delete"))
+	}
+
+	commandTag, err := p.db.Exec(ctx, query, args...)
+	if err != nil {
+		return p.db.Error(err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return entity.ErrorNotFound
+	}
+
+	return nil
+}
+
+// ListRating returns the ratings of req.Filter["post_id"], newest first.
+func (p ratingRepo) ListRating(ctx context.Context, req *entity.ListReq) (*entity.RatingListRes, error) {
+	ctx, span := otlp.Start(ctx, serviceNameRatingService, spanNameRatingService+"ListRating")
+	defer span.End()
+
+	var ratings entity.RatingListRes
+
+	where := p.db.Sq.Equal("post_id", req.Filter["post_id"])
+	order := keyset{createdAt: "created_at", id: "user_id", desc: true}
+	queryBuilder, err := order.page(p.ratingsSelectQueryPrefix().Where(where), req)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "list"))
+	}
+
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, p.db.Error(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rating entity.Rating
+		if err = rows.Scan(
+			&rating.PostId,
+			&rating.UserId,
+			&rating.Stars,
+			&rating.Review,
+			&rating.CreatedAt,
+			&rating.UpdatedAt,
+		); err != nil {
+			return nil, p.db.Error(err)
+		}
+
+		ratings.Rating = append(ratings.Rating, &rating)
+	}
+	ratings.Rating, ratings.NextCursor, ratings.PrevCursor = cursors(order, ratings.Rating, req, func(rating *entity.Rating) *entity.Cursor {
+		return &entity.Cursor{
+			CreatedAt: rating.CreatedAt,
+			Id:        rating.UserId,
+		}
+	})
+	if req.SkipCount {
+		return &ratings, nil
+	}
+
+	query, args, err = p.db.Sq.Builder.Select("COUNT(*)").
+		From(p.tableName).
+		Where(where).
+		ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "list"))
+	}
+
+	if err := p.db.QueryRow(ctx, query, args...).Scan(&ratings.TotalCount); err != nil {
+		return nil, p.db.Error(err)
+	}
+
+	return &ratings, nil
+}
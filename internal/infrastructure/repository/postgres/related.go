@@ -0,0 +1,185 @@
+package postgres
+
+import (
+	"context"
+	"time"
+	"univer/internal/entity"
+	"univer/internal/pkg/otlp"
+	postgres "univer/internal/pkg/storage"
+)
+
+const (
+	relatedPostsTableName     = "related_posts"
+	serviceNameRelatedService = "relatedServiceRepo"
+	spanNameRelatedService    = "relatedSpanRepo"
+
+	// relatedMinCoViews is how many students must have viewed two posts for
+	// them to be related; a single shared viewer is mostly noise.
+	relatedMinCoViews = 2
+	// relatedMaxUserViews leaves out students who viewed more posts than this,
+	// crawlers mostly, whose views relate everything to everything.
+	relatedMaxUserViews = 500
+)
+
+// computeRelatedQuery relates every pair of posts viewed or downloaded by the
+// same students since $2, scores a pair by co-viewers / sqrt(viewers of a *
+// viewers of b) and keeps the $5 best of every post. Views recorded before
+// they had a time count too.
+const computeRelatedQuery = `
+WITH viewed AS (
+	SELECT events.user_id, events.post_id
+	FROM (
+		SELECT user_id, post_id FROM views WHERE created_at IS NULL OR created_at >= $2
+		UNION
+		SELECT user_id, post_id FROM downloads WHERE created_at >= $2
+	) events
+	JOIN posts ON posts.id = events.post_id AND posts.deleted_at IS NULL
+), viewers AS (
+	SELECT user_id FROM viewed GROUP BY user_id HAVING count(*) BETWEEN 2 AND $3
+), counts AS (
+	SELECT post_id, count(*) AS viewers FROM viewed GROUP BY post_id
+), pairs AS (
+	SELECT a.post_id, b.post_id AS related_id, count(*) AS co_views
+	FROM viewers
+	JOIN viewed a ON a.user_id = viewers.user_id
+	JOIN viewed b ON b.user_id = viewers.user_id AND b.post_id <> a.post_id
+	GROUP BY a.post_id, b.post_id
+	HAVING count(*) >= $4
+), scored AS (
+	SELECT pairs.post_id, pairs.related_id, pairs.co_views,
+		pairs.co_views / sqrt(a.viewers::float8 * b.viewers) AS score
+	FROM pairs
+	JOIN counts a ON a.post_id = pairs.post_id
+	JOIN counts b ON b.post_id = pairs.related_id
+)
+INSERT INTO related_posts (post_id, related_id, score, co_views, computed_at)
+SELECT post_id, related_id, score, co_views, $1
+FROM (
+	SELECT *, row_number() OVER (PARTITION BY post_id ORDER BY score DESC, co_views DESC, related_id) AS position
+	FROM scored
+) ranked
+WHERE position <= $5`
+
+// listSimilarQuery returns the live posts of the same subject and then of the
+// same category as $1, most viewed first, leaving out the ids in $2.
+const listSimilarQuery = `
+SELECT posts.id::text,
+	CASE WHEN posts.subject_id = source.subject_id THEN 'subject' ELSE 'category' END
+FROM posts
+JOIN posts AS source ON source.id = $1
+WHERE posts.deleted_at IS NULL
+	AND posts.id <> source.id
+	AND (posts.subject_id = source.subject_id OR posts.category_id = source.category_id)
+	AND NOT posts.id::text = ANY($2)
+ORDER BY (posts.subject_id = source.subject_id) IS TRUE DESC, posts.views DESC, posts.id
+LIMIT $3`
+
+type relatedRepo struct {
+	db *postgres.PostgresDB
+}
+
+func NewRelatedRepo(db *postgres.PostgresDB) *relatedRepo {
+	return &relatedRepo{
+		db: db,
+	}
+}
+
+// ComputeRelated replaces the related posts of every post with ones computed
+// from the views since since, keeping limit of each. When another instance
+// is computing them already this one leaves it to it.
+func (p relatedRepo) ComputeRelated(ctx context.Context, since, now time.Time, limit int) error {
+	ctx, span := otlp.Start(ctx, serviceNameRelatedService, spanNameRelatedService+"ComputeRelated")
+	defer span.End()
+
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		return p.db.Error(err)
+	}
+	defer tx.Rollback(ctx)
+
+	var locked bool
+	if err = tx.QueryRow(ctx, "SELECT pg_try_advisory_xact_lock(hashtext('related_posts'))").Scan(&locked); err != nil {
+		return p.db.Error(err)
+	}
+	if !locked {
+		return nil
+	}
+
+	if _, err = tx.Exec(ctx, "DELETE FROM related_posts"); err != nil {
+		return p.db.Error(err)
+	}
+	_, err = tx.Exec(ctx, computeRelatedQuery,
+		now, since, relatedMaxUserViews, relatedMinCoViews, limit)
+	if err != nil {
+		return p.db.Error(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return p.db.Error(err)
+	}
+
+	return nil
+}
+
+// ListRelated returns the posts related to a post by their viewers, most
+// similar first.
+func (p relatedRepo) ListRelated(ctx context.Context, postId string, limit int) ([]*entity.RelatedPost, error) {
+	ctx, span := otlp.Start(ctx, serviceNameRelatedService, spanNameRelatedService+"ListRelated")
+	defer span.End()
+
+	query, args, err := p.db.Sq.Builder.
+		Select("related_id", "score", "co_views").
+		From(relatedPostsTableName).
+		Where(p.db.Sq.Equal("post_id", postId)).
+		OrderBy("score DESC", "co_views DESC", "related_id").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, relatedPostsTableName+" list")
+	}
+
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, p.db.Error(err)
+	}
+	defer rows.Close()
+
+	var related []*entity.RelatedPost
+	for rows.Next() {
+		post := entity.RelatedPost{Post: &entity.Post{}, Source: entity.RelatedViews}
+		if err = rows.Scan(&post.Post.Id, &post.Score, &post.CoViews); err != nil {
+			return nil, p.db.Error(err)
+		}
+		related = append(related, &post)
+	}
+
+	return related, rows.Err()
+}
+
+// ListSimilar returns up to limit posts of the same subject, then of the same
+// category as a post, leaving out the ones in exclude.
+func (p relatedRepo) ListSimilar(ctx context.Context, postId string, exclude []string, limit int) ([]*entity.RelatedPost, error) {
+	ctx, span := otlp.Start(ctx, serviceNameRelatedService, spanNameRelatedService+"ListSimilar")
+	defer span.End()
+
+	if exclude == nil {
+		exclude = []string{}
+	}
+
+	rows, err := p.db.Query(ctx, listSimilarQuery, postId, exclude, limit)
+	if err != nil {
+		return nil, p.db.Error(err)
+	}
+	defer rows.Close()
+
+	var similar []*entity.RelatedPost
+	for rows.Next() {
+		post := entity.RelatedPost{Post: &entity.Post{}}
+		if err = rows.Scan(&post.Post.Id, &post.Source); err != nil {
+			return nil, p.db.Error(err)
+		}
+		similar = append(similar, &post)
+	}
+
+	return similar, rows.Err()
+}
@@ -0,0 +1,267 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"univer/internal/entity"
+	"univer/internal/pkg/otlp"
+	postgres "univer/internal/pkg/storage"
+	"univer/internal/pkg/translit"
+
+	"github.com/Masterminds/squirrel"
+)
+
+const (
+	subjectServiceTableName   = "subjects"
+	subjectMappingTableName   = "subject_mapping_report"
+	serviceNameSubjectService = "subjectServiceRepo"
+	spanNameSubjectService    = "subjectSpanRepo"
+)
+
+type subjectRepo struct {
+	tableName string
+	db        *postgres.PostgresDB
+}
+
+func NewSubjectRepo(db *postgres.PostgresDB) *subjectRepo {
+	return &subjectRepo{
+		tableName: subjectServiceTableName,
+		db:        db,
+	}
+}
+
+func (p *subjectRepo) subjectSelectQueryPrefix() squirrel.SelectBuilder {
+	return p.db.Sq.Builder.
+		Select(
+			"id",
+			"name",
+			"created_at",
+			"updated_at",
+		).From(p.tableName)
+}
+
+func (p subjectRepo) CreateSubject(ctx context.Context, subject *entity.Subject) (*entity.Subject, error) {
+	ctx, span := otlp.Start(ctx, serviceNameSubjectService, spanNameSubjectService+"CreateSubject")
+	defer span.End()
+
+	data := map[string]any{
+		"id":         subject.Id,
+		"name":       subject.Name,
+		"created_at": subject.CreatedAt,
+		"updated_at": subject.UpdatedAt,
+	}
+	query, args, err := p.db.Sq.Builder.Insert(p.tableName).SetMap(data).ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "create"))
+	}
+
+	_, err = p.db.Exec(ctx, query, args...)
+	if err != nil {
+		return nil, p.db.Error(err)
+	}
+
+	return subject, nil
+}
+
+// UpdateSubject renames a subject. The posts of the subject show its name,
+// so they are renamed with it.
+func (p subjectRepo) UpdateSubject(ctx context.Context, subject *entity.Subject) (*entity.Subject, error) {
+	ctx, span := otlp.Start(ctx, serviceNameSubjectService, spanNameSubjectService+"UpdateSubject")
+	defer span.End()
+
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		return nil, p.db.Error(err)
+	}
+	defer tx.Rollback(ctx)
+
+	commandTag, err := tx.Exec(ctx, "UPDATE subjects SET name = $2, updated_at = $3 WHERE id = $1 AND deleted_at IS NULL", subject.Id, subject.Name, subject.UpdatedAt)
+	if err != nil {
+		return nil, p.db.Error(err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return nil, entity.ErrorNotFound
+	}
+
+	if _, err = tx.Exec(ctx, "UPDATE posts SET science = $2 WHERE subject_id = $1", subject.Id, subject.Name); err != nil {
+		return nil, p.db.Error(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, p.db.Error(err)
+	}
+
+	return subject, nil
+}
+
+// DeleteSubject deletes a subject no live post uses.
+func (p subjectRepo) DeleteSubject(ctx context.Context, req *entity.DeleteReq) error {
+	ctx, span := otlp.Start(ctx, serviceNameSubjectService, spanNameSubjectService+"DeleteSubject")
+	defer span.End()
+
+	var used bool
+	if err := p.db.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM posts WHERE subject_id = $1 AND deleted_at IS NULL)", req.Id).Scan(&used); err != nil {
+		return p.db.Error(err)
+	}
+	if used {
+		return entity.ErrorSubjectInUse
+	}
+
+	sqlStr, args, err := p.db.Sq.Builder.
+		Update(p.tableName).
+		Set("deleted_at", req.DeletedAt).
+		Where(p.db.Sq.Equal("id", req.Id)).
+		Where("deleted_at IS NULL").
+		ToSql()
+	if err != nil {
+		return p.db.ErrSQLBuild(err, p.tableName+" delete")
+	}
+
+	commandTag, err := p.db.Exec(ctx, sqlStr, args...)
+	if err != nil {
+		return p.db.Error(err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return entity.ErrorNotFound
+	}
+
+	return nil
+}
+
+// GetSubject finds a subject by id or by name. Names are compared in their
+// transliterated form, so Latin and Cyrillic spellings find the same subject.
+func (p subjectRepo) GetSubject(ctx context.Context, params map[string]string) (*entity.Subject, error) {
+	ctx, span := otlp.Start(ctx, serviceNameSubjectService, spanNameSubjectService+"GetSubject")
+	defer span.End()
+
+	var subject entity.Subject
+
+	queryBuilder := p.subjectSelectQueryPrefix().Where("deleted_at IS NULL")
+	for key, value := range params {
+		if key == "id" {
+			queryBuilder = queryBuilder.Where(p.db.Sq.Equal(key, value))
+		} else if key == "name" {
+			queryBuilder = queryBuilder.Where(squirrel.Expr("uz_normalize(name) = ?", translit.Normalize(value)))
+		}
+	}
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "get"))
+	}
+
+	if err = p.db.QueryRow(ctx, query, args...).Scan(
+		&subject.Id,
+		&subject.Name,
+		&subject.CreatedAt,
+		&subject.UpdatedAt,
+	); err != nil {
+		return nil, p.db.Error(err)
+	}
+
+	return &subject, nil
+}
+
+func (p subjectRepo) ListSubject(ctx context.Context, req *entity.ListReq) (*entity.ListSubjectRes, error) {
+	ctx, span := otlp.Start(ctx, serviceNameSubjectService, spanNameSubjectService+"ListSubject")
+	defer span.End()
+
+	var subjects entity.ListSubjectRes
+
+	order := keyset{createdAt: "created_at", id: "id"}
+	queryBuilder, err := order.page(p.subjectSelectQueryPrefix().Where("deleted_at IS NULL"), req)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "list"))
+	}
+
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, p.db.Error(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var subject entity.Subject
+		if err = rows.Scan(
+			&subject.Id,
+			&subject.Name,
+			&subject.CreatedAt,
+			&subject.UpdatedAt,
+		); err != nil {
+			return nil, p.db.Error(err)
+		}
+
+		subjects.Subject = append(subjects.Subject, &subject)
+	}
+	subjects.Subject, subjects.NextCursor, subjects.PrevCursor = cursors(order, subjects.Subject, req, func(subject *entity.Subject) *entity.Cursor {
+		return &entity.Cursor{
+			CreatedAt: subject.CreatedAt,
+			Id:        subject.Id,
+		}
+	})
+	if req.SkipCount {
+		return &subjects, nil
+	}
+
+	query, args, err = p.db.Sq.Builder.Select("COUNT(*)").
+		From(p.tableName).
+		Where("deleted_at IS NULL").
+		ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "list"))
+	}
+
+	if err := p.db.QueryRow(ctx, query, args...).Scan(&subjects.TotalCount); err != nil {
+		return nil, p.db.Error(err)
+	}
+
+	return &subjects, nil
+}
+
+// SubjectMapping returns the report of how free-text sciences were mapped to
+// subjects, the ones left unmapped first.
+func (p subjectRepo) SubjectMapping(ctx context.Context) ([]*entity.SubjectMapping, error) {
+	ctx, span := otlp.Start(ctx, serviceNameSubjectService, spanNameSubjectService+"SubjectMapping")
+	defer span.End()
+
+	query, args, err := p.db.Sq.Builder.
+		Select(
+			"science",
+			"coalesce(subject_id::text, '')",
+			"coalesce(subject_name, '')",
+			"posts",
+		).
+		From(subjectMappingTableName).
+		OrderBy("subject_id IS NOT NULL", "posts DESC", "science").
+		ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", subjectMappingTableName, "list"))
+	}
+
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, p.db.Error(err)
+	}
+	defer rows.Close()
+
+	var mappings []*entity.SubjectMapping
+	for rows.Next() {
+		var mapping entity.SubjectMapping
+		if err = rows.Scan(
+			&mapping.Science,
+			&mapping.SubjectId,
+			&mapping.SubjectName,
+			&mapping.Posts,
+		); err != nil {
+			return nil, p.db.Error(err)
+		}
+		mappings = append(mappings, &mapping)
+	}
+
+	return mappings, rows.Err()
+}
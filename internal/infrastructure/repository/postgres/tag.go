@@ -0,0 +1,377 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"univer/internal/entity"
+	"univer/internal/pkg/otlp"
+	postgres "univer/internal/pkg/storage"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	tagServiceTableName   = "tags"
+	tagSynonymsTableName  = "tag_synonyms"
+	postTagsTableName     = "post_tags"
+	serviceNameTagService = "tagServiceRepo"
+	spanNameTagService    = "tagSpanRepo"
+
+	// sortPopular orders tags by the number of their live posts
+	sortPopular = "popular"
+)
+
+type tagRepo struct {
+	tableName string
+	db        *postgres.PostgresDB
+}
+
+func NewTagRepo(db *postgres.PostgresDB) *tagRepo {
+	return &tagRepo{
+		tableName: tagServiceTableName,
+		db:        db,
+	}
+}
+
+// tagsSelectQueryPrefix selects tags with the number of their posts that are
+// not deleted.
+func (p *tagRepo) tagsSelectQueryPrefix() squirrel.SelectBuilder {
+	return p.db.Sq.Builder.
+		Select(
+			"tags.id",
+			"tags.slug",
+			"tags.name",
+			"(SELECT count(*) FROM post_tags JOIN posts ON posts.id = post_tags.post_id WHERE post_tags.tag_id = tags.id AND posts.deleted_at IS NULL) AS posts",
+			"tags.created_at",
+			"tags.updated_at",
+		).From(p.tableName)
+}
+
+// setPostTagsQuery replaces the tags of post $1 with the tag ids in $2.
+const setPostTagsQuery = `
+	WITH removed AS (
+		DELETE FROM post_tags WHERE post_id = $1 AND NOT (tag_id = ANY($2::uuid[]))
+	)
+	INSERT INTO post_tags (post_id, tag_id)
+	SELECT $1, unnest($2::uuid[])
+	ON CONFLICT (post_id, tag_id) DO NOTHING`
+
+// tagIds matches the id of the tag with slug or with slug as a synonym.
+func tagIds(slug string) squirrel.Sqlizer {
+	return squirrel.Expr("SELECT id FROM tags WHERE slug = ? UNION ALL SELECT tag_id FROM tag_synonyms WHERE synonym = ?", slug, slug)
+}
+
+// ResolveTags returns the tags with the slugs of tags, following synonyms.
+// Tags that do not exist yet are created with the id and name given.
+func (p tagRepo) ResolveTags(ctx context.Context, tags []*entity.Tag) ([]*entity.Tag, error) {
+	ctx, span := otlp.Start(ctx, serviceNameTagService, spanNameTagService+"ResolveTags")
+	defer span.End()
+
+	// a tag inserted here is not visible to the other branches of the same
+	// statement, so exactly one of them returns a row
+	query := `
+		WITH synonym AS (
+			SELECT tag_id FROM tag_synonyms WHERE synonym = $2
+		), inserted AS (
+			INSERT INTO tags (id, slug, name, created_at, updated_at)
+			SELECT $1, $2, $3, $4, $4 WHERE NOT EXISTS (SELECT 1 FROM synonym)
+			ON CONFLICT (slug) DO NOTHING
+			RETURNING id, slug, name, created_at, updated_at
+		)
+		SELECT id, slug, name, created_at, updated_at FROM inserted
+		UNION ALL
+		SELECT id, slug, name, created_at, updated_at FROM tags WHERE id = (SELECT tag_id FROM synonym)
+		UNION ALL
+		SELECT id, slug, name, created_at, updated_at FROM tags WHERE slug = $2 AND NOT EXISTS (SELECT 1 FROM synonym)`
+
+	resolved := make([]*entity.Tag, 0, len(tags))
+	for _, tag := range tags {
+		var result entity.Tag
+		scan := func() error {
+			return p.db.QueryRow(ctx, query, tag.Id, tag.Slug, tag.Name, tag.CreatedAt).Scan(
+				&result.Id,
+				&result.Slug,
+				&result.Name,
+				&result.CreatedAt,
+				&result.UpdatedAt,
+			)
+		}
+		err := scan()
+		if errors.Is(err, pgx.ErrNoRows) {
+			// a concurrent request inserted the slug after this statement
+			// took its snapshot, so the insert did nothing and the select
+			// could not see the winner; a second run sees it
+			err = scan()
+		}
+		if err != nil {
+			return nil, p.db.Error(err)
+		}
+		resolved = append(resolved, &result)
+	}
+
+	return resolved, nil
+}
+
+// GetTag returns the tag with slug, or the one slug is a synonym of, together
+// with its synonyms.
+func (p tagRepo) GetTag(ctx context.Context, slug string) (*entity.Tag, error) {
+	ctx, span := otlp.Start(ctx, serviceNameTagService, spanNameTagService+"GetTag")
+	defer span.End()
+
+	query, args, err := p.tagsSelectQueryPrefix().
+		Column("ARRAY(SELECT synonym FROM tag_synonyms WHERE tag_synonyms.tag_id = tags.id ORDER BY synonym)").
+		Where(squirrel.Expr("tags.id IN (?)", tagIds(slug))).
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "get"))
+	}
+
+	var tag entity.Tag
+	if err = p.db.QueryRow(ctx, query, args...).Scan(
+		&tag.Id,
+		&tag.Slug,
+		&tag.Name,
+		&tag.Posts,
+		&tag.CreatedAt,
+		&tag.UpdatedAt,
+		&tag.Synonyms,
+	); err != nil {
+		return nil, p.db.Error(err)
+	}
+
+	return &tag, nil
+}
+
+// ListTag returns the tags that have live posts, the most used first.
+func (p tagRepo) ListTag(ctx context.Context, req *entity.ListReq) (*entity.TagListRes, error) {
+	ctx, span := otlp.Start(ctx, serviceNameTagService, spanNameTagService+"ListTag")
+	defer span.End()
+
+	var tags entity.TagListRes
+
+	counted := p.db.Sq.Builder.Select("counted.*").
+		FromSelect(p.tagsSelectQueryPrefix(), "counted").
+		Where("counted.posts > 0")
+
+	order := keyset{name: sortPopular, key: "counted.posts", createdAt: "counted.created_at", id: "counted.id", desc: true}
+	queryBuilder, err := order.page(counted, req)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "list"))
+	}
+
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, p.db.Error(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tag entity.Tag
+		if err = rows.Scan(
+			&tag.Id,
+			&tag.Slug,
+			&tag.Name,
+			&tag.Posts,
+			&tag.CreatedAt,
+			&tag.UpdatedAt,
+		); err != nil {
+			return nil, p.db.Error(err)
+		}
+
+		tags.Tag = append(tags.Tag, &tag)
+	}
+	tags.Tag, tags.NextCursor, tags.PrevCursor = cursors(order, tags.Tag, req, func(tag *entity.Tag) *entity.Cursor {
+		posts := float64(tag.Posts)
+		return &entity.Cursor{
+			Key:       &posts,
+			CreatedAt: tag.CreatedAt,
+			Id:        tag.Id,
+		}
+	})
+	if req.SkipCount {
+		return &tags, nil
+	}
+
+	query, args, err = p.db.Sq.Builder.Select("COUNT(*)").
+		FromSelect(counted, "used").
+		ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "list"))
+	}
+
+	if err := p.db.QueryRow(ctx, query, args...).Scan(&tags.TotalCount); err != nil {
+		return nil, p.db.Error(err)
+	}
+
+	return &tags, nil
+}
+
+// SuggestTag returns up to limit tags whose slug or one of whose synonyms
+// starts with prefix, the most used first.
+func (p tagRepo) SuggestTag(ctx context.Context, prefix string, limit int) ([]*entity.Tag, error) {
+	ctx, span := otlp.Start(ctx, serviceNameTagService, spanNameTagService+"SuggestTag")
+	defer span.End()
+
+	query, args, err := p.tagsSelectQueryPrefix().
+		Where(squirrel.Or{
+			squirrel.Like{"tags.slug": prefix + "%"},
+			squirrel.Expr("tags.id IN (SELECT tag_id FROM tag_synonyms WHERE synonym LIKE ?)", prefix+"%"),
+		}).
+		OrderBy("posts DESC", "tags.slug").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "suggest"))
+	}
+
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, p.db.Error(err)
+	}
+	defer rows.Close()
+
+	var tags []*entity.Tag
+	for rows.Next() {
+		var tag entity.Tag
+		if err = rows.Scan(
+			&tag.Id,
+			&tag.Slug,
+			&tag.Name,
+			&tag.Posts,
+			&tag.CreatedAt,
+			&tag.UpdatedAt,
+		); err != nil {
+			return nil, p.db.Error(err)
+		}
+		tags = append(tags, &tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// RenameTag gives a tag a new slug and name. The old slug becomes a synonym,
+// so links to it keep working; the posts of the tag are not touched.
+func (p tagRepo) RenameTag(ctx context.Context, tag *entity.Tag, oldSlug string) error {
+	ctx, span := otlp.Start(ctx, serviceNameTagService, spanNameTagService+"RenameTag")
+	defer span.End()
+
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		return p.db.Error(err)
+	}
+	defer tx.Rollback(ctx)
+
+	commandTag, err := tx.Exec(ctx, "UPDATE tags SET slug = $2, name = $3, updated_at = $4 WHERE id = $1", tag.Id, tag.Slug, tag.Name, tag.UpdatedAt)
+	if err != nil {
+		return p.db.Error(err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return entity.ErrorNotFound
+	}
+
+	if _, err = tx.Exec(ctx, "DELETE FROM tag_synonyms WHERE synonym = $1", tag.Slug); err != nil {
+		return p.db.Error(err)
+	}
+	if oldSlug != tag.Slug {
+		if _, err = tx.Exec(ctx, "INSERT INTO tag_synonyms (synonym, tag_id) VALUES ($1, $2) ON CONFLICT (synonym) DO UPDATE SET tag_id = EXCLUDED.tag_id", oldSlug, tag.Id); err != nil {
+			return p.db.Error(err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return p.db.Error(err)
+	}
+
+	return nil
+}
+
+// MergeTag moves the posts and synonyms of the source tag to the target tag
+// and deletes the source. Its slug becomes a synonym of the target.
+func (p tagRepo) MergeTag(ctx context.Context, sourceId, targetId string) error {
+	ctx, span := otlp.Start(ctx, serviceNameTagService, spanNameTagService+"MergeTag")
+	defer span.End()
+
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		return p.db.Error(err)
+	}
+	defer tx.Rollback(ctx)
+
+	statements := []string{
+		"INSERT INTO post_tags (post_id, tag_id, created_at) SELECT post_id, $2, created_at FROM post_tags WHERE tag_id = $1 ON CONFLICT (post_id, tag_id) DO NOTHING",
+		"UPDATE tag_synonyms SET tag_id = $2 WHERE tag_id = $1",
+		"INSERT INTO tag_synonyms (synonym, tag_id) SELECT slug, $2 FROM tags WHERE id = $1 ON CONFLICT (synonym) DO UPDATE SET tag_id = EXCLUDED.tag_id",
+	}
+	for _, statement := range statements {
+		if _, err = tx.Exec(ctx, statement, sourceId, targetId); err != nil {
+			return p.db.Error(err)
+		}
+	}
+
+	// the source's post_tags rows go with it
+	commandTag, err := tx.Exec(ctx, "DELETE FROM tags WHERE id = $1", sourceId)
+	if err != nil {
+		return p.db.Error(err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return entity.ErrorNotFound
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return p.db.Error(err)
+	}
+
+	return nil
+}
+
+func (p tagRepo) AddSynonym(ctx context.Context, tagId, synonym string) error {
+	ctx, span := otlp.Start(ctx, serviceNameTagService, spanNameTagService+"AddSynonym")
+	defer span.End()
+
+	query, args, err := p.db.Sq.Builder.Insert(tagSynonymsTableName).
+		SetMap(map[string]any{
+			"synonym": synonym,
+			"tag_id":  tagId,
+		}).ToSql()
+	if err != nil {
+		return p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", tagSynonymsTableName, "create"))
+	}
+
+	_, err = p.db.Exec(ctx, query, args...)
+	if err != nil {
+		return p.db.Error(err)
+	}
+
+	return nil
+}
+
+func (p tagRepo) DeleteSynonym(ctx context.Context, synonym string) error {
+	ctx, span := otlp.Start(ctx, serviceNameTagService, spanNameTagService+"DeleteSynonym")
+	defer span.End()
+
+	query, args, err := p.db.Sq.Builder.Delete(tagSynonymsTableName).
+		Where(p.db.Sq.Equal("synonym", synonym)).
+		ToSql()
+	if err != nil {
+		return p.db.ErrSQLBuild(err, tagSynonymsTableName+" delete")
+	}
+
+	commandTag, err := p.db.Exec(ctx, query, args...)
+	if err != nil {
+		return p.db.Error(err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return entity.ErrorNotFound
+	}
+
+	return nil
+}
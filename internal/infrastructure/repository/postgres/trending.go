@@ -0,0 +1,206 @@
+package postgres
+
+import (
+	"context"
+	"time"
+	"univer/internal/entity"
+	"univer/internal/pkg/otlp"
+	postgres "univer/internal/pkg/storage"
+)
+
+const (
+	trendingPostsTableName     = "trending_posts"
+	trendingTopicsTableName    = "trending_topics"
+	serviceNameTrendingService = "trendingServiceRepo"
+	spanNameTrendingService    = "trendingSpanRepo"
+
+	// trendingDownloadWeight is how many views a download counts as; taking
+	// the file says more about a post than opening it.
+	trendingDownloadWeight = 3
+)
+
+// computeTrendingQuery scores the posts viewed or downloaded in a window by
+// their decayed views and downloads, a download weighing $6 views, and keeps
+// the top ones overall, per category and per subject, along with the
+// categories and subjects themselves.
+const computeTrendingQuery = `
+WITH events AS (
+	SELECT post_id, created_at, 1::float8 AS weight
+	FROM views
+	WHERE created_at >= $4 AND created_at <= $2
+	UNION ALL
+	SELECT post_id, created_at, $6::float8
+	FROM downloads
+	WHERE created_at >= $4 AND created_at <= $2
+), scores AS (
+	SELECT events.post_id,
+		posts.category_id::text AS category,
+		coalesce(posts.subject_id::text, '') AS subject,
+		sum(events.weight * exp(-ln(2) * extract(epoch FROM $2::timestamptz - events.created_at) / $3)) AS score
+	FROM events
+	JOIN posts ON posts.id = events.post_id AND posts.deleted_at IS NULL
+	GROUP BY events.post_id, posts.category_id, posts.subject_id
+), scoped AS (
+	SELECT 'all' AS scope, '' AS scope_value, post_id, score FROM scores
+	UNION ALL
+	SELECT 'category', category, post_id, score FROM scores
+	UNION ALL
+	SELECT 'science', subject, post_id, score FROM scores WHERE subject <> ''
+), ranked_posts AS (
+	INSERT INTO trending_posts (time_window, scope, scope_value, post_id, score, computed_at)
+	SELECT $1, scope, scope_value, post_id, score, $2
+	FROM (
+		SELECT *, row_number() OVER (PARTITION BY scope, scope_value ORDER BY score DESC, post_id) AS position
+		FROM scoped
+	) ranked
+	WHERE position <= $5
+	RETURNING 1
+), topics AS (
+	SELECT 'category' AS kind, category AS value, sum(score) AS score FROM scores GROUP BY category
+	UNION ALL
+	SELECT 'science', subject, sum(score) FROM scores WHERE subject <> '' GROUP BY subject
+)
+INSERT INTO trending_topics (time_window, kind, value, score, computed_at)
+SELECT $1, kind, value, score, $2
+FROM (
+	SELECT *, row_number() OVER (PARTITION BY kind ORDER BY score DESC, value) AS position
+	FROM topics
+) ranked
+WHERE position <= $5`
+
+type trendingRepo struct {
+	db *postgres.PostgresDB
+}
+
+func NewTrendingRepo(db *postgres.PostgresDB) *trendingRepo {
+	return &trendingRepo{
+		db: db,
+	}
+}
+
+// ComputeTrending replaces the trending posts and topics of every window with
+// ones computed at now, keeping limit of each list. When another instance is
+// computing them already this one leaves it to it.
+func (p trendingRepo) ComputeTrending(ctx context.Context, windows []entity.TrendingWindow, now time.Time, limit int) error {
+	ctx, span := otlp.Start(ctx, serviceNameTrendingService, spanNameTrendingService+"ComputeTrending")
+	defer span.End()
+
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		return p.db.Error(err)
+	}
+	defer tx.Rollback(ctx)
+
+	var locked bool
+	if err = tx.QueryRow(ctx, "SELECT pg_try_advisory_xact_lock(hashtext('trending'))").Scan(&locked); err != nil {
+		return p.db.Error(err)
+	}
+	if !locked {
+		return nil
+	}
+
+	for _, window := range windows {
+		if _, err = tx.Exec(ctx, "DELETE FROM trending_posts WHERE time_window = $1", window.Name); err != nil {
+			return p.db.Error(err)
+		}
+		if _, err = tx.Exec(ctx, "DELETE FROM trending_topics WHERE time_window = $1", window.Name); err != nil {
+			return p.db.Error(err)
+		}
+		_, err = tx.Exec(ctx, computeTrendingQuery,
+			window.Name, now, window.HalfLife.Seconds(), now.Add(-window.Span), limit, trendingDownloadWeight)
+		if err != nil {
+			return p.db.Error(err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return p.db.Error(err)
+	}
+
+	return nil
+}
+
+// ListTrending returns the top posts of a window and scope, highest score
+// first, and when they were computed.
+func (p trendingRepo) ListTrending(ctx context.Context, req *entity.TrendingReq) ([]*entity.TrendingPost, time.Time, error) {
+	ctx, span := otlp.Start(ctx, serviceNameTrendingService, spanNameTrendingService+"ListTrending")
+	defer span.End()
+
+	var computedAt time.Time
+
+	query, args, err := p.db.Sq.Builder.
+		Select("post_id", "score", "computed_at").
+		From(trendingPostsTableName).
+		Where(p.db.Sq.Equal("time_window", req.Window)).
+		Where(p.db.Sq.Equal("scope", req.Scope)).
+		Where(p.db.Sq.Equal("scope_value", req.Value)).
+		OrderBy("score DESC", "post_id").
+		Limit(uint64(req.Limit)).
+		ToSql()
+	if err != nil {
+		return nil, computedAt, p.db.ErrSQLBuild(err, trendingPostsTableName+" list")
+	}
+
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, computedAt, p.db.Error(err)
+	}
+	defer rows.Close()
+
+	var posts []*entity.TrendingPost
+	for rows.Next() {
+		post := entity.TrendingPost{Post: &entity.Post{}}
+		if err = rows.Scan(&post.Post.Id, &post.Score, &computedAt); err != nil {
+			return nil, computedAt, p.db.Error(err)
+		}
+		posts = append(posts, &post)
+	}
+
+	return posts, computedAt, rows.Err()
+}
+
+// ListTrendingTopics returns the top categories and subjects of a window,
+// highest score first, with their names.
+func (p trendingRepo) ListTrendingTopics(ctx context.Context, window string, limit int) ([]*entity.TrendingTopic, error) {
+	ctx, span := otlp.Start(ctx, serviceNameTrendingService, spanNameTrendingService+"ListTrendingTopics")
+	defer span.End()
+
+	query, args, err := p.db.Sq.Builder.
+		Select(
+			"trending_topics.kind",
+			"trending_topics.value",
+			"coalesce(category.name, subjects.name, '')",
+			"trending_topics.score",
+		).
+		From(trendingTopicsTableName).
+		LeftJoin(categoryServiceTableName+" ON trending_topics.kind = 'category' AND category.id::text = trending_topics.value").
+		LeftJoin(subjectServiceTableName+" ON trending_topics.kind = 'science' AND subjects.id::text = trending_topics.value").
+		Where(p.db.Sq.Equal("trending_topics.time_window", window)).
+		OrderBy("trending_topics.kind", "trending_topics.score DESC", "trending_topics.value").
+		ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, trendingTopicsTableName+" list")
+	}
+
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, p.db.Error(err)
+	}
+	defer rows.Close()
+
+	var topics []*entity.TrendingTopic
+	counts := map[string]int{}
+	for rows.Next() {
+		var topic entity.TrendingTopic
+		if err = rows.Scan(&topic.Kind, &topic.Value, &topic.Name, &topic.Score); err != nil {
+			return nil, p.db.Error(err)
+		}
+		if counts[topic.Kind] >= limit {
+			continue
+		}
+		counts[topic.Kind]++
+		topics = append(topics, &topic)
+	}
+
+	return topics, rows.Err()
+}
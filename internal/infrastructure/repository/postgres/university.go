@@ -0,0 +1,526 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"univer/internal/entity"
+	"univer/internal/pkg/otlp"
+	postgres "univer/internal/pkg/storage"
+
+	"github.com/Masterminds/squirrel"
+)
+
+const (
+	universityServiceTableName   = "universities"
+	facultyServiceTableName      = "faculties"
+	courseServiceTableName       = "courses"
+	serviceNameUniversityService = "universityServiceRepo"
+	spanNameUniversityService    = "universitySpanRepo"
+)
+
+type universityRepo struct {
+	tableName string
+	db        *postgres.PostgresDB
+}
+
+func NewUniversityRepo(db *postgres.PostgresDB) *universityRepo {
+	return &universityRepo{
+		tableName: universityServiceTableName,
+		db:        db,
+	}
+}
+
+func (p *universityRepo) universitySelectQueryPrefix() squirrel.SelectBuilder {
+	return p.db.Sq.Builder.
+		Select(
+			"id",
+			"name",
+			"created_at",
+			"updated_at",
+		).From(p.tableName).
+		Where("deleted_at IS NULL")
+}
+
+func (p *universityRepo) facultySelectQueryPrefix() squirrel.SelectBuilder {
+	return p.db.Sq.Builder.
+		Select(
+			"id",
+			"university_id",
+			"name",
+			"created_at",
+			"updated_at",
+		).From(facultyServiceTableName).
+		Where("deleted_at IS NULL")
+}
+
+// courseSelectQueryPrefix joins the faculty so a course knows its university.
+func (p *universityRepo) courseSelectQueryPrefix() squirrel.SelectBuilder {
+	return p.db.Sq.Builder.
+		Select(
+			"courses.id",
+			"courses.faculty_id",
+			"faculties.university_id",
+			"courses.name",
+			"courses.year",
+			"courses.created_at",
+			"courses.updated_at",
+		).From(courseServiceTableName).
+		Join(facultyServiceTableName + " ON faculties.id = courses.faculty_id").
+		Where("courses.deleted_at IS NULL")
+}
+
+func (p universityRepo) insert(ctx context.Context, table string, data map[string]any) error {
+	query, args, err := p.db.Sq.Builder.Insert(table).SetMap(data).ToSql()
+	if err != nil {
+		return p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", table, "create"))
+	}
+
+	if _, err = p.db.Exec(ctx, query, args...); err != nil {
+		return p.db.Error(err)
+	}
+
+	return nil
+}
+
+func (p universityRepo) update(ctx context.Context, table, id string, clauses map[string]any) error {
+	sqlStr, args, err := p.db.Sq.Builder.
+		Update(table).
+		SetMap(clauses).
+		Where(p.db.Sq.Equal("id", id)).
+		Where("deleted_at IS NULL").
+		ToSql()
+	if err != nil {
+		return p.db.ErrSQLBuild(err, table+" update")
+	}
+
+	commandTag, err := p.db.Exec(ctx, sqlStr, args...)
+	if err != nil {
+		return p.db.Error(err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return entity.ErrorNotFound
+	}
+
+	return nil
+}
+
+// delete soft deletes a row of table unless the inUse query, given the id,
+// finds something still pointing at it.
+func (p universityRepo) delete(ctx context.Context, table string, req *entity.DeleteReq, inUse string, errInUse error) error {
+	var used bool
+	if err := p.db.QueryRow(ctx, "SELECT EXISTS ("+inUse+")", req.Id).Scan(&used); err != nil {
+		return p.db.Error(err)
+	}
+	if used {
+		return errInUse
+	}
+
+	return p.update(ctx, table, req.Id, map[string]any{
+		"deleted_at": req.DeletedAt,
+	})
+}
+
+func (p universityRepo) CreateUniversity(ctx context.Context, university *entity.University) (*entity.University, error) {
+	ctx, span := otlp.Start(ctx, serviceNameUniversityService, spanNameUniversityService+"CreateUniversity")
+	defer span.End()
+
+	err := p.insert(ctx, p.tableName, map[string]any{
+		"id":         university.Id,
+		"name":       university.Name,
+		"created_at": university.CreatedAt,
+		"updated_at": university.UpdatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return university, nil
+}
+
+func (p universityRepo) UpdateUniversity(ctx context.Context, university *entity.University) (*entity.University, error) {
+	ctx, span := otlp.Start(ctx, serviceNameUniversityService, spanNameUniversityService+"UpdateUniversity")
+	defer span.End()
+
+	err := p.update(ctx, p.tableName, university.Id, map[string]any{
+		"name":       university.Name,
+		"updated_at": university.UpdatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return p.GetUniversity(ctx, university.Id)
+}
+
+func (p universityRepo) DeleteUniversity(ctx context.Context, req *entity.DeleteReq) error {
+	ctx, span := otlp.Start(ctx, serviceNameUniversityService, spanNameUniversityService+"DeleteUniversity")
+	defer span.End()
+
+	return p.delete(ctx, p.tableName, req, `
+		SELECT 1 FROM faculties WHERE university_id = $1 AND deleted_at IS NULL
+		UNION ALL
+		SELECT 1 FROM posts WHERE university_id = $1 AND deleted_at IS NULL
+		UNION ALL
+		SELECT 1 FROM users WHERE university_id = $1 AND deleted_at IS NULL`,
+		entity.ErrorUniversityInUse)
+}
+
+func (p universityRepo) GetUniversity(ctx context.Context, id string) (*entity.University, error) {
+	ctx, span := otlp.Start(ctx, serviceNameUniversityService, spanNameUniversityService+"GetUniversity")
+	defer span.End()
+
+	query, args, err := p.universitySelectQueryPrefix().Where(p.db.Sq.Equal("id", id)).ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "get"))
+	}
+
+	var university entity.University
+	if err = p.db.QueryRow(ctx, query, args...).Scan(
+		&university.Id,
+		&university.Name,
+		&university.CreatedAt,
+		&university.UpdatedAt,
+	); err != nil {
+		return nil, p.db.Error(err)
+	}
+
+	return &university, nil
+}
+
+func (p universityRepo) ListUniversity(ctx context.Context, req *entity.ListReq) (*entity.UniversityListRes, error) {
+	ctx, span := otlp.Start(ctx, serviceNameUniversityService, spanNameUniversityService+"ListUniversity")
+	defer span.End()
+
+	var universities entity.UniversityListRes
+
+	order := keyset{createdAt: "created_at", id: "id"}
+	queryBuilder, err := order.page(p.universitySelectQueryPrefix(), req)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "list"))
+	}
+
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, p.db.Error(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var university entity.University
+		if err = rows.Scan(
+			&university.Id,
+			&university.Name,
+			&university.CreatedAt,
+			&university.UpdatedAt,
+		); err != nil {
+			return nil, p.db.Error(err)
+		}
+
+		universities.University = append(universities.University, &university)
+	}
+	universities.University, universities.NextCursor, universities.PrevCursor = cursors(order, universities.University, req, func(university *entity.University) *entity.Cursor {
+		return &entity.Cursor{
+			CreatedAt: university.CreatedAt,
+			Id:        university.Id,
+		}
+	})
+	if req.SkipCount {
+		return &universities, nil
+	}
+
+	universities.TotalCount, err = p.count(ctx, p.tableName, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &universities, nil
+}
+
+func (p universityRepo) CreateFaculty(ctx context.Context, faculty *entity.Faculty) (*entity.Faculty, error) {
+	ctx, span := otlp.Start(ctx, serviceNameUniversityService, spanNameUniversityService+"CreateFaculty")
+	defer span.End()
+
+	if _, err := p.GetUniversity(ctx, faculty.UniversityId); err != nil {
+		return nil, err
+	}
+
+	err := p.insert(ctx, facultyServiceTableName, map[string]any{
+		"id":            faculty.Id,
+		"university_id": faculty.UniversityId,
+		"name":          faculty.Name,
+		"created_at":    faculty.CreatedAt,
+		"updated_at":    faculty.UpdatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return faculty, nil
+}
+
+// UpdateFaculty renames a faculty; it cannot move to another university.
+func (p universityRepo) UpdateFaculty(ctx context.Context, faculty *entity.Faculty) (*entity.Faculty, error) {
+	ctx, span := otlp.Start(ctx, serviceNameUniversityService, spanNameUniversityService+"UpdateFaculty")
+	defer span.End()
+
+	err := p.update(ctx, facultyServiceTableName, faculty.Id, map[string]any{
+		"name":       faculty.Name,
+		"updated_at": faculty.UpdatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return p.GetFaculty(ctx, faculty.Id)
+}
+
+func (p universityRepo) DeleteFaculty(ctx context.Context, req *entity.DeleteReq) error {
+	ctx, span := otlp.Start(ctx, serviceNameUniversityService, spanNameUniversityService+"DeleteFaculty")
+	defer span.End()
+
+	return p.delete(ctx, facultyServiceTableName, req, `
+		SELECT 1 FROM courses WHERE faculty_id = $1 AND deleted_at IS NULL
+		UNION ALL
+		SELECT 1 FROM posts WHERE faculty_id = $1 AND deleted_at IS NULL`,
+		entity.ErrorFacultyInUse)
+}
+
+func (p universityRepo) GetFaculty(ctx context.Context, id string) (*entity.Faculty, error) {
+	ctx, span := otlp.Start(ctx, serviceNameUniversityService, spanNameUniversityService+"GetFaculty")
+	defer span.End()
+
+	query, args, err := p.facultySelectQueryPrefix().Where(p.db.Sq.Equal("id", id)).ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", facultyServiceTableName, "get"))
+	}
+
+	var faculty entity.Faculty
+	if err = p.db.QueryRow(ctx, query, args...).Scan(
+		&faculty.Id,
+		&faculty.UniversityId,
+		&faculty.Name,
+		&faculty.CreatedAt,
+		&faculty.UpdatedAt,
+	); err != nil {
+		return nil, p.db.Error(err)
+	}
+
+	return &faculty, nil
+}
+
+func (p universityRepo) ListFaculty(ctx context.Context, universityId string, req *entity.ListReq) (*entity.FacultyListRes, error) {
+	ctx, span := otlp.Start(ctx, serviceNameUniversityService, spanNameUniversityService+"ListFaculty")
+	defer span.End()
+
+	var faculties entity.FacultyListRes
+
+	order := keyset{createdAt: "created_at", id: "id"}
+	queryBuilder, err := order.page(p.facultySelectQueryPrefix().Where(p.db.Sq.Equal("university_id", universityId)), req)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", facultyServiceTableName, "list"))
+	}
+
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, p.db.Error(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var faculty entity.Faculty
+		if err = rows.Scan(
+			&faculty.Id,
+			&faculty.UniversityId,
+			&faculty.Name,
+			&faculty.CreatedAt,
+			&faculty.UpdatedAt,
+		); err != nil {
+			return nil, p.db.Error(err)
+		}
+
+		faculties.Faculty = append(faculties.Faculty, &faculty)
+	}
+	faculties.Faculty, faculties.NextCursor, faculties.PrevCursor = cursors(order, faculties.Faculty, req, func(faculty *entity.Faculty) *entity.Cursor {
+		return &entity.Cursor{
+			CreatedAt: faculty.CreatedAt,
+			Id:        faculty.Id,
+		}
+	})
+	if req.SkipCount {
+		return &faculties, nil
+	}
+
+	faculties.TotalCount, err = p.count(ctx, facultyServiceTableName, p.db.Sq.Equal("university_id", universityId))
+	if err != nil {
+		return nil, err
+	}
+
+	return &faculties, nil
+}
+
+func (p universityRepo) CreateCourse(ctx context.Context, course *entity.Course) (*entity.Course, error) {
+	ctx, span := otlp.Start(ctx, serviceNameUniversityService, spanNameUniversityService+"CreateCourse")
+	defer span.End()
+
+	faculty, err := p.GetFaculty(ctx, course.FacultyId)
+	if err != nil {
+		return nil, err
+	}
+	course.UniversityId = faculty.UniversityId
+
+	err = p.insert(ctx, courseServiceTableName, map[string]any{
+		"id":         course.Id,
+		"faculty_id": course.FacultyId,
+		"name":       course.Name,
+		"year":       course.Year,
+		"created_at": course.CreatedAt,
+		"updated_at": course.UpdatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return course, nil
+}
+
+// UpdateCourse renames a course or changes its year; it cannot move to
+// another faculty.
+func (p universityRepo) UpdateCourse(ctx context.Context, course *entity.Course) (*entity.Course, error) {
+	ctx, span := otlp.Start(ctx, serviceNameUniversityService, spanNameUniversityService+"UpdateCourse")
+	defer span.End()
+
+	err := p.update(ctx, courseServiceTableName, course.Id, map[string]any{
+		"name":       course.Name,
+		"year":       course.Year,
+		"updated_at": course.UpdatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return p.GetCourse(ctx, course.Id)
+}
+
+func (p universityRepo) DeleteCourse(ctx context.Context, req *entity.DeleteReq) error {
+	ctx, span := otlp.Start(ctx, serviceNameUniversityService, spanNameUniversityService+"DeleteCourse")
+	defer span.End()
+
+	return p.delete(ctx, courseServiceTableName, req,
+		"SELECT 1 FROM posts WHERE course_id = $1 AND deleted_at IS NULL",
+		entity.ErrorCourseInUse)
+}
+
+func (p universityRepo) GetCourse(ctx context.Context, id string) (*entity.Course, error) {
+	ctx, span := otlp.Start(ctx, serviceNameUniversityService, spanNameUniversityService+"GetCourse")
+	defer span.End()
+
+	query, args, err := p.courseSelectQueryPrefix().Where(p.db.Sq.Equal("courses.id", id)).ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", courseServiceTableName, "get"))
+	}
+
+	var course entity.Course
+	if err = p.db.QueryRow(ctx, query, args...).Scan(
+		&course.Id,
+		&course.FacultyId,
+		&course.UniversityId,
+		&course.Name,
+		&course.Year,
+		&course.CreatedAt,
+		&course.UpdatedAt,
+	); err != nil {
+		return nil, p.db.Error(err)
+	}
+
+	return &course, nil
+}
+
+func (p universityRepo) ListCourse(ctx context.Context, facultyId string, req *entity.ListReq) (*entity.CourseListRes, error) {
+	ctx, span := otlp.Start(ctx, serviceNameUniversityService, spanNameUniversityService+"ListCourse")
+	defer span.End()
+
+	var courses entity.CourseListRes
+
+	order := keyset{createdAt: "courses.created_at", id: "courses.id"}
+	queryBuilder, err := order.page(p.courseSelectQueryPrefix().Where(p.db.Sq.Equal("courses.faculty_id", facultyId)), req)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", courseServiceTableName, "list"))
+	}
+
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, p.db.Error(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var course entity.Course
+		if err = rows.Scan(
+			&course.Id,
+			&course.FacultyId,
+			&course.UniversityId,
+			&course.Name,
+			&course.Year,
+			&course.CreatedAt,
+			&course.UpdatedAt,
+		); err != nil {
+			return nil, p.db.Error(err)
+		}
+
+		courses.Course = append(courses.Course, &course)
+	}
+	courses.Course, courses.NextCursor, courses.PrevCursor = cursors(order, courses.Course, req, func(course *entity.Course) *entity.Cursor {
+		return &entity.Cursor{
+			CreatedAt: course.CreatedAt,
+			Id:        course.Id,
+		}
+	})
+	if req.SkipCount {
+		return &courses, nil
+	}
+
+	courses.TotalCount, err = p.count(ctx, courseServiceTableName, p.db.Sq.Equal("faculty_id", facultyId))
+	if err != nil {
+		return nil, err
+	}
+
+	return &courses, nil
+}
+
+// count counts the live rows of table, narrowed by where when it is set.
+func (p universityRepo) count(ctx context.Context, table string, where squirrel.Sqlizer) (int, error) {
+	queryBuilder := p.db.Sq.Builder.Select("COUNT(*)").
+		From(table).
+		Where("deleted_at IS NULL")
+	if where != nil {
+		queryBuilder = queryBuilder.Where(where)
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return 0, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", table, "list"))
+	}
+
+	var count int
+	if err := p.db.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return 0, p.db.Error(err)
+	}
+
+	return count, nil
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"univer/internal/entity"
 	"univer/internal/pkg/otlp"
 	postgres "univer/internal/pkg/storage"
+	"univer/internal/pkg/translit"
 
 	"github.com/Masterminds/squirrel"
 )
@@ -17,6 +19,9 @@ const (
 	spanNameUserService    = "userSpanRepo"
 )
 
+// likeEscaper escapes the LIKE wildcards in user input.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 type userRepo struct {
 	tableName string
 	db        *postgres.PostgresDB
@@ -41,6 +46,7 @@ func (p *userRepo) usersSelectQueryPrefix() squirrel.SelectBuilder {
 			"image_url",
 			"role",
 			"refresh_token",
+			"coalesce(university_id::text, '')",
 			"created_at",
 			"updated_at",
 		).From(p.tableName)
@@ -106,7 +112,7 @@ func (p userRepo) Update(ctx context.Context, user *entity.User) (*entity.User,
 	}
 
 	if commandTag.RowsAffected() == 0 {
-		return nil, p.db.Error(fmt.Errorf("no sql rows"))
+		return nil, entity.ErrorNotFound
 	}
 
 	return user, nil
@@ -135,7 +141,7 @@ func (p userRepo) Delete(ctx context.Context, req *entity.DeleteReq) error {
 	}
 
 	if commandTag.RowsAffected() == 0 {
-		return p.db.Error(fmt.Errorf("no sql rows"))
+		return entity.ErrorNotFound
 	}
 
 	return nil
@@ -161,6 +167,11 @@ func (p userRepo) Get(ctx context.Context, params map[string]string) (*entity.Us
 			queryBuilder = queryBuilder.Where(p.db.Sq.Equal(key, value))
 		}else if key == "username"{
 			queryBuilder = queryBuilder.Where(p.db.Sq.Equal(key, value))
+		}else if key == "username_normalized"{
+			// spelled in either script; an exact match wins over a
+			// transliterated one
+			queryBuilder = queryBuilder.Where("uz_normalize(username) = ?", translit.Normalize(value)).
+				OrderByClause("username = ? DESC", value).Limit(1)
 		}else if key == "del"{
 			cnt ++
 		}
@@ -189,6 +200,7 @@ func (p userRepo) Get(ctx context.Context, params map[string]string) (*entity.Us
 		&nullImageUrl,
 		&user.Role,
 		&nullRefresh,
+		&user.UniversityId,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	); err != nil {
@@ -210,7 +222,7 @@ func (p userRepo) Get(ctx context.Context, params map[string]string) (*entity.Us
 	return &user, nil
 }
 
-func (p userRepo) List(ctx context.Context, limit int, offset int, filter map[string]string) (*entity.ListUserRes, error) {
+func (p userRepo) List(ctx context.Context, req *entity.ListReq) (*entity.ListUserRes, error) {
 
 	ctx, span := otlp.Start(ctx, serviceNameUserService, spanNameUserService+"ListUsers")
 	defer span.End()
@@ -220,13 +232,17 @@ func (p userRepo) List(ctx context.Context, limit int, offset int, filter map[st
 	)
 	queryBuilder := p.usersSelectQueryPrefix()
 
-	if limit != 0 {
-		queryBuilder = queryBuilder.Limit(uint64(limit)).Offset(uint64(offset))
-	}
-
+	filter := req.Filter
 	role := filter["role"]
 	queryBuilder = queryBuilder.Where(p.db.Sq.Equal("role", role))
-	queryBuilder = queryBuilder.Where("deleted_at IS NULL").OrderBy("created_at")
+	if username, ok := filter["username"]; ok {
+		queryBuilder = queryBuilder.Where("uz_normalize(username) LIKE ?", likeEscaper.Replace(translit.Normalize(username))+"%")
+	}
+	order := keyset{createdAt: "created_at", id: "id"}
+	queryBuilder, err := order.page(queryBuilder.Where("deleted_at IS NULL"), req)
+	if err != nil {
+		return nil, err
+	}
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
@@ -257,6 +273,7 @@ func (p userRepo) List(ctx context.Context, limit int, offset int, filter map[st
 			&nullImageUrl,
 			&user.Role,
 			&nullRefresh,
+			&user.UniversityId,
 			&user.CreatedAt,
 			&user.UpdatedAt,
 		); err != nil {
@@ -277,19 +294,33 @@ func (p userRepo) List(ctx context.Context, limit int, offset int, filter map[st
 
 		users.User = append(users.User, &user)
 	}
+	users.User, users.NextCursor, users.PrevCursor = cursors(order, users.User, req, func(user *entity.User) *entity.Cursor {
+		return &entity.Cursor{
+			CreatedAt: user.CreatedAt,
+			Id:        user.Id,
+		}
+	})
+	if req.SkipCount {
+		return &users, nil
+	}
 
 	var count uint64
 
 	queryBuilder = p.db.Sq.Builder.Select("COUNT(*)").
 	From(p.tableName).
 	Where("deleted_at is null")
+	if username, ok := filter["username"]; ok {
+		queryBuilder = queryBuilder.
+			Where(p.db.Sq.Equal("role", role)).
+			Where("uz_normalize(username) LIKE ?", likeEscaper.Replace(translit.Normalize(username))+"%")
+	}
 
-	query, _, err = queryBuilder.ToSql()
+	query, args, err = queryBuilder.ToSql()
 	if err != nil {
 		return nil, p.db.ErrSQLBuild(err, fmt.Sprintf("%s %s", p.tableName, "list"))
 	}
 
-	if err := p.db.QueryRow(ctx, query).Scan(&count); err != nil {
+	if err := p.db.QueryRow(ctx, query, args...).Scan(&count); err != nil {
 		users.TotalCount = 0
 	}
 	users.TotalCount = int64(count)
@@ -360,7 +391,7 @@ func (p userRepo) UpdateRefresh(ctx context.Context, request *entity.UpdateRefre
 	}
 
 	if commandTag.RowsAffected() == 0 {
-		return &entity.Response{Status: false}, p.db.Error(fmt.Errorf("no sql rows"))
+		return &entity.Response{Status: false}, entity.ErrorNotFound
 	}
 
 	return &entity.Response{Status: true}, nil
@@ -389,7 +420,7 @@ func (p userRepo) UpdatePassword(ctx context.Context, request *entity.UpdatePass
 	}
 
 	if commandTag.RowsAffected() == 0 {
-		return &entity.Response{Status: false}, p.db.Error(fmt.Errorf("no sql rows"))
+		return &entity.Response{Status: false}, entity.ErrorNotFound
 	}
 
 	return &entity.Response{Status: true}, nil
@@ -418,7 +449,36 @@ func (p userRepo) UpdateProfile(ctx context.Context, request *entity.UpdateProfi
 	}
 
 	if commandTag.RowsAffected() == 0 {
-		return &entity.Response{Status: false}, p.db.Error(fmt.Errorf("no sql rows"))
+		return &entity.Response{Status: false}, entity.ErrorNotFound
+	}
+
+	return &entity.Response{Status: true}, nil
+}
+
+func (p userRepo) UpdateUniversity(ctx context.Context, request *entity.UpdateUniversity) (*entity.Response, error) {
+	ctx, span := otlp.Start(ctx, serviceNameUserService, spanNameUserService+"UpdateUniversity")
+	defer span.End()
+
+	clauses := map[string]any{
+		"university_id": nullId(request.UniversityId),
+	}
+	sqlStr, args, err := p.db.Sq.Builder.
+		Update(p.tableName).
+		SetMap(clauses).
+		Where(p.db.Sq.Equal("id", request.Id)).
+		Where("deleted_at IS NULL").
+		ToSql()
+	if err != nil {
+		return &entity.Response{Status: false}, p.db.ErrSQLBuild(err, p.tableName+" update")
+	}
+
+	commandTag, err := p.db.Exec(ctx, sqlStr, args...)
+	if err != nil {
+		return &entity.Response{Status: false}, p.db.Error(err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return &entity.Response{Status: false}, entity.ErrorNotFound
 	}
 
 	return &entity.Response{Status: true}, nil
@@ -447,7 +507,7 @@ func (p userRepo) DeleteProfile(ctx context.Context, id string)error{
 	}
 
 	if commandTag.RowsAffected() == 0 {
-		return p.db.Error(fmt.Errorf("no sql rows"))
+		return entity.ErrorNotFound
 	}
 
 	return nil
@@ -473,7 +533,7 @@ func (p userRepo) UpdateToPremium(ctx context.Context, id string) (*entity.Respo
 	}
 
 	if commandTag.RowsAffected() == 0 {
-		return &entity.Response{Status: false}, p.db.Error(fmt.Errorf("no sql rows"))
+		return &entity.Response{Status: false}, entity.ErrorNotFound
 	}
 
 	return &entity.Response{Status: true}, nil
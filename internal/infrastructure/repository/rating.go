@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"univer/internal/entity"
+)
+
+type Rating interface {
+	SaveRating(ctx context.Context, rating *entity.Rating) (*entity.Rating, error)
+	GetRating(ctx context.Context, postId, userId string) (*entity.Rating, error)
+	DeleteRating(ctx context.Context, postId, userId string) error
+	ListRating(ctx context.Context, req *entity.ListReq) (*entity.RatingListRes, error)
+}
@@ -0,0 +1,163 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+	"univer/internal/entity"
+
+	goredis "github.com/go-redis/redis/v8"
+
+	redis "univer/internal/pkg/storage"
+)
+
+const (
+	viewsSeenPrefix     = "views:seen:"
+	viewsEvents         = "views:events"
+	viewsCounts         = "views:counts"
+	viewsFlushingEvents = "views:flushing:events"
+	viewsFlushingCounts = "views:flushing:counts"
+
+	// viewsGuestsSuffix names the field counting a post's guest views in the
+	// counts hashes, next to the one counting all its views
+	viewsGuestsSuffix = ":guests"
+)
+
+// recordViewScript adds the viewer to the post's seen set of the day and, when
+// they are new to it, buffers the view and counts it. It returns the post's
+// buffered views and guest views, the ones being flushed included.
+var recordViewScript = goredis.NewScript(`
+local guests = ARGV[3] .. ARGV[5]
+local added = redis.call('SADD', KEYS[1], ARGV[1])
+redis.call('EXPIREAT', KEYS[1], ARGV[4])
+if added == 1 then
+	redis.call('RPUSH', KEYS[2], ARGV[2])
+	redis.call('HINCRBY', KEYS[3], ARGV[3], 1)
+	if ARGV[6] == 'true' then
+		redis.call('HINCRBY', KEYS[3], guests, 1)
+	end
+end
+local function pending(field)
+	return (tonumber(redis.call('HGET', KEYS[3], field)) or 0) + (tonumber(redis.call('HGET', KEYS[4], field)) or 0)
+end
+return {pending(ARGV[3]), pending(guests)}
+`)
+
+// takeViewsScript moves the buffered views and counts aside for flushing,
+// unless the last flush left some there, and returns the views to flush.
+var takeViewsScript = goredis.NewScript(`
+if redis.call('EXISTS', KEYS[2]) == 0 then
+	if redis.call('EXISTS', KEYS[1]) == 0 then
+		return {}
+	end
+	redis.call('RENAME', KEYS[1], KEYS[2])
+	redis.call('DEL', KEYS[4])
+	if redis.call('EXISTS', KEYS[3]) == 1 then
+		redis.call('RENAME', KEYS[3], KEYS[4])
+	end
+end
+return redis.call('LRANGE', KEYS[2], 0, -1)
+`)
+
+func NewViewBuffer(rdb *redis.RedisDB) *viewBuffer {
+	return &viewBuffer{
+		rdb: rdb,
+	}
+}
+
+// viewBuffer keeps who viewed a post in a set per post and UTC day, so a
+// viewer coming back the same day is not buffered again, and the views not
+// stored yet in a list, counted per post in a hash. A seen set expires when
+// its day ends; CreateViews leaves out views of posts viewed on earlier days.
+type viewBuffer struct {
+	rdb *redis.RedisDB
+}
+
+func (v *viewBuffer) Record(ctx context.Context, view *entity.View) (int64, int64, error) {
+	event := strings.Join([]string{
+		view.UserId,
+		view.PostId,
+		strconv.FormatInt(view.CreatedAt.UnixMilli(), 10),
+		strconv.FormatBool(view.Anonymous),
+	}, " ")
+
+	day := view.CreatedAt.UTC().Truncate(24 * time.Hour)
+	pending, err := recordViewScript.Run(ctx, &v.rdb.Client,
+		[]string{viewsSeenPrefix + view.PostId + ":" + day.Format(time.DateOnly), viewsEvents, viewsCounts, viewsFlushingCounts},
+		view.UserId, event, view.PostId, day.AddDate(0, 0, 1).Unix(), viewsGuestsSuffix, strconv.FormatBool(view.Anonymous),
+	).Int64Slice()
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(pending) != 2 {
+		return 0, 0, fmt.Errorf("record view: unexpected reply %v", pending)
+	}
+	return pending[0], pending[1], nil
+}
+
+func (v *viewBuffer) Pending(ctx context.Context, postIds ...string) (map[string]int64, error) {
+	pending := make(map[string]int64, len(postIds))
+	if len(postIds) == 0 {
+		return pending, nil
+	}
+
+	pipe := v.rdb.Client.Pipeline()
+	counts := pipe.HMGet(ctx, viewsCounts, postIds...)
+	flushing := pipe.HMGet(ctx, viewsFlushingCounts, postIds...)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	for _, cmd := range []*goredis.SliceCmd{counts, flushing} {
+		for i, value := range cmd.Val() {
+			if value, ok := value.(string); ok {
+				count, _ := strconv.ParseInt(value, 10, 64)
+				pending[postIds[i]] += count
+			}
+		}
+	}
+
+	return pending, nil
+}
+
+func (v *viewBuffer) Take(ctx context.Context) ([]*entity.View, error) {
+	events, err := takeViewsScript.Run(ctx, &v.rdb.Client,
+		[]string{viewsEvents, viewsFlushingEvents, viewsCounts, viewsFlushingCounts},
+	).StringSlice()
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]*entity.View, 0, len(events))
+	for _, event := range events {
+		// events buffered before guests were counted have no anonymous field
+		fields := strings.Fields(event)
+		if len(fields) != 3 && len(fields) != 4 {
+			log.Println("malformed view event", event)
+			continue
+		}
+		createdAt, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			log.Println("malformed view event", event)
+			continue
+		}
+		view := &entity.View{
+			UserId:    fields[0],
+			PostId:    fields[1],
+			CreatedAt: time.UnixMilli(createdAt).UTC(),
+		}
+		if len(fields) == 4 {
+			view.Anonymous, _ = strconv.ParseBool(fields[3])
+		}
+		views = append(views, view)
+	}
+
+	return views, nil
+}
+
+func (v *viewBuffer) Done(ctx context.Context) error {
+	return v.rdb.Client.Del(ctx, viewsFlushingEvents, viewsFlushingCounts).Err()
+}
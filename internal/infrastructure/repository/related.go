@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"univer/internal/entity"
+)
+
+type Related interface {
+	ComputeRelated(ctx context.Context, since, now time.Time, limit int) error
+	ListRelated(ctx context.Context, postId string, limit int) ([]*entity.RelatedPost, error)
+	ListSimilar(ctx context.Context, postId string, exclude []string, limit int) ([]*entity.RelatedPost, error)
+}
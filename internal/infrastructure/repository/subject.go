@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"univer/internal/entity"
+)
+
+type Subject interface {
+	CreateSubject(ctx context.Context, subject *entity.Subject) (*entity.Subject, error)
+	UpdateSubject(ctx context.Context, subject *entity.Subject) (*entity.Subject, error)
+	DeleteSubject(ctx context.Context, req *entity.DeleteReq) error
+	GetSubject(ctx context.Context, params map[string]string) (*entity.Subject, error)
+	ListSubject(ctx context.Context, req *entity.ListReq) (*entity.ListSubjectRes, error)
+	SubjectMapping(ctx context.Context) ([]*entity.SubjectMapping, error)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"univer/internal/entity"
+)
+
+type Tag interface {
+	ResolveTags(ctx context.Context, tags []*entity.Tag) ([]*entity.Tag, error)
+	GetTag(ctx context.Context, slug string) (*entity.Tag, error)
+	ListTag(ctx context.Context, req *entity.ListReq) (*entity.TagListRes, error)
+	SuggestTag(ctx context.Context, prefix string, limit int) ([]*entity.Tag, error)
+	RenameTag(ctx context.Context, tag *entity.Tag, oldSlug string) error
+	MergeTag(ctx context.Context, sourceId, targetId string) error
+	AddSynonym(ctx context.Context, tagId, synonym string) error
+	DeleteSynonym(ctx context.Context, synonym string) error
+}
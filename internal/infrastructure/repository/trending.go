@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"univer/internal/entity"
+)
+
+type Trending interface {
+	ComputeTrending(ctx context.Context, windows []entity.TrendingWindow, now time.Time, limit int) error
+	ListTrending(ctx context.Context, req *entity.TrendingReq) ([]*entity.TrendingPost, time.Time, error)
+	ListTrendingTopics(ctx context.Context, window string, limit int) ([]*entity.TrendingTopic, error)
+}
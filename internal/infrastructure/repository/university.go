@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"univer/internal/entity"
+)
+
+type University interface {
+	CreateUniversity(ctx context.Context, university *entity.University) (*entity.University, error)
+	UpdateUniversity(ctx context.Context, university *entity.University) (*entity.University, error)
+	DeleteUniversity(ctx context.Context, req *entity.DeleteReq) error
+	GetUniversity(ctx context.Context, id string) (*entity.University, error)
+	ListUniversity(ctx context.Context, req *entity.ListReq) (*entity.UniversityListRes, error)
+
+	CreateFaculty(ctx context.Context, faculty *entity.Faculty) (*entity.Faculty, error)
+	UpdateFaculty(ctx context.Context, faculty *entity.Faculty) (*entity.Faculty, error)
+	DeleteFaculty(ctx context.Context, req *entity.DeleteReq) error
+	GetFaculty(ctx context.Context, id string) (*entity.Faculty, error)
+	ListFaculty(ctx context.Context, universityId string, req *entity.ListReq) (*entity.FacultyListRes, error)
+
+	CreateCourse(ctx context.Context, course *entity.Course) (*entity.Course, error)
+	UpdateCourse(ctx context.Context, course *entity.Course) (*entity.Course, error)
+	DeleteCourse(ctx context.Context, req *entity.DeleteReq) error
+	GetCourse(ctx context.Context, id string) (*entity.Course, error)
+	ListCourse(ctx context.Context, facultyId string, req *entity.ListReq) (*entity.CourseListRes, error)
+}
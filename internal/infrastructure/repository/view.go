@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"univer/internal/entity"
+)
+
+// ViewBuffer dedups views and holds them until they are stored in bulk.
+type ViewBuffer interface {
+	// Record buffers a view unless the viewer already viewed the post that
+	// UTC day and returns how many views, and how many guest views among
+	// them, of the post are buffered. A view of a post the viewer saw on an
+	// earlier day is buffered too and left out when it is stored.
+	Record(ctx context.Context, view *entity.View) (int64, int64, error)
+	// Pending returns how many views of each post are buffered.
+	Pending(ctx context.Context, postIds ...string) (map[string]int64, error)
+	// Take returns the buffered views to store. Until Done is called it keeps
+	// returning the same ones, so a failed flush is retried.
+	Take(ctx context.Context) ([]*entity.View, error)
+	Done(ctx context.Context) error
+}
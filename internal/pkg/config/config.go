@@ -1,8 +1,13 @@
 package config
 
 import (
+	"errors"
 	"os"
+	"strings"
 	"time"
+	"univer/internal/pkg/app"
+
+	"github.com/spf13/cast"
 
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
@@ -20,9 +25,10 @@ type Config struct {
 	Server      struct {
 		Host         string
 		Port         string
-		ReadTimeout  string
-		WriteTimeout string
-		IdleTimeout  string
+		ReadTimeout    string
+		WriteTimeout   string
+		IdleTimeout    string
+		TrustedProxies []string
 	}
 
 	Context struct {
@@ -50,6 +56,9 @@ type Config struct {
 		RefreshTTL time.Duration
 		SignInKey  string
 	}
+	Payment struct {
+		WebhookSecret string
+	}
 	Minio struct {
 		Endpoint                 string
 		AccessKeyID              string
@@ -57,6 +66,56 @@ type Config struct {
 		Location                 string
 		ImageUrlUploadBucketName string
 		FileUploadBucketName     string
+		PreviewBucketName        string
+		PresignedURLTTL          time.Duration
+	}
+	Upload struct {
+		MaxSize       int64
+		ChunkSize     int64
+		SessionTTL    time.Duration
+		SweepInterval time.Duration
+	}
+	Converter struct {
+		Driver      string
+		Binary      string
+		Timeout     time.Duration
+		Concurrency int
+	}
+	Preview struct {
+		Driver  string
+		Binary  string
+		Pages   int
+		DPI     int
+		Width   int
+		Timeout time.Duration
+	}
+	Extract struct {
+		Driver  string
+		Binary  string
+		MaxSize int
+		Timeout time.Duration
+	}
+	Search struct {
+		SuggestLimit    int
+		SuggestCacheTTL time.Duration
+	}
+	Trending struct {
+		Interval time.Duration
+		CacheTTL time.Duration
+		Limit    int
+	}
+	Views struct {
+		FlushInterval time.Duration
+	}
+	Visitor struct {
+		Secret         string
+		CookieTTL      time.Duration
+		FingerprintTTL time.Duration
+	}
+	Related struct {
+		Interval time.Duration
+		Lookback time.Duration
+		Limit    int
 	}
 	SMTP struct {
 		Email         string
@@ -82,6 +141,11 @@ func NewConfig() (*Config, error) {
 	config.Server.ReadTimeout = getEnv("SERVER_READ_TIMEOUT", "10s")
 	config.Server.WriteTimeout = getEnv("SERVER_WRITE_TIMEOUT", "10s")
 	config.Server.IdleTimeout = getEnv("SERVER_IDLE_TIMEOUT", "120s")
+	// addresses or CIDRs of the reverse proxies whose X-Forwarded-For is
+	// believed, comma separated; none by default
+	if trustedProxies := getEnv("TRUSTED_PROXIES", ""); trustedProxies != "" {
+		config.Server.TrustedProxies = strings.Split(trustedProxies, ",")
+	}
 
 	//context configuration
 	ContexTimeout, err := time.ParseDuration(getEnv("CONTEXT_TIMEOUT", "30s"))
@@ -135,8 +199,132 @@ func NewConfig() (*Config, error) {
 	config.Minio.Endpoint = getEnv("ENDPOINT", "localhost:9000")  // minio:9000
 	config.Minio.FileUploadBucketName = getEnv("FILE_UPLOAD_BUCKET_NAME", "univer")
 	config.Minio.ImageUrlUploadBucketName = getEnv("IMAGE_URL_UPLOAD_BUCKET_NAME", "univer-image")
+	config.Minio.PreviewBucketName = getEnv("PREVIEW_BUCKET_NAME", "univer-preview")
+
+	presignedURLTTL, err := time.ParseDuration(getEnv("PRESIGNED_URL_TTL", "15m"))
+	if err != nil {
+		return nil, err
+	}
+	config.Minio.PresignedURLTTL = presignedURLTTL
+
+	// resumable upload configuration, MinIO needs every part but the last
+	// one to be at least 5 MiB
+	config.Upload.MaxSize = cast.ToInt64(getEnv("UPLOAD_MAX_SIZE", "524288000"))
+	config.Upload.ChunkSize = cast.ToInt64(getEnv("UPLOAD_CHUNK_SIZE", "8388608"))
+	if config.Upload.ChunkSize < 5<<20 {
+		config.Upload.ChunkSize = 5 << 20
+	}
+	uploadSessionTTL, err := time.ParseDuration(getEnv("UPLOAD_SESSION_TTL", "24h"))
+	if err != nil {
+		return nil, err
+	}
+	config.Upload.SessionTTL = uploadSessionTTL
+	uploadSweepInterval, err := time.ParseDuration(getEnv("UPLOAD_SWEEP_INTERVAL", "1h"))
+	if err != nil {
+		return nil, err
+	}
+	config.Upload.SweepInterval = uploadSweepInterval
+
+	// document converter configuration
+	config.Converter.Driver = getEnv("CONVERTER_DRIVER", "libreoffice") // libreoffice, fake
+	config.Converter.Binary = getEnv("LIBREOFFICE_BINARY", "soffice")
+	convertTimeout, err := time.ParseDuration(getEnv("CONVERTER_TIMEOUT", "2m"))
+	if err != nil {
+		return nil, err
+	}
+	config.Converter.Timeout = convertTimeout
+	config.Converter.Concurrency = cast.ToInt(getEnv("CONVERTER_CONCURRENCY", "2"))
+
+	// document preview configuration
+	config.Preview.Driver = getEnv("PREVIEW_DRIVER", "poppler") // poppler, fake
+	config.Preview.Binary = getEnv("PDFTOPPM_BINARY", "pdftoppm")
+	config.Preview.Pages = cast.ToInt(getEnv("PREVIEW_PAGES", "3"))
+	config.Preview.DPI = cast.ToInt(getEnv("PREVIEW_DPI", "100"))
+	config.Preview.Width = cast.ToInt(getEnv("PREVIEW_WIDTH", "480"))
+	previewTimeout, err := time.ParseDuration(getEnv("PREVIEW_TIMEOUT", "1m"))
+	if err != nil {
+		return nil, err
+	}
+	config.Preview.Timeout = previewTimeout
+
+	// text extraction configuration
+	config.Extract.Driver = getEnv("EXTRACT_DRIVER", "pdftotext") // pdftotext, fake
+	config.Extract.Binary = getEnv("PDFTOTEXT_BINARY", "pdftotext")
+	config.Extract.MaxSize = cast.ToInt(getEnv("EXTRACT_MAX_SIZE", "262144")) // bytes of text kept per post
+	extractTimeout, err := time.ParseDuration(getEnv("EXTRACT_TIMEOUT", "1m"))
+	if err != nil {
+		return nil, err
+	}
+	config.Extract.Timeout = extractTimeout
+
+	// search suggestion configuration
+	config.Search.SuggestLimit = cast.ToInt(getEnv("SUGGEST_LIMIT", "10"))
+	suggestCacheTTL, err := time.ParseDuration(getEnv("SUGGEST_CACHE_TTL", "10m"))
+	if err != nil {
+		return nil, err
+	}
+	config.Search.SuggestCacheTTL = suggestCacheTTL
+
+	// view counting configuration
+	viewsFlushInterval, err := time.ParseDuration(getEnv("VIEWS_FLUSH_INTERVAL", "10s"))
+	if err != nil {
+		return nil, err
+	}
+	config.Views.FlushInterval = viewsFlushInterval
+
+	// guest visitor configuration; the secret signs visitor cookies and must
+	// not be the token key, only develop gets a default one
+	config.Visitor.Secret = getEnv("VISITOR_SECRET", "")
+	if config.Visitor.Secret == "" && config.Environment == app.EnvironmentDevelop {
+		config.Visitor.Secret = "debug-visitor"
+	}
+	if config.Visitor.Secret == "" {
+		return nil, errors.New("VISITOR_SECRET is required outside develop")
+	}
+	if config.Visitor.Secret == config.Token.SignInKey {
+		return nil, errors.New("VISITOR_SECRET must differ from TOKEN_SIGNIN_KEY")
+	}
+	visitorCookieTTL, err := time.ParseDuration(getEnv("VISITOR_COOKIE_TTL", "8760h"))
+	if err != nil {
+		return nil, err
+	}
+	config.Visitor.CookieTTL = visitorCookieTTL
+	visitorFingerprintTTL, err := time.ParseDuration(getEnv("VISITOR_FINGERPRINT_TTL", "24h"))
+	if err != nil {
+		return nil, err
+	}
+	config.Visitor.FingerprintTTL = visitorFingerprintTTL
+
+	// payment provider configuration; without a secret no payment can be
+	// confirmed
+	config.Payment.WebhookSecret = getEnv("PAYMENT_WEBHOOK_SECRET", "")
+
+	// trending posts configuration
+	trendingInterval, err := time.ParseDuration(getEnv("TRENDING_INTERVAL", "15m"))
+	if err != nil {
+		return nil, err
+	}
+	config.Trending.Interval = trendingInterval
+	trendingCacheTTL, err := time.ParseDuration(getEnv("TRENDING_CACHE_TTL", "5m"))
+	if err != nil {
+		return nil, err
+	}
+	config.Trending.CacheTTL = trendingCacheTTL
+	config.Trending.Limit = cast.ToInt(getEnv("TRENDING_LIMIT", "100"))
+
+	// related posts configuration
+	relatedInterval, err := time.ParseDuration(getEnv("RELATED_INTERVAL", "1h"))
+	if err != nil {
+		return nil, err
+	}
+	config.Related.Interval = relatedInterval
+	relatedLookback, err := time.ParseDuration(getEnv("RELATED_LOOKBACK", "2160h"))
+	if err != nil {
+		return nil, err
+	}
+	config.Related.Lookback = relatedLookback
+	config.Related.Limit = cast.ToInt(getEnv("RELATED_LIMIT", "30"))
 
-	
 	return &config, nil
 }
 
@@ -0,0 +1,29 @@
+package converter
+
+import (
+	"context"
+	"io"
+	"strings"
+)
+
+// Converter turns an office document into a PDF.
+type Converter interface {
+	// Convert reads the document from src, name is only used to pick the input
+	// format by its extension, and returns the PDF bytes.
+	Convert(ctx context.Context, name string, src io.Reader) ([]byte, error)
+}
+
+var convertible = map[string]bool{
+	".doc":  true,
+	".docx": true,
+	".ppt":  true,
+	".pptx": true,
+	".xls":  true,
+	".xlsx": true,
+	".xlsm": true,
+}
+
+// Convertible reports whether files with the extension ext can be converted.
+func Convertible(ext string) bool {
+	return convertible[strings.ToLower(ext)]
+}
@@ -0,0 +1,39 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+)
+
+// minimalPDF is a valid single blank page document.
+const minimalPDF = "%PDF-1.4\n" +
+	"1 0 obj<</Type/Catalog/Pages 2 0 R>>endobj\n" +
+	"2 0 obj<</Type/Pages/Kids[3 0 R]/Count 1>>endobj\n" +
+	"3 0 obj<</Type/Page/Parent 2 0 R/MediaBox[0 0 595 842]>>endobj\n" +
+	"trailer<</Root 1 0 R>>\n" +
+	"%%EOF\n"
+
+// Fake is a Converter for tests and local runs without LibreOffice installed.
+// It drains the input and returns PDF, or Err when it is set.
+type Fake struct {
+	PDF []byte
+	Err error
+}
+
+func (f *Fake) Convert(ctx context.Context, name string, src io.Reader) ([]byte, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	if !Convertible(filepath.Ext(name)) {
+		return nil, fmt.Errorf("converter: unsupported file type %q", filepath.Ext(name))
+	}
+	if _, err := io.Copy(io.Discard, src); err != nil {
+		return nil, err
+	}
+	if f.PDF != nil {
+		return f.PDF, nil
+	}
+	return []byte(minimalPDF), nil
+}
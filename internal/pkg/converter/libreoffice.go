@@ -0,0 +1,80 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LibreOffice converts documents by running LibreOffice in headless mode.
+type LibreOffice struct {
+	binary  string
+	timeout time.Duration
+}
+
+func NewLibreOffice(binary string, timeout time.Duration) *LibreOffice {
+	return &LibreOffice{
+		binary:  binary,
+		timeout: timeout,
+	}
+}
+
+func (l *LibreOffice) Convert(ctx context.Context, name string, src io.Reader) ([]byte, error) {
+	ext := strings.ToLower(filepath.Ext(name))
+	if !Convertible(ext) {
+		return nil, fmt.Errorf("converter: unsupported file type %q", ext)
+	}
+
+	dir, err := os.MkdirTemp("", "convert-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "input"+ext)
+	file, err := os.Create(input)
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.Copy(file, src)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if l.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.timeout)
+		defer cancel()
+	}
+
+	// every run gets its own profile directory, LibreOffice refuses to start
+	// a second instance on a shared one
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, l.binary,
+		"-env:UserInstallation=file://"+filepath.ToSlash(filepath.Join(dir, "profile")),
+		"--headless",
+		"--convert-to", "pdf",
+		"--outdir", dir,
+		input,
+	)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("converter: libreoffice: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	pdf, err := os.ReadFile(filepath.Join(dir, "input.pdf"))
+	if err != nil {
+		return nil, fmt.Errorf("converter: libreoffice produced no pdf: %w", err)
+	}
+
+	return pdf, nil
+}
@@ -0,0 +1,34 @@
+package converter
+
+import (
+	"context"
+	"io"
+)
+
+// Limited is a Converter that runs at most a fixed number of conversions at
+// once; the rest wait for a free slot or for their context to end.
+type Limited struct {
+	converter Converter
+	slots     chan struct{}
+}
+
+func NewLimited(converter Converter, concurrency int) *Limited {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &Limited{
+		converter: converter,
+		slots:     make(chan struct{}, concurrency),
+	}
+}
+
+func (l *Limited) Convert(ctx context.Context, name string, src io.Reader) ([]byte, error) {
+	select {
+	case l.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-l.slots }()
+
+	return l.converter.Convert(ctx, name, src)
+}
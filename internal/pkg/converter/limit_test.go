@@ -0,0 +1,132 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// gate is a document whose conversion runs until release is closed.
+type gate struct {
+	once    sync.Once
+	active  *atomic.Int32
+	peak    *atomic.Int32
+	started chan<- struct{}
+	release <-chan struct{}
+}
+
+func (g *gate) Read(p []byte) (int, error) {
+	g.once.Do(func() {
+		n := g.active.Add(1)
+		for {
+			peak := g.peak.Load()
+			if n <= peak || g.peak.CompareAndSwap(peak, n) {
+				break
+			}
+		}
+		g.started <- struct{}{}
+		<-g.release
+		g.active.Add(-1)
+	})
+	return 0, io.EOF
+}
+
+func TestLimitedConcurrency(t *testing.T) {
+	const concurrency, conversions = 2, 5
+
+	var active, peak atomic.Int32
+	started := make(chan struct{}, conversions)
+	release := make(chan struct{})
+	limited := NewLimited(&Fake{}, concurrency)
+
+	errs := make(chan error, conversions)
+	for i := 0; i < conversions; i++ {
+		go func() {
+			_, err := limited.Convert(context.Background(), "lecture.docx", &gate{
+				active:  &active,
+				peak:    &peak,
+				started: started,
+				release: release,
+			})
+			errs <- err
+		}()
+	}
+
+	for i := 0; i < concurrency; i++ {
+		<-started
+	}
+	select {
+	case <-started:
+		t.Fatalf("more than %d conversions started at once", concurrency)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	for i := 0; i < conversions; i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("Convert() error = %v", err)
+		}
+	}
+	if got := peak.Load(); got > concurrency {
+		t.Errorf("%d conversions ran at once, want at most %d", got, concurrency)
+	}
+}
+
+func TestLimitedCancel(t *testing.T) {
+	var active, peak atomic.Int32
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	limited := NewLimited(&Fake{}, 1)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := limited.Convert(context.Background(), "lecture.docx", &gate{
+			active:  &active,
+			peak:    &peak,
+			started: started,
+			release: release,
+		})
+		done <- err
+	}()
+	<-started
+
+	// a caller that gives up while every slot is taken does not keep one
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := limited.Convert(ctx, "lecture.docx", eofReader{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Convert() error = %v, want %v", err, context.Canceled)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := limited.Convert(ctx, "lecture.docx", eofReader{}); err != nil {
+		t.Fatalf("Convert() error = %v, want the slot to be free", err)
+	}
+}
+
+func TestLimitedReleasesOnError(t *testing.T) {
+	fail := errors.New("conversion failed")
+	limited := NewLimited(&Fake{Err: fail}, 1)
+
+	for i := 0; i < 2; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		_, err := limited.Convert(ctx, "lecture.docx", eofReader{})
+		cancel()
+		if !errors.Is(err, fail) {
+			t.Fatalf("Convert() error = %v, want %v", err, fail)
+		}
+	}
+}
+
+type eofReader struct{}
+
+func (eofReader) Read([]byte) (int, error) { return 0, io.EOF }
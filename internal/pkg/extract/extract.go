@@ -0,0 +1,27 @@
+// Package extract pulls the plain text out of post files for search.
+package extract
+
+import (
+	"context"
+	"io"
+	"strings"
+)
+
+// Document is the text of a file and the number of pages it spans.
+type Document struct {
+	Text  string
+	Pages int
+}
+
+// Extractor pulls the text out of a PDF document.
+type Extractor interface {
+	Extract(ctx context.Context, pdf io.Reader) (*Document, error)
+}
+
+// Truncate cuts the text of d to at most max bytes without splitting a rune.
+func (d *Document) Truncate(max int) {
+	if max <= 0 || len(d.Text) <= max {
+		return
+	}
+	d.Text = strings.ToValidUTF8(d.Text[:max], "")
+}
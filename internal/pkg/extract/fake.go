@@ -0,0 +1,25 @@
+package extract
+
+import (
+	"context"
+	"io"
+)
+
+// Fake is an Extractor for tests and local runs without poppler installed. It
+// returns Document, or Err when it is set.
+type Fake struct {
+	Document Document
+	Err      error
+}
+
+func (f *Fake) Extract(ctx context.Context, pdf io.Reader) (*Document, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	if _, err := io.Copy(io.Discard, pdf); err != nil {
+		return nil, err
+	}
+
+	document := f.Document
+	return &document, nil
+}
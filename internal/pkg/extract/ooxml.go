@@ -0,0 +1,171 @@
+package extract
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxPartSize bounds how much of a single XML part is read.
+const maxPartSize = 64 << 20
+
+// ErrUnsupported is returned by OOXML for extensions it cannot read.
+var ErrUnsupported = errors.New("extract: unsupported file type")
+
+// OOXML reads the text of a .docx, .pptx, .xlsx or .xlsm file straight from
+// its XML parts. Pages are the page count Word stored, the number of slides or
+// the number of sheets.
+func OOXML(r io.ReaderAt, size int64, ext string) (*Document, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("extract: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	var text strings.Builder
+	switch strings.ToLower(ext) {
+	case ".docx":
+		if err := readText(files["word/document.xml"], &text, "t", "p"); err != nil {
+			return nil, err
+		}
+		return &Document{
+			Text:  strings.TrimSpace(text.String()),
+			Pages: wordPages(files["docProps/app.xml"]),
+		}, nil
+	case ".pptx":
+		slides := numbered(files, "ppt/slides/slide")
+		for _, slide := range slides {
+			if err := readText(slide, &text, "t", "p"); err != nil {
+				return nil, err
+			}
+		}
+		return &Document{
+			Text:  strings.TrimSpace(text.String()),
+			Pages: len(slides),
+		}, nil
+	case ".xlsx", ".xlsm":
+		// cell text lives in the shared string table; numbers are skipped
+		if err := readText(files["xl/sharedStrings.xml"], &text, "t", "si"); err != nil {
+			return nil, err
+		}
+		sheets := numbered(files, "xl/worksheets/sheet")
+		for _, sheet := range sheets {
+			if err := readText(sheet, &text, "t", "row"); err != nil {
+				return nil, err
+			}
+		}
+		return &Document{
+			Text:  strings.TrimSpace(text.String()),
+			Pages: len(sheets),
+		}, nil
+	default:
+		return nil, ErrUnsupported
+	}
+}
+
+// readText appends the character data of every textElem element of an XML
+// part to w and ends a line after every breakElem. A missing part is empty.
+func readText(file *zip.File, w *strings.Builder, textElem, breakElem string) error {
+	if file == nil {
+		return nil
+	}
+	rc, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("extract: %s: %w", file.Name, err)
+	}
+	defer rc.Close()
+
+	decoder := xml.NewDecoder(io.LimitReader(rc, maxPartSize))
+	inText := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("extract: %s: %w", file.Name, err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case textElem:
+				inText = true
+			case "tab":
+				w.WriteByte(' ')
+			case "br":
+				w.WriteByte('\n')
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case textElem:
+				inText = false
+			case breakElem:
+				w.WriteByte('\n')
+			}
+		case xml.CharData:
+			if inText {
+				w.Write(t)
+			}
+		}
+	}
+}
+
+// numbered returns the parts named prefix1.xml, prefix2.xml, ... in number
+// order.
+func numbered(files map[string]*zip.File, prefix string) []*zip.File {
+	type part struct {
+		number int
+		file   *zip.File
+	}
+
+	var parts []part
+	for name, file := range files {
+		if path.Dir(name) != path.Dir(prefix) || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ".xml") {
+			continue
+		}
+		number, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".xml"))
+		if err != nil {
+			continue
+		}
+		parts = append(parts, part{number, file})
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].number < parts[j].number })
+
+	result := make([]*zip.File, 0, len(parts))
+	for _, p := range parts {
+		result = append(result, p.file)
+	}
+	return result
+}
+
+// wordPages reads the page count Word saved in docProps/app.xml, 0 if it is
+// missing.
+func wordPages(file *zip.File) int {
+	if file == nil {
+		return 0
+	}
+	rc, err := file.Open()
+	if err != nil {
+		return 0
+	}
+	defer rc.Close()
+
+	var props struct {
+		Pages int `xml:"Pages"`
+	}
+	if err := xml.NewDecoder(io.LimitReader(rc, maxPartSize)).Decode(&props); err != nil {
+		return 0
+	}
+	return props.Pages
+}
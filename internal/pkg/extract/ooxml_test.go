@@ -0,0 +1,119 @@
+package extract
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type entry struct {
+	name string
+	data string
+}
+
+func buildZip(t *testing.T, entries ...entry) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, e := range entries {
+		f, err := w.Create(e.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(e.data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestOOXML(t *testing.T) {
+	document := entry{"word/document.xml", `<?xml version="1.0"?>
+<w:document xmlns:w="w"><w:body>
+<w:p><w:r><w:t>Lecture</w:t></w:r><w:r><w:tab/><w:t>one</w:t></w:r></w:p>
+<w:p><w:r><w:t>Line</w:t><w:br/><w:t>two</w:t></w:r></w:p>
+</w:body></w:document>`}
+	slide := func(n, text string) entry {
+		return entry{"ppt/slides/slide" + n + ".xml", `<p:sld xmlns:p="p" xmlns:a="a"><a:p><a:r><a:t>` + text + `</a:t></a:r></a:p></p:sld>`}
+	}
+
+	tests := []struct {
+		name    string
+		content []byte
+		ext     string
+		doc     *Document
+		err     error
+	}{
+		{
+			name: "docx",
+			content: buildZip(t, document,
+				entry{"docProps/app.xml", `<Properties><Pages>2</Pages></Properties>`}),
+			ext: ".docx",
+			doc: &Document{Text: "Lecture one\nLine\ntwo", Pages: 2},
+		},
+		{
+			name:    "docx without a page count",
+			content: buildZip(t, document),
+			ext:     ".DOCX",
+			doc:     &Document{Text: "Lecture one\nLine\ntwo"},
+		},
+		{
+			name: "pptx slides in number order",
+			content: buildZip(t, slide("10", "Tenth"), slide("2", "Second"), slide("1", "First"),
+				entry{"ppt/slides/_rels/slide1.xml.rels", `<Relationships/>`}),
+			ext: ".pptx",
+			doc: &Document{Text: "First\nSecond\nTenth", Pages: 3},
+		},
+		{
+			name: "xlsx",
+			content: buildZip(t,
+				entry{"xl/sharedStrings.xml", `<sst><si><t>Name</t></si><si><t>Grade</t></si></sst>`},
+				entry{"xl/worksheets/sheet1.xml", `<worksheet><sheetData><row><c t="s"><v>0</v></c><c t="inlineStr"><is><t>Inline</t></is></c></row></sheetData></worksheet>`},
+				entry{"xl/worksheets/sheet2.xml", `<worksheet><sheetData><row><c><v>5</v></c></row></sheetData></worksheet>`}),
+			ext: ".xlsm",
+			doc: &Document{Text: "Name\nGrade\nInline", Pages: 2},
+		},
+		{
+			name:    "docx without a document",
+			content: buildZip(t, entry{"[Content_Types].xml", `<Types/>`}),
+			ext:     ".docx",
+			doc:     &Document{},
+		},
+		{
+			name:    "unsupported extension",
+			content: buildZip(t, document),
+			ext:     ".doc",
+			err:     ErrUnsupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := OOXML(bytes.NewReader(tt.content), int64(len(tt.content)), tt.ext)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("OOXML() error = %v, want %v", err, tt.err)
+			}
+			if tt.doc == nil {
+				return
+			}
+			if doc.Text != tt.doc.Text {
+				t.Errorf("OOXML() text = %q, want %q", doc.Text, tt.doc.Text)
+			}
+			if doc.Pages != tt.doc.Pages {
+				t.Errorf("OOXML() pages = %d, want %d", doc.Pages, tt.doc.Pages)
+			}
+		})
+	}
+}
+
+func TestOOXMLNotAnArchive(t *testing.T) {
+	content := []byte("%PDF-1.7")
+	if _, err := OOXML(bytes.NewReader(content), int64(len(content)), ".docx"); err == nil {
+		t.Fatal("OOXML() error = nil, want an error")
+	}
+}
@@ -0,0 +1,68 @@
+package extract
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// PdfToText extracts text with pdftotext from poppler-utils.
+type PdfToText struct {
+	binary  string
+	timeout time.Duration
+}
+
+func NewPdfToText(binary string, timeout time.Duration) *PdfToText {
+	return &PdfToText{
+		binary:  binary,
+		timeout: timeout,
+	}
+}
+
+func (p *PdfToText) Extract(ctx context.Context, pdf io.Reader) (*Document, error) {
+	dir, err := os.MkdirTemp("", "extract-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "input.pdf")
+	file, err := os.Create(input)
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.Copy(file, pdf)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.binary, "-enc", "UTF-8", input, "-")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("extract: pdftotext: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	// pdftotext ends every page with a form feed
+	text := stdout.String()
+	return &Document{
+		Text:  strings.TrimSpace(strings.ReplaceAll(text, "\f", "\n")),
+		Pages: strings.Count(text, "\f"),
+	}, nil
+}
@@ -0,0 +1,142 @@
+package filetype
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Kind is the format of a file as recognised from its content.
+type Kind string
+
+const (
+	Unknown Kind = ""
+	PDF     Kind = "pdf"
+	OLE     Kind = "ole" // legacy .doc, .ppt and .xls compound documents
+	DOCX    Kind = "docx"
+	PPTX    Kind = "pptx"
+	XLSX    Kind = "xlsx"
+	ZIP     Kind = "zip"
+)
+
+var (
+	ErrUnknown    = errors.New("file type is not supported")
+	ErrMismatch   = errors.New("file content does not match its extension")
+	ErrExecutable = errors.New("archive contains an executable file")
+	ErrZipBomb    = errors.New("archive is too large when unpacked")
+	ErrCorrupt    = errors.New("archive is corrupt")
+)
+
+var (
+	magicPDF = []byte("%PDF-")
+	magicOLE = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+	magicZIP = [][]byte{
+		[]byte("PK\x03\x04"),
+		[]byte("PK\x05\x06"), // empty archive
+	}
+	magicExecutable = [][]byte{
+		[]byte("MZ"),             // Windows PE
+		[]byte("\x7fELF"),        // Linux
+		{0xCF, 0xFA, 0xED, 0xFE}, // Mach-O 64-bit
+		{0xCE, 0xFA, 0xED, 0xFE}, // Mach-O 32-bit
+		{0xCA, 0xFE, 0xBA, 0xBE}, // Mach-O universal, Java class
+		[]byte("#!"),             // scripts
+	}
+)
+
+// extensions lists the kinds of content accepted for each file extension.
+var extensions = map[string][]Kind{
+	".pdf":  {PDF},
+	".doc":  {OLE},
+	".ppt":  {OLE},
+	".xls":  {OLE},
+	".docx": {DOCX},
+	".pptx": {PPTX},
+	".xlsx": {XLSX},
+	".xlsm": {XLSX},
+	".zip":  {ZIP},
+}
+
+var contentTypes = map[string]string{
+	".pdf":  "application/pdf",
+	".doc":  "application/msword",
+	".ppt":  "application/vnd.ms-powerpoint",
+	".xls":  "application/vnd.ms-excel",
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	".xlsm": "application/vnd.ms-excel.sheet.macroEnabled.12",
+	".zip":  "application/zip",
+}
+
+// Detect recognises the format of the size bytes readable from r by their
+// magic bytes. ZIP containers are inspected for executables and zip bombs,
+// and opened to tell OOXML documents apart from plain archives.
+func Detect(r io.ReaderAt, size int64) (Kind, error) {
+	header := make([]byte, len(magicOLE))
+	n, err := r.ReadAt(header, 0)
+	if err != nil && err != io.EOF {
+		return Unknown, err
+	}
+	header = header[:n]
+
+	switch {
+	case bytes.HasPrefix(header, magicPDF):
+		return PDF, nil
+	case bytes.HasPrefix(header, magicOLE):
+		return OLE, nil
+	case hasAnyPrefix(header, magicZIP):
+		kind, err := detectZip(r, size)
+		if err != nil {
+			return Unknown, err
+		}
+		return kind, nil
+	}
+
+	return Unknown, ErrUnknown
+}
+
+// Validate checks that the content of a file matches its extension and returns
+// the content type it should be stored with.
+func Validate(r io.ReaderAt, size int64, ext string) (string, error) {
+	ext = strings.ToLower(ext)
+	allowed, ok := extensions[ext]
+	if !ok {
+		return "", ErrUnknown
+	}
+
+	kind, err := Detect(r, size)
+	if errors.Is(err, ErrUnknown) {
+		return "", fmt.Errorf("%w: content is not a %s file", ErrMismatch, ext)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	for _, k := range allowed {
+		if k == kind {
+			return contentTypes[ext], nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s is not a %s file", ErrMismatch, kind, ext)
+}
+
+// ContentType returns the MIME type for a supported file extension.
+func ContentType(ext string) string {
+	if contentType, ok := contentTypes[strings.ToLower(ext)]; ok {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+func hasAnyPrefix(b []byte, prefixes [][]byte) bool {
+	for _, prefix := range prefixes {
+		if bytes.HasPrefix(b, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,223 @@
+package filetype
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"hash/crc32"
+	"math/rand"
+	"testing"
+)
+
+type entry struct {
+	name string
+	data []byte
+}
+
+func buildZip(t *testing.T, entries ...entry) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, e := range entries {
+		f, err := w.Create(e.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write(e.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// corruptZip builds an archive whose only entry does not match its checksum.
+func corruptZip(t *testing.T, e entry) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	f, err := w.CreateRaw(&zip.FileHeader{
+		Name:               e.name,
+		Method:             zip.Store,
+		CRC32:              crc32.ChecksumIEEE(e.data) + 1,
+		CompressedSize64:   uint64(len(e.data)),
+		UncompressedSize64: uint64(len(e.data)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write(e.data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func docx(t *testing.T, extra ...entry) []byte {
+	return buildZip(t, append([]entry{
+		{"[Content_Types].xml", []byte(`<?xml version="1.0"?><Types/>`)},
+		{"word/document.xml", []byte(`<?xml version="1.0"?><document/>`)},
+	}, extra...)...)
+}
+
+func TestValidate(t *testing.T) {
+	pdf := []byte("%PDF-1.7\n%%EOF")
+	ole := append(append([]byte{}, magicOLE...), make([]byte, 64)...)
+	plain := buildZip(t, entry{"notes.txt", []byte("lecture notes")})
+	noise := make([]byte, maxNestedSize+1)
+	rand.New(rand.NewSource(1)).Read(noise)
+
+	tests := []struct {
+		name        string
+		content     []byte
+		ext         string
+		contentType string
+		err         error
+	}{
+		{
+			name:        "pdf",
+			content:     pdf,
+			ext:         ".pdf",
+			contentType: "application/pdf",
+		},
+		{
+			name:        "extension is case insensitive",
+			content:     pdf,
+			ext:         ".PDF",
+			contentType: "application/pdf",
+		},
+		{
+			name:        "legacy word document",
+			content:     ole,
+			ext:         ".doc",
+			contentType: "application/msword",
+		},
+		{
+			name:        "docx",
+			content:     docx(t),
+			ext:         ".docx",
+			contentType: "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+		},
+		{
+			name:        "plain zip",
+			content:     plain,
+			ext:         ".zip",
+			contentType: "application/zip",
+		},
+		{
+			name:    "unsupported extension",
+			content: pdf,
+			ext:     ".txt",
+			err:     ErrUnknown,
+		},
+		{
+			name:    "pdf renamed to docx",
+			content: pdf,
+			ext:     ".docx",
+			err:     ErrMismatch,
+		},
+		{
+			name:    "unknown content",
+			content: []byte("just some text"),
+			ext:     ".pdf",
+			err:     ErrMismatch,
+		},
+		{
+			name:    "docx uploaded as zip",
+			content: docx(t),
+			ext:     ".zip",
+			err:     ErrMismatch,
+		},
+		{
+			name:    "zip with an executable",
+			content: buildZip(t, entry{"setup.exe", []byte("MZ")}),
+			ext:     ".zip",
+			err:     ErrExecutable,
+		},
+		{
+			name:    "zip with a renamed executable",
+			content: buildZip(t, entry{"readme.txt", []byte("MZ\x90\x00")}),
+			ext:     ".zip",
+			err:     ErrExecutable,
+		},
+		{
+			name:    "zip with an executable in a nested zip",
+			content: buildZip(t, entry{"inner.zip", buildZip(t, entry{"run.sh", []byte("#!/bin/sh")})}),
+			ext:     ".zip",
+			err:     ErrExecutable,
+		},
+		{
+			name:    "zip with a large nested zip",
+			content: buildZip(t, entry{"inner.zip", buildZip(t, entry{"noise.bin", noise})}),
+			ext:     ".zip",
+			err:     ErrZipBomb,
+		},
+		{
+			name:    "zip with a corrupt entry",
+			content: corruptZip(t, entry{"notes.txt", []byte("lecture notes")}),
+			ext:     ".zip",
+			err:     ErrCorrupt,
+		},
+		{
+			name:    "docx with an executable",
+			content: docx(t, entry{"evil.exe", []byte("MZ")}),
+			ext:     ".docx",
+			err:     ErrExecutable,
+		},
+		{
+			name:    "zip bomb",
+			content: buildZip(t, entry{"zeros.txt", make([]byte, 1<<20)}),
+			ext:     ".zip",
+			err:     ErrZipBomb,
+		},
+		{
+			name:    "docx bomb",
+			content: docx(t, entry{"word/media/zeros.bin", make([]byte, 1<<20)}),
+			ext:     ".docx",
+			err:     ErrZipBomb,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contentType, err := Validate(bytes.NewReader(tt.content), int64(len(tt.content)), tt.ext)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Validate() error = %v, want %v", err, tt.err)
+			}
+			if contentType != tt.contentType {
+				t.Errorf("Validate() content type = %q, want %q", contentType, tt.contentType)
+			}
+		})
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name    string
+		content []byte
+		kind    Kind
+	}{
+		{"docx", docx(t), DOCX},
+		{"pptx", buildZip(t, entry{"[Content_Types].xml", nil}, entry{"ppt/presentation.xml", nil}), PPTX},
+		{"xlsx", buildZip(t, entry{"[Content_Types].xml", nil}, entry{"xl/workbook.xml", nil}), XLSX},
+		{"word folder without content types", buildZip(t, entry{"word/document.xml", nil}), ZIP},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, err := Detect(bytes.NewReader(tt.content), int64(len(tt.content)))
+			if err != nil {
+				t.Fatalf("Detect() error = %v", err)
+			}
+			if kind != tt.kind {
+				t.Errorf("Detect() = %q, want %q", kind, tt.kind)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package filetype
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+const (
+	// maxUnpackedSize caps the total uncompressed size of an archive,
+	// nested archives included.
+	maxUnpackedSize = 1 << 30
+	// maxRatio caps the compression ratio of a single entry, real documents
+	// stay far below it while zip bombs are well above.
+	maxRatio = 100
+	// maxEntries caps the number of files in an archive.
+	maxEntries = 10000
+	// maxDepth caps how deep nested archives are opened.
+	maxDepth = 3
+	// maxNestedSize caps the uncompressed size of a nested archive, which is
+	// held in memory while it is inspected.
+	maxNestedSize = 4 << 20
+)
+
+var executableExtensions = map[string]bool{
+	".exe": true, ".dll": true, ".com": true, ".scr": true, ".msi": true,
+	".bat": true, ".cmd": true, ".ps1": true, ".vbs": true, ".vbe": true,
+	".jse": true, ".wsf": true, ".hta": true, ".cpl": true, ".jar": true,
+	".apk": true, ".app": true, ".dmg": true, ".lnk": true,
+}
+
+// detectZip tells OOXML documents apart from plain archives. Both are
+// inspected the same way, an OOXML document can carry any file a plain
+// archive can.
+func detectZip(r io.ReaderAt, size int64) (Kind, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return Unknown, fmt.Errorf("%w: %v", ErrUnknown, err)
+	}
+
+	var budget int64 = maxUnpackedSize
+	if err := inspect(archive, 1, &budget); err != nil {
+		return Unknown, err
+	}
+
+	if kind := ooxmlKind(archive); kind != Unknown {
+		return kind, nil
+	}
+	return ZIP, nil
+}
+
+// ooxmlKind tells Word, PowerPoint and Excel documents apart by the folder of
+// their main part.
+func ooxmlKind(archive *zip.Reader) Kind {
+	var contentTypes bool
+	kind := Unknown
+	for _, file := range archive.File {
+		switch {
+		case file.Name == "[Content_Types].xml":
+			contentTypes = true
+		case strings.HasPrefix(file.Name, "word/"):
+			kind = DOCX
+		case strings.HasPrefix(file.Name, "ppt/"):
+			kind = PPTX
+		case strings.HasPrefix(file.Name, "xl/"):
+			kind = XLSX
+		}
+	}
+	if !contentTypes {
+		return Unknown
+	}
+	return kind
+}
+
+func checkSizes(archive *zip.Reader, budget *int64) error {
+	if len(archive.File) > maxEntries {
+		return fmt.Errorf("%w: more than %d files", ErrZipBomb, maxEntries)
+	}
+	for _, file := range archive.File {
+		*budget -= int64(file.UncompressedSize64)
+		if *budget < 0 {
+			return ErrZipBomb
+		}
+		if file.CompressedSize64 > 0 && file.UncompressedSize64/file.CompressedSize64 > maxRatio {
+			return fmt.Errorf("%w: %s", ErrZipBomb, file.Name)
+		}
+	}
+	return nil
+}
+
+// inspect rejects archives that contain executables or unpack to more than the
+// remaining budget. Nested zip archives are opened up to maxDepth levels.
+func inspect(archive *zip.Reader, depth int, budget *int64) error {
+	if err := checkSizes(archive, budget); err != nil {
+		return err
+	}
+
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		if executableExtensions[strings.ToLower(path.Ext(file.Name))] {
+			return fmt.Errorf("%w: %s", ErrExecutable, file.Name)
+		}
+
+		header, nested, err := readEntry(file, depth)
+		if err != nil {
+			return err
+		}
+		if hasAnyPrefix(header, magicExecutable) {
+			return fmt.Errorf("%w: %s", ErrExecutable, file.Name)
+		}
+		if nested == nil {
+			continue
+		}
+
+		nestedArchive, err := zip.NewReader(bytes.NewReader(nested), int64(len(nested)))
+		if err != nil {
+			continue
+		}
+		if err := inspect(nestedArchive, depth+1, budget); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readEntry reads a zip entry to its end, so an entry that does not match its
+// checksum fails, and returns its first bytes. A nested archive is returned
+// whole to be opened in turn, as long as it is within maxDepth and
+// maxNestedSize.
+func readEntry(file *zip.File, depth int) ([]byte, []byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s: %v", ErrCorrupt, file.Name, err)
+	}
+	defer rc.Close()
+
+	header := make([]byte, 8)
+	n, err := io.ReadFull(rc, header)
+	header = header[:n]
+	switch {
+	case err == io.EOF || err == io.ErrUnexpectedEOF:
+		return header, nil, nil
+	case err != nil:
+		return nil, nil, fmt.Errorf("%w: %s: %v", ErrCorrupt, file.Name, err)
+	}
+
+	if !hasAnyPrefix(header, magicZIP) {
+		if _, err := io.Copy(io.Discard, rc); err != nil {
+			return nil, nil, fmt.Errorf("%w: %s: %v", ErrCorrupt, file.Name, err)
+		}
+		return header, nil, nil
+	}
+
+	if depth >= maxDepth {
+		return nil, nil, fmt.Errorf("%w: archives nested deeper than %d levels", ErrZipBomb, maxDepth)
+	}
+	if file.UncompressedSize64 > maxNestedSize {
+		return nil, nil, fmt.Errorf("%w: nested archive %s is larger than %d MB", ErrZipBomb, file.Name, maxNestedSize>>20)
+	}
+	// the reader fails an entry that unpacks to more than its declared size,
+	// which was already taken from the budget
+	nested := bytes.NewBuffer(make([]byte, 0, file.UncompressedSize64))
+	nested.Write(header)
+	if _, err := io.Copy(nested, rc); err != nil {
+		return nil, nil, fmt.Errorf("%w: %s: %v", ErrCorrupt, file.Name, err)
+	}
+
+	return header, nested.Bytes(), nil
+}
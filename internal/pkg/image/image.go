@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/golang/freetype"
+	xdraw "golang.org/x/image/draw"
 	"golang.org/x/image/math/fixed"
 )
 
@@ -63,3 +64,18 @@ func getFirstTwoLetters(name string) string {
 
 	return strings.ToUpper(name[:1])
 }
+
+// Thumbnail scales src down to the given width keeping its aspect ratio.
+// Images that are already narrower are returned unchanged.
+func Thumbnail(src image.Image, width int) image.Image {
+	bounds := src.Bounds()
+	if bounds.Dx() <= width {
+		return src
+	}
+
+	height := bounds.Dy() * width / bounds.Dx()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, xdraw.Over, nil)
+
+	return dst
+}
@@ -8,7 +8,10 @@ import (
 	"github.com/minio/minio-go/v7"
 )
 
-func MinIOBucket(bucketName string, minIO *minio.Client) error {
+// MinIOBucket creates bucketName if it does not exist yet. Public buckets get an
+// anonymous s3:GetObject policy, private ones have any existing policy removed
+// so their objects are only reachable through presigned URLs.
+func MinIOBucket(bucketName string, public bool, minIO *minio.Client) error {
 
 	err := minIO.MakeBucket(context.Background(), bucketName, minio.MakeBucketOptions{})
 	if err != nil {
@@ -20,6 +23,15 @@ func MinIOBucket(bucketName string, minIO *minio.Client) error {
 		}
 	}
 
+	if !public {
+		err = minIO.SetBucketPolicy(context.Background(), bucketName, "")
+		if err != nil {
+			log.Println(err.Error())
+			return err
+		}
+		return nil
+	}
+
 	policy := fmt.Sprintf(`{
 			"Version": "2012-10-17",
 			"Statement": [
@@ -0,0 +1,32 @@
+package minio
+
+import (
+	"context"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// AbortStaleUploads aborts the multipart uploads in bucketName that were
+// started more than olderThan ago, so parts of abandoned resumable uploads do
+// not pile up in the bucket. It returns how many uploads were aborted.
+func AbortStaleUploads(ctx context.Context, minIO *minio.Client, bucketName string, olderThan time.Duration) (int, error) {
+	core := minio.Core{Client: minIO}
+	cutoff := time.Now().Add(-olderThan)
+
+	var aborted int
+	for upload := range minIO.ListIncompleteUploads(ctx, bucketName, "", true) {
+		if upload.Err != nil {
+			return aborted, upload.Err
+		}
+		if upload.Initiated.After(cutoff) {
+			continue
+		}
+		if err := core.AbortMultipartUpload(ctx, bucketName, upload.Key, upload.UploadID); err != nil {
+			return aborted, err
+		}
+		aborted++
+	}
+
+	return aborted, nil
+}
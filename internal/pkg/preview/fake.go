@@ -0,0 +1,39 @@
+package preview
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+)
+
+// Fake is a Renderer for tests and local runs without poppler installed. It
+// returns blank A4-shaped pages, or Err when it is set.
+type Fake struct {
+	Pages int
+	Err   error
+}
+
+func (f *Fake) Render(ctx context.Context, pdf io.Reader, pages int) ([]image.Image, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	if _, err := io.Copy(io.Discard, pdf); err != nil {
+		return nil, err
+	}
+
+	count := pages
+	if f.Pages != 0 && f.Pages < count {
+		count = f.Pages
+	}
+
+	images := make([]image.Image, 0, count)
+	for i := 0; i < count; i++ {
+		img := image.NewRGBA(image.Rect(0, 0, 595, 842))
+		draw.Draw(img, img.Bounds(), &image.Uniform{color.White}, image.Point{}, draw.Src)
+		images = append(images, img)
+	}
+
+	return images, nil
+}
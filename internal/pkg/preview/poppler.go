@@ -0,0 +1,102 @@
+package preview
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Poppler renders pages with pdftoppm from poppler-utils.
+type Poppler struct {
+	binary  string
+	dpi     int
+	timeout time.Duration
+}
+
+func NewPoppler(binary string, dpi int, timeout time.Duration) *Poppler {
+	return &Poppler{
+		binary:  binary,
+		dpi:     dpi,
+		timeout: timeout,
+	}
+}
+
+func (p *Poppler) Render(ctx context.Context, pdf io.Reader, pages int) ([]image.Image, error) {
+	dir, err := os.MkdirTemp("", "preview-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "input.pdf")
+	file, err := os.Create(input)
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.Copy(file, pdf)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.binary,
+		"-png",
+		"-r", strconv.Itoa(p.dpi),
+		"-f", "1",
+		"-l", strconv.Itoa(pages),
+		input,
+		filepath.Join(dir, "page"),
+	)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("preview: pdftoppm: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	// pdftoppm zero-pads page numbers to the width of the page count, so the
+	// names sort in page order
+	files, err := filepath.Glob(filepath.Join(dir, "page-*.png"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	images := make([]image.Image, 0, len(files))
+	for _, name := range files {
+		img, err := decodePNG(name)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, img)
+	}
+
+	return images, nil
+}
+
+func decodePNG(name string) (image.Image, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return png.Decode(file)
+}
@@ -0,0 +1,13 @@
+package preview
+
+import (
+	"context"
+	"image"
+	"io"
+)
+
+// Renderer rasterizes the first pages of a PDF document.
+type Renderer interface {
+	// Render returns at most pages images, one per page, in page order.
+	Render(ctx context.Context, pdf io.Reader, pages int) ([]image.Image, error)
+}
@@ -0,0 +1,94 @@
+// Package search turns user search input into Postgres full-text queries.
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// TSQuery converts a search string into to_tsquery syntax. Words are ANDed,
+// "quoted words" must appear next to each other, a trailing * matches any word
+// starting with the prefix and a leading - excludes the word. Punctuation is
+// dropped, so the result is always a valid query; it is empty when the input
+// has no words.
+func TSQuery(input string) string {
+	var terms []string
+	for i, segment := range strings.Split(input, `"`) {
+		// odd segments are inside quotes
+		if i%2 == 1 {
+			if phrase := phraseTerm(strings.Fields(segment)); phrase != "" {
+				terms = append(terms, phrase)
+			}
+			continue
+		}
+		for _, field := range strings.Fields(segment) {
+			if term := wordTerm(field); term != "" {
+				terms = append(terms, term)
+			}
+		}
+	}
+
+	return strings.Join(terms, " & ")
+}
+
+func wordTerm(field string) string {
+	negate := strings.HasPrefix(field, "-")
+	prefix := strings.HasSuffix(field, "*")
+
+	words := lexemes(field)
+	if len(words) == 0 {
+		return ""
+	}
+	if prefix {
+		words[len(words)-1] += ":*"
+	}
+
+	term := strings.Join(words, " <-> ")
+	if len(words) > 1 {
+		term = "(" + term + ")"
+	}
+	if negate {
+		term = "!" + term
+	}
+	return term
+}
+
+func phraseTerm(fields []string) string {
+	var words []string
+	for _, field := range fields {
+		words = append(words, lexemes(field)...)
+	}
+	if len(words) == 0 {
+		return ""
+	}
+	if len(words) == 1 {
+		return words[0]
+	}
+	return "(" + strings.Join(words, " <-> ") + ")"
+}
+
+// lexemes splits a field the way the Postgres parser does, on everything that
+// is not a letter or a digit.
+func lexemes(field string) []string {
+	return strings.FieldsFunc(strings.ToLower(field), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Plain returns the words of a search string separated by spaces, for
+// similarity matching. It is empty when the input uses phrases or excludes
+// words, since fuzzy matches cannot honour either.
+func Plain(input string) string {
+	if strings.Contains(input, `"`) {
+		return ""
+	}
+
+	var words []string
+	for _, field := range strings.Fields(input) {
+		if strings.HasPrefix(field, "-") {
+			return ""
+		}
+		words = append(words, lexemes(field)...)
+	}
+	return strings.Join(words, " ")
+}
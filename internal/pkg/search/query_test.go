@@ -0,0 +1,56 @@
+package search
+
+import "testing"
+
+func TestTSQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"empty", "", ""},
+		{"single word", "matematika", "matematika"},
+		{"words are lowercased and ANDed", "Oliy Matematika", "oliy & matematika"},
+		{"phrase", `"oliy matematika" fizika`, "(oliy <-> matematika) & fizika"},
+		{"single word phrase", `"algebra"`, "algebra"},
+		{"unclosed phrase", `"oliy matematika`, "(oliy <-> matematika)"},
+		{"prefix", "mat*", "mat:*"},
+		{"excluded word", "-fizika matematika", "!fizika & matematika"},
+		{"excluded prefix", "-alg*", "!alg:*"},
+		{"punctuation splits a word", "e-mail", "(e <-> mail)"},
+		{"excluded split word", "-e-mail", "!(e <-> mail)"},
+		{"punctuation is dropped", "C++ 2024!", "c & 2024"},
+		{"only operators", `!!! & | ( ) - "" *`, ""},
+		{"cyrillic", "Олий математика", "олий & математика"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TSQuery(tt.input); got != tt.want {
+				t.Errorf("TSQuery(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlain(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"empty", "", ""},
+		{"words", "Oliy  Matematika", "oliy matematika"},
+		{"punctuation and prefixes", "mat* e-mail", "mat e mail"},
+		{"phrase", `"oliy matematika"`, ""},
+		{"excluded word", "matematika -fizika", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Plain(tt.input); got != tt.want {
+				t.Errorf("Plain(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
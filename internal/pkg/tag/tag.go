@@ -0,0 +1,47 @@
+// Package tag normalizes the tags put on posts.
+//
+// A tag is kept as a slug: lowercase letters and digits with the words joined
+// by hyphens, so "Machine  Learning" and "machine-learning" are the same tag.
+// Apostrophes are dropped rather than split on, which keeps oʻzbek one word.
+// Latin and Cyrillic spellings stay apart; admins tie them together with
+// synonyms.
+package tag
+
+import (
+	"strings"
+	"unicode"
+)
+
+// MaxLength caps the length of a slug in runes.
+const MaxLength = 50
+
+// apostrophes lists the characters used for the oʻ/gʻ mark and the tutuq
+// belgisi (ʼ).
+const apostrophes = "'ʻʼ`‘’"
+
+// Slug returns the slug of name, "" when it has no letters or digits.
+func Slug(name string) string {
+	var b strings.Builder
+	b.Grow(len(name))
+	hyphen := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case strings.ContainsRune(apostrophes, r):
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if hyphen && b.Len() != 0 {
+				b.WriteByte('-')
+			}
+			hyphen = false
+			b.WriteRune(r)
+		default:
+			hyphen = true
+		}
+	}
+
+	return b.String()
+}
+
+// Name returns the display name a new tag gets from its slug.
+func Name(slug string) string {
+	return strings.ReplaceAll(slug, "-", " ")
+}
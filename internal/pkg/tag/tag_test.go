@@ -0,0 +1,33 @@
+package tag
+
+import "testing"
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"", ""},
+		{"Machine Learning", "machine-learning"},
+		{"  Machine  Learning  ", "machine-learning"},
+		{"machine-learning", "machine-learning"},
+		{"C++ / Go", "c-go"},
+		{"Oʻzbek tili", "ozbek-tili"},
+		{"O'zbek tili", "ozbek-tili"},
+		{"Oliy matematika 2", "oliy-matematika-2"},
+		{"Олий математика", "олий-математика"},
+		{"#!?", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Slug(tt.name); got != tt.want {
+			t.Errorf("Slug(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestName(t *testing.T) {
+	if got := Name("oliy-matematika-2"); got != "oliy matematika 2" {
+		t.Errorf("Name() = %q, want %q", got, "oliy matematika 2")
+	}
+}
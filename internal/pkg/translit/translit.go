@@ -0,0 +1,43 @@
+// Package translit maps Uzbek Latin, Uzbek Cyrillic and Russian text to one
+// canonical form for matching.
+//
+// The canonical form is lowercase Latin without apostrophes, so oʻ, o‘, o' and
+// ў all become o, and "ye" is folded to "e" because Cyrillic е is written both
+// ways in Latin. It is meant for comparison only, never for display.
+//
+// Normalize must stay in sync with the uz_normalize SQL function, which
+// applies the same mapping to indexed text.
+package translit
+
+import "strings"
+
+var cyrillic = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
+	'ж': "j", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "x", 'ц': "s", 'ч': "ch", 'ш': "sh", 'щ': "sh", 'ы': "i",
+	'э': "e", 'ю': "yu", 'я': "ya", 'ў': "o", 'қ': "q", 'ғ': "g", 'ҳ': "h",
+	'ъ': "", 'ь': "",
+}
+
+// apostrophes lists the characters used for the oʻ/gʻ mark and the tutuq
+// belgisi (ʼ).
+const apostrophes = "'ʻʼ`‘’"
+
+// Normalize returns the canonical form of s.
+func Normalize(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range strings.ToLower(s) {
+		if latin, ok := cyrillic[r]; ok {
+			b.WriteString(latin)
+			continue
+		}
+		if strings.ContainsRune(apostrophes, r) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return strings.ReplaceAll(b.String(), "ye", "e")
+}
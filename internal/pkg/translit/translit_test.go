@@ -0,0 +1,34 @@
+package translit
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", ""},
+		{"Matematika 101", "matematika 101"},
+		{"Oʻzbekiston", "ozbekiston"},
+		{"O‘zbekiston", "ozbekiston"},
+		{"O'zbekiston", "ozbekiston"},
+		{"Ўзбекистон", "ozbekiston"},
+		{"G‘alaba", "galaba"},
+		{"Ғалаба", "galaba"},
+		{"Қарши", "qarshi"},
+		{"Ҳуқуқ", "huquq"},
+		{"maʼruza", "maruza"},
+		{"маъруза", "maruza"},
+		{"Yevropa", "evropa"},
+		{"Европа", "evropa"},
+		{"Ёшлар", "yoshlar"},
+		{"Чорва", "chorva"},
+		{"Тошкент", "toshkent"},
+	}
+
+	for _, tt := range tests {
+		if got := Normalize(tt.input); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// Package visitor identifies guests, who have no user id, so their views can
+// be told apart.
+package visitor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Fingerprint derives a visitor id from a guest's address and user agent.
+// The id changes every ttl, so guests sharing both are told apart again
+// after it.
+func Fingerprint(secret, ip, userAgent string, now time.Time, ttl time.Duration) string {
+	sum := mac(secret, ip+"\n"+userAgent+"\n"+strconv.FormatInt(now.Truncate(ttl).Unix(), 10))
+	id, _ := uuid.FromBytes(sum[:16])
+	return id.String()
+}
+
+// Sign returns a cookie value holding a visitor id that Verify accepts.
+func Sign(secret, id string) string {
+	return id + "." + base64.RawURLEncoding.EncodeToString(mac(secret, id))
+}
+
+// Verify returns the visitor id held by a cookie value made by Sign, and
+// false when the value was not signed with secret.
+func Verify(secret, value string) (string, bool) {
+	id, signature, ok := strings.Cut(value, ".")
+	if !ok {
+		return "", false
+	}
+	sum, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sum, mac(secret, id)) {
+		return "", false
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return "", false
+	}
+	return id, true
+}
+
+func mac(secret, value string) []byte {
+	hash := hmac.New(sha256.New, []byte(secret))
+	hash.Write([]byte(value))
+	return hash.Sum(nil)
+}
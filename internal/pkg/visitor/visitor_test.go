@@ -0,0 +1,70 @@
+package visitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestSignVerify(t *testing.T) {
+	id := uuid.NewString()
+	value := Sign("secret", id)
+
+	tests := []struct {
+		name   string
+		secret string
+		value  string
+		ok     bool
+	}{
+		{"signed", "secret", value, true},
+		{"other secret", "other", value, false},
+		{"no signature", "secret", id, false},
+		{"empty signature", "secret", id + ".", false},
+		{"signature not base64", "secret", id + ".!!!", false},
+		{"tampered id", "secret", uuid.NewString() + value[strings.Index(value, "."):], false},
+		{"signed id that is not a uuid", "secret", Sign("secret", "admin"), false},
+		{"empty", "secret", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Verify(tt.secret, tt.value)
+			if ok != tt.ok {
+				t.Fatalf("Verify() ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && got != id {
+				t.Errorf("Verify() = %q, want %q", got, id)
+			}
+			if !ok && got != "" {
+				t.Errorf("Verify() = %q, want empty", got)
+			}
+		})
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	ttl := 24 * time.Hour
+	id := Fingerprint("secret", "203.0.113.7", "Mozilla/5.0", now, ttl)
+
+	if _, err := uuid.Parse(id); err != nil {
+		t.Fatalf("Fingerprint() = %q, not a uuid: %v", id, err)
+	}
+	if got := Fingerprint("secret", "203.0.113.7", "Mozilla/5.0", now.Add(time.Hour), ttl); got != id {
+		t.Errorf("Fingerprint() changed within ttl: %q, want %q", got, id)
+	}
+
+	others := map[string]string{
+		"next window": Fingerprint("secret", "203.0.113.7", "Mozilla/5.0", now.Add(ttl), ttl),
+		"other ip":    Fingerprint("secret", "203.0.113.8", "Mozilla/5.0", now, ttl),
+		"other agent": Fingerprint("secret", "203.0.113.7", "curl/8.0", now, ttl),
+		"other key":   Fingerprint("other", "203.0.113.7", "Mozilla/5.0", now, ttl),
+	}
+	for name, got := range others {
+		if got == id {
+			t.Errorf("Fingerprint() with %s = %q, want a different id", name, got)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"time"
+	"univer/internal/entity"
+	"univer/internal/infrastructure/repository"
+	"univer/internal/pkg/otlp"
+)
+
+const (
+	serviceNameBookmarkService = "bookmarkServiceUsecase"
+	spanNameBookmarkService    = "bookmarkSpanUsecase"
+)
+
+type Bookmark interface {
+	AddBookmark(ctx context.Context, bookmark *entity.Bookmark) error
+	DeleteBookmark(ctx context.Context, userId, postId string) error
+	ListBookmark(ctx context.Context, req *entity.ListReq) (*entity.PostListRes, error)
+}
+
+type bookmarkService struct {
+	BaseUseCase
+	ctxTimeout time.Duration
+	repo       repository.Bookmark
+	postRepo   repository.Post
+}
+
+func NewBookmarkService(ctxTimeout time.Duration, repo repository.Bookmark, postRepo repository.Post) Bookmark {
+	return bookmarkService{
+		ctxTimeout: ctxTimeout,
+		repo:       repo,
+		postRepo:   postRepo,
+	}
+}
+
+func (r bookmarkService) AddBookmark(ctx context.Context, bookmark *entity.Bookmark) error {
+	ctx, span := otlp.Start(ctx, serviceNameBookmarkService, spanNameBookmarkService+"AddBookmark")
+	defer span.End()
+
+	if _, err := r.postRepo.GetPost(ctx, map[string]string{"id": bookmark.PostId}); err != nil {
+		return err
+	}
+
+	r.beforeRequest(nil, &bookmark.CreatedAt, nil, nil)
+
+	return r.repo.AddBookmark(ctx, bookmark)
+}
+
+func (r bookmarkService) DeleteBookmark(ctx context.Context, userId, postId string) error {
+	ctx, span := otlp.Start(ctx, serviceNameBookmarkService, spanNameBookmarkService+"DeleteBookmark")
+	defer span.End()
+
+	return r.repo.DeleteBookmark(ctx, userId, postId)
+}
+
+// ListBookmark returns a page of the posts the user bookmarked, most recently
+// saved first. The cursors page through the bookmarks.
+func (r bookmarkService) ListBookmark(ctx context.Context, req *entity.ListReq) (*entity.PostListRes, error) {
+	ctx, span := otlp.Start(ctx, serviceNameBookmarkService, spanNameBookmarkService+"ListBookmark")
+	defer span.End()
+
+	bookmarks, err := r.repo.ListBookmark(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	postIds := make([]string, 0, len(bookmarks.Bookmark))
+	for _, bookmark := range bookmarks.Bookmark {
+		postIds = append(postIds, bookmark.PostId)
+	}
+
+	posts, err := r.postRepo.GetPosts(ctx, postIds)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.PostListRes{
+		Post:       orderPosts(postIds, posts),
+		TotalCount: bookmarks.TotalCount,
+		NextCursor: bookmarks.NextCursor,
+		PrevCursor: bookmarks.PrevCursor,
+	}, nil
+}
@@ -70,5 +70,5 @@ func (p categoryService) ListCategory(ctx context.Context, req *entity.ListReq)
 	ctx, span := otlp.Start(ctx, serviceNameCategoryService, spanNameCategoryService + "ListCategory")
 	defer span.End()
 
-	return p.repo.ListCategory(ctx, req.Limit, req.Offset)
+	return p.repo.ListCategory(ctx, req)
 }
@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"time"
+	"univer/internal/entity"
+	"univer/internal/infrastructure/repository"
+	"univer/internal/pkg/otlp"
+)
+
+const (
+	serviceNameCollectionService = "collectionServiceUsecase"
+	spanNameCollectionService    = "collectionSpanUsecase"
+)
+
+type Collection interface {
+	CreateCollection(ctx context.Context, collection *entity.Collection) (*entity.Collection, error)
+	UpdateCollection(ctx context.Context, collection *entity.Collection) (*entity.Collection, error)
+	DeleteCollection(ctx context.Context, req *entity.DeleteReq) error
+	GetCollection(ctx context.Context, id string) (*entity.Collection, error)
+	ListCollection(ctx context.Context, req *entity.ListReq) (*entity.CollectionListRes, error)
+	AddPost(ctx context.Context, collectionId, postId string) error
+	RemovePost(ctx context.Context, collectionId, postId string) error
+	Posts(ctx context.Context, collectionId string) ([]*entity.Post, error)
+	Reorder(ctx context.Context, collectionId string, postIds []string) error
+}
+
+type collectionService struct {
+	BaseUseCase
+	ctxTimeout time.Duration
+	repo       repository.Collection
+	postRepo   repository.Post
+}
+
+func NewCollectionService(ctxTimeout time.Duration, repo repository.Collection, postRepo repository.Post) Collection {
+	return collectionService{
+		ctxTimeout: ctxTimeout,
+		repo:       repo,
+		postRepo:   postRepo,
+	}
+}
+
+func (r collectionService) CreateCollection(ctx context.Context, collection *entity.Collection) (*entity.Collection, error) {
+	ctx, span := otlp.Start(ctx, serviceNameCollectionService, spanNameCollectionService+"CreateCollection")
+	defer span.End()
+
+	r.beforeRequest(&collection.Id, &collection.CreatedAt, &collection.UpdatedAt, nil)
+
+	return r.repo.CreateCollection(ctx, collection)
+}
+
+func (r collectionService) UpdateCollection(ctx context.Context, collection *entity.Collection) (*entity.Collection, error) {
+	ctx, span := otlp.Start(ctx, serviceNameCollectionService, spanNameCollectionService+"UpdateCollection")
+	defer span.End()
+
+	r.beforeRequest(nil, nil, &collection.UpdatedAt, nil)
+
+	return r.repo.UpdateCollection(ctx, collection)
+}
+
+func (r collectionService) DeleteCollection(ctx context.Context, req *entity.DeleteReq) error {
+	ctx, span := otlp.Start(ctx, serviceNameCollectionService, spanNameCollectionService+"DeleteCollection")
+	defer span.End()
+
+	r.beforeRequest(nil, nil, nil, &req.DeletedAt)
+
+	return r.repo.DeleteCollection(ctx, req)
+}
+
+func (r collectionService) GetCollection(ctx context.Context, id string) (*entity.Collection, error) {
+	ctx, span := otlp.Start(ctx, serviceNameCollectionService, spanNameCollectionService+"GetCollection")
+	defer span.End()
+
+	return r.repo.GetCollection(ctx, id)
+}
+
+func (r collectionService) ListCollection(ctx context.Context, req *entity.ListReq) (*entity.CollectionListRes, error) {
+	ctx, span := otlp.Start(ctx, serviceNameCollectionService, spanNameCollectionService+"ListCollection")
+	defer span.End()
+
+	return r.repo.ListCollection(ctx, req)
+}
+
+// AddPost puts a post at the end of a collection. A post that is there
+// already keeps its place; a full collection is refused.
+func (r collectionService) AddPost(ctx context.Context, collectionId, postId string) error {
+	ctx, span := otlp.Start(ctx, serviceNameCollectionService, spanNameCollectionService+"AddPost")
+	defer span.End()
+
+	if _, err := r.postRepo.GetPost(ctx, map[string]string{"id": postId}); err != nil {
+		return err
+	}
+
+	postIds, err := r.repo.ListCollectionPosts(ctx, collectionId)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(postIds, postId) {
+		return nil
+	}
+	if len(postIds) >= entity.MaxCollectionPosts {
+		return entity.ErrorCollectionFull
+	}
+
+	return r.repo.AddCollectionPost(ctx, collectionId, postId)
+}
+
+func (r collectionService) RemovePost(ctx context.Context, collectionId, postId string) error {
+	ctx, span := otlp.Start(ctx, serviceNameCollectionService, spanNameCollectionService+"RemovePost")
+	defer span.End()
+
+	return r.repo.RemoveCollectionPost(ctx, collectionId, postId)
+}
+
+// Posts returns the posts of a collection in their order.
+func (r collectionService) Posts(ctx context.Context, collectionId string) ([]*entity.Post, error) {
+	ctx, span := otlp.Start(ctx, serviceNameCollectionService, spanNameCollectionService+"Posts")
+	defer span.End()
+
+	postIds, err := r.repo.ListCollectionPosts(ctx, collectionId)
+	if err != nil {
+		return nil, err
+	}
+
+	posts, err := r.postRepo.GetPosts(ctx, postIds)
+	if err != nil {
+		return nil, err
+	}
+
+	return orderPosts(postIds, posts), nil
+}
+
+// Reorder moves the posts of a collection into the order of postIds, which
+// must name every post of the collection exactly once.
+func (r collectionService) Reorder(ctx context.Context, collectionId string, postIds []string) error {
+	ctx, span := otlp.Start(ctx, serviceNameCollectionService, spanNameCollectionService+"Reorder")
+	defer span.End()
+
+	current, err := r.repo.ListCollectionPosts(ctx, collectionId)
+	if err != nil {
+		return err
+	}
+
+	sorted := slices.Clone(postIds)
+	slices.Sort(sorted)
+	slices.Sort(current)
+	if !slices.Equal(sorted, current) {
+		return entity.ErrorReorder
+	}
+
+	return r.repo.ReorderCollectionPosts(ctx, collectionId, postIds)
+}
+
+// orderPosts arranges posts in the order of ids, dropping ids without a post.
+func orderPosts(ids []string, posts []*entity.Post) []*entity.Post {
+	byId := make(map[string]*entity.Post, len(posts))
+	for _, post := range posts {
+		byId[post.Id] = post
+	}
+
+	ordered := make([]*entity.Post, 0, len(posts))
+	for _, id := range ids {
+		if post, ok := byId[id]; ok {
+			ordered = append(ordered, post)
+		}
+	}
+
+	return ordered
+}
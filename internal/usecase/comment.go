@@ -76,7 +76,7 @@ func (p *commentService) ListComment(ctx context.Context, req *entity.ListReq) (
 	ctx, span := otlp.Start(ctx, serviceNameCommentService, spanNameCommentService+"ListComment")
 	defer span.End()
 
-	return p.repo.ListComment(ctx, req.Limit, req.Offset, req.Filter)
+	return p.repo.ListComment(ctx, req)
 }
 
 func (p *commentService) CreateLike(ctx context.Context, req *entity.Like) (bool, error) {
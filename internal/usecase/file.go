@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+	"time"
+	"univer/internal/entity"
+	"univer/internal/infrastructure/repository"
+	"univer/internal/pkg/otlp"
+)
+
+const (
+	serviceNameFileService = "fileServiceUsecase"
+	spanNameFileService    = "fileSpanUsecase"
+)
+
+type File interface {
+	GetFile(ctx context.Context, hash string) (*entity.File, error)
+	Acquire(ctx context.Context, file *entity.File) (*entity.File, error)
+	Release(ctx context.Context, hash string) (*entity.File, error)
+}
+
+type fileService struct {
+	BaseUseCase
+	ctxTimeout time.Duration
+	repo       repository.File
+}
+
+func NewFileService(ctxTimeout time.Duration, repo repository.File) File {
+	return fileService{
+		ctxTimeout: ctxTimeout,
+		repo:       repo,
+	}
+}
+
+func (f fileService) GetFile(ctx context.Context, hash string) (*entity.File, error) {
+	ctx, span := otlp.Start(ctx, serviceNameFileService, spanNameFileService+"GetFile")
+	defer span.End()
+
+	return f.repo.GetFile(ctx, hash)
+}
+
+func (f fileService) Acquire(ctx context.Context, file *entity.File) (*entity.File, error) {
+	ctx, span := otlp.Start(ctx, serviceNameFileService, spanNameFileService+"Acquire")
+	defer span.End()
+
+	f.beforeRequest(nil, &file.CreatedAt, &file.UpdatedAt, nil)
+
+	return f.repo.AcquireFile(ctx, file)
+}
+
+// Release returns the file once its last reference is gone, nil otherwise.
+func (f fileService) Release(ctx context.Context, hash string) (*entity.File, error) {
+	ctx, span := otlp.Start(ctx, serviceNameFileService, spanNameFileService+"Release")
+	defer span.End()
+
+	return f.repo.ReleaseFile(ctx, hash)
+}
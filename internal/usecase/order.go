@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+	"univer/internal/entity"
+	"univer/internal/infrastructure/repository"
+	"univer/internal/pkg/otlp"
+)
+
+const (
+	serviceNameOrderService = "orderServiceUsecase"
+	spanNameOrderService    = "orderSpanUsecase"
+)
+
+type Order interface {
+	Purchase(ctx context.Context, order *entity.Order) (*entity.Order, error)
+	ConfirmPayment(ctx context.Context, payment *entity.Order) (*entity.Order, error)
+	GetOrder(ctx context.Context, req *entity.GetReq) (*entity.Order, error)
+	ListOrder(ctx context.Context, req *entity.ListReq) (*entity.OrderListRes, error)
+	CanAccess(ctx context.Context, userId, role string, posts ...*entity.Post) (map[string]bool, error)
+}
+
+type orderService struct {
+	BaseUseCase
+	ctxTimeout time.Duration
+	repo       repository.Order
+	postRepo   repository.Post
+}
+
+func NewOrderService(ctxTimeout time.Duration, repo repository.Order, postRepo repository.Post) Order {
+	return orderService{
+		ctxTimeout: ctxTimeout,
+		repo:       repo,
+		postRepo:   postRepo,
+	}
+}
+
+// Purchase opens a pending order for order.PostId at the post's current
+// price, or returns the one the user already has open. The post is only
+// unlocked once the payment provider confirms the order with ConfirmPayment.
+func (o orderService) Purchase(ctx context.Context, order *entity.Order) (*entity.Order, error) {
+	ctx, span := otlp.Start(ctx, serviceNameOrderService, spanNameOrderService+"Purchase")
+	defer span.End()
+
+	post, err := o.postRepo.GetPost(ctx, map[string]string{"id": order.PostId})
+	if err != nil {
+		return nil, err
+	}
+	if !post.PriceStatus {
+		return nil, entity.ErrorFreePost
+	}
+	if post.UserId == order.UserId {
+		return nil, entity.ErrorOwnPost
+	}
+
+	purchased, err := o.repo.CheckPurchase(ctx, order.UserId, []string{post.Id})
+	if err != nil {
+		return nil, err
+	}
+	if purchased[post.Id] {
+		return nil, entity.ErrorPurchased
+	}
+
+	pending, err := o.repo.GetOrder(ctx, map[string]string{
+		"user_id": order.UserId,
+		"post_id": post.Id,
+		"status":  entity.OrderStatusPending,
+	})
+	if err == nil {
+		return pending, nil
+	}
+	if !errors.Is(err, entity.ErrorNotFound) {
+		return nil, err
+	}
+
+	o.beforeRequest(&order.Id, &order.CreatedAt, &order.UpdatedAt, nil)
+	order.Price = post.Price
+	order.Status = entity.OrderStatusPending
+
+	return o.repo.CreateOrder(ctx, order)
+}
+
+// ConfirmPayment closes a pending order as payment.Status, paid or failed,
+// as reported by the payment provider. A paid order must have been paid its
+// full price. Repeating a confirmation returns the order unchanged.
+func (o orderService) ConfirmPayment(ctx context.Context, payment *entity.Order) (*entity.Order, error) {
+	ctx, span := otlp.Start(ctx, serviceNameOrderService, spanNameOrderService+"ConfirmPayment")
+	defer span.End()
+
+	order, err := o.repo.GetOrder(ctx, map[string]string{"id": payment.Id})
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != entity.OrderStatusPending {
+		if order.Status == payment.Status && order.TransactionId == payment.TransactionId {
+			return order, nil
+		}
+		return nil, entity.ErrorOrderClosed
+	}
+	if payment.Status == entity.OrderStatusPaid && math.Abs(payment.Price-order.Price) > 0.005 {
+		return nil, entity.ErrorPaymentAmount
+	}
+
+	order.Status = payment.Status
+	order.TransactionId = payment.TransactionId
+	o.beforeRequest(nil, nil, &order.UpdatedAt, nil)
+
+	err = o.repo.CloseOrder(ctx, order)
+	switch {
+	case errors.Is(err, entity.ErrorConflict):
+		// the user paid another order for the same post first
+		return nil, entity.ErrorPurchased
+	case errors.Is(err, entity.ErrorNotFound):
+		// a concurrent confirmation closed it
+		return nil, entity.ErrorOrderClosed
+	case err != nil:
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (o orderService) GetOrder(ctx context.Context, req *entity.GetReq) (*entity.Order, error) {
+	ctx, span := otlp.Start(ctx, serviceNameOrderService, spanNameOrderService+"GetOrder")
+	defer span.End()
+
+	return o.repo.GetOrder(ctx, req.Filter)
+}
+
+func (o orderService) ListOrder(ctx context.Context, req *entity.ListReq) (*entity.OrderListRes, error) {
+	ctx, span := otlp.Start(ctx, serviceNameOrderService, spanNameOrderService+"ListOrder")
+	defer span.End()
+
+	return o.repo.ListOrder(ctx, req.Limit, req.Offset, req.Filter)
+}
+
+// CanAccess reports, per post id, whether the user may read the post's file:
+// free posts, the author's own posts, admins and buyers whose payment was
+// confirmed are allowed.
+func (o orderService) CanAccess(ctx context.Context, userId, role string, posts ...*entity.Post) (map[string]bool, error) {
+	ctx, span := otlp.Start(ctx, serviceNameOrderService, spanNameOrderService+"CanAccess")
+	defer span.End()
+
+	access := make(map[string]bool, len(posts))
+	var paid []string
+	for _, post := range posts {
+		if !post.PriceStatus || post.UserId == userId || role == "admin" {
+			access[post.Id] = true
+			continue
+		}
+		paid = append(paid, post.Id)
+	}
+	if len(paid) == 0 || role == "unauthorized" {
+		return access, nil
+	}
+
+	purchased, err := o.repo.CheckPurchase(ctx, userId, paid)
+	if err != nil {
+		return nil, err
+	}
+	for id := range purchased {
+		access[id] = true
+	}
+
+	return access, nil
+}
@@ -2,55 +2,268 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"log"
 	"time"
 	"univer/internal/entity"
 	"univer/internal/infrastructure/repository"
 	"univer/internal/pkg/otlp"
+	"univer/internal/pkg/tag"
+
+	"github.com/google/uuid"
 )
 
 const (
 	postServiceTableName    = "posts"
 	serviceNamePostsService = "postServiceUsecase"
 	spanNamePostsService    = "postSpanUsecase"
+
+	// viewsFlushBatch is how many buffered views are stored per statement.
+	viewsFlushBatch = 1000
 )
 
 type Post interface {
 	CreatePost(ctx context.Context, post *entity.Post) (*entity.Post, error)
+	CheckPost(ctx context.Context, post *entity.Post) error
 	UpdatePost(ctx context.Context, post *entity.PostUpdateReq) (*entity.PostUpdateReq, error)
 	DeletePost(ctx context.Context, req *entity.DeleteReq) error
 	GetPost(ctx context.Context, req *entity.GetReq) (*entity.Post, error)
 	ListPost(ctx context.Context, req *entity.ListReq) (*entity.PostListRes, error)
 	Search(ctx context.Context, req *entity.ListReq) (*entity.PostListRes, error)
+	SearchFacets(ctx context.Context, req *entity.ListReq) (*entity.SearchFacets, error)
+	UpdatePdfPath(ctx context.Context, postId string, version int, pdfPath string) error
+	UpdatePreviews(ctx context.Context, postId string, version int, previews []string) error
+	UpdateContent(ctx context.Context, content *entity.PostContent) error
+	Suggest(ctx context.Context, query string, limit int) ([]*entity.Suggestion, error)
+	FlushViews(ctx context.Context) error
+	CreateDownload(ctx context.Context, download *entity.Download) error
+	DownloadStats(ctx context.Context, postId string, days int) (*entity.DownloadStats, error)
 }
 
 type postService struct {
 	BaseUseCase
-	ctxTimeout time.Duration
-	repo       repository.Post
+	ctxTimeout     time.Duration
+	repo           repository.Post
+	tagRepo        repository.Tag
+	subjectRepo    repository.Subject
+	categoryRepo   repository.Category
+	universityRepo repository.University
+	viewBuffer     repository.ViewBuffer
 }
 
-func NewPostService(ctxTimout time.Duration, repo repository.Post) Post {
+func NewPostService(ctxTimout time.Duration, repo repository.Post, tagRepo repository.Tag, subjectRepo repository.Subject, categoryRepo repository.Category, universityRepo repository.University, viewBuffer repository.ViewBuffer) Post {
 	return postService{
-		ctxTimeout: ctxTimout,
-		repo:       repo,
+		ctxTimeout:     ctxTimout,
+		repo:           repo,
+		tagRepo:        tagRepo,
+		subjectRepo:    subjectRepo,
+		categoryRepo:   categoryRepo,
+		universityRepo: universityRepo,
+		viewBuffer:     viewBuffer,
 	}
 }
 func (p postService) CreatePost(ctx context.Context, Post *entity.Post) (*entity.Post, error) {
 	ctx, span := otlp.Start(ctx, serviceNamePostsService, spanNamePostsService+"CreatePost")
 	defer span.End()
 
+	// tags are checked before the post is stored so a bad tag fails the whole
+	// request
+	tags, err := p.newTags(Post.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.check(ctx, Post); err != nil {
+		return nil, err
+	}
+
+	tagIds, err := p.resolveTags(ctx, tags)
+	if err != nil {
+		return nil, err
+	}
+
 	p.beforeRequest(nil, &Post.CreatedAt, &Post.UpdatedAt, nil)
+	Post.Version = 1
+
+	return p.repo.CreatePost(ctx, Post, tagIds)
+}
+
+// CheckPost checks a new post the way CreatePost does without storing it, so
+// a post that would be refused can be refused before its file is uploaded.
+func (p postService) CheckPost(ctx context.Context, Post *entity.Post) error {
+	ctx, span := otlp.Start(ctx, serviceNamePostsService, spanNamePostsService+"CheckPost")
+	defer span.End()
+
+	if _, err := p.newTags(Post.Tags); err != nil {
+		return err
+	}
 
-	return p.repo.CreatePost(ctx, Post)
+	return p.check(ctx, Post)
+}
+
+// check resolves the subject and the placement of a post and checks that its
+// category exists.
+func (p postService) check(ctx context.Context, Post *entity.Post) error {
+	subject, err := p.subject(ctx, Post.Science)
+	if err != nil {
+		return err
+	}
+	Post.SubjectId, Post.Science = subject.Id, subject.Name
+	if err := p.category(ctx, Post.CategoryId); err != nil {
+		return err
+	}
+
+	return p.place(ctx, &Post.UniversityId, &Post.FacultyId, &Post.CourseId)
 }
 func (p postService) UpdatePost(ctx context.Context, Post *entity.PostUpdateReq) (*entity.PostUpdateReq, error) {
 	ctx, span := otlp.Start(ctx, serviceNamePostsService, spanNamePostsService+"UpdatePost")
 	defer span.End()
 
+	var tags []*entity.Tag
+	if Post.Tags != nil {
+		var err error
+		if tags, err = p.newTags(Post.Tags); err != nil {
+			return nil, err
+		}
+	}
+	subject, err := p.subject(ctx, Post.Science)
+	if err != nil {
+		return nil, err
+	}
+	Post.SubjectId, Post.Science = subject.Id, subject.Name
+	if err := p.category(ctx, Post.CategoryId); err != nil {
+		return nil, err
+	}
+	if err := p.place(ctx, &Post.UniversityId, &Post.FacultyId, &Post.CourseId); err != nil {
+		return nil, err
+	}
+
+	// nil tag ids leave the tags of the post as they are
+	var tagIds []string
+	if Post.Tags != nil {
+		if tagIds, err = p.resolveTags(ctx, tags); err != nil {
+			return nil, err
+		}
+	}
+
 	p.beforeRequest(nil, nil, &Post.UpdatedAt, nil)
 
-	return p.repo.UpdatePost(ctx, Post)
+	return p.repo.UpdatePost(ctx, Post, tagIds)
+}
+
+// subject finds the subject a post's science names, either by its id or by
+// its name in any spelling.
+func (p postService) subject(ctx context.Context, science string) (*entity.Subject, error) {
+	params := map[string]string{"name": science}
+	if _, err := uuid.Parse(science); err == nil {
+		params = map[string]string{"id": science}
+	}
+
+	subject, err := p.subjectRepo.GetSubject(ctx, params)
+	if errors.Is(err, entity.ErrorNotFound) {
+		return nil, entity.ErrorUnknownSubject
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return subject, nil
+}
+
+// category checks that the category of a post exists.
+func (p postService) category(ctx context.Context, categoryId string) error {
+	if _, err := uuid.Parse(categoryId); err != nil {
+		return entity.ErrorUnknownCategory
+	}
+
+	_, err := p.categoryRepo.GetCategory(ctx, map[string]string{"id": categoryId})
+	if errors.Is(err, entity.ErrorNotFound) {
+		return entity.ErrorUnknownCategory
+	}
+	return err
+}
+
+// place fills in the faculty and university of a post from its course, or
+// its university from its faculty, and checks that the ones given agree.
+func (p postService) place(ctx context.Context, universityId, facultyId, courseId *string) error {
+	switch {
+	case *courseId != "":
+		course, err := p.universityRepo.GetCourse(ctx, *courseId)
+		if err != nil {
+			return placementError(err)
+		}
+		if (*facultyId != "" && *facultyId != course.FacultyId) || (*universityId != "" && *universityId != course.UniversityId) {
+			return entity.ErrorPlacement
+		}
+		*facultyId, *universityId = course.FacultyId, course.UniversityId
+	case *facultyId != "":
+		faculty, err := p.universityRepo.GetFaculty(ctx, *facultyId)
+		if err != nil {
+			return placementError(err)
+		}
+		if *universityId != "" && *universityId != faculty.UniversityId {
+			return entity.ErrorPlacement
+		}
+		*universityId = faculty.UniversityId
+	case *universityId != "":
+		if _, err := p.universityRepo.GetUniversity(ctx, *universityId); err != nil {
+			return placementError(err)
+		}
+	}
+
+	return nil
+}
+
+func placementError(err error) error {
+	if errors.Is(err, entity.ErrorNotFound) {
+		return entity.ErrorPlacement
+	}
+	return err
+}
+
+// newTags turns the tag names given for a post into tags, dropping repeats.
+// Tags that do not exist yet are created with the id and times set here.
+func (p postService) newTags(names []string) ([]*entity.Tag, error) {
+	if len(names) > entity.MaxPostTags {
+		return nil, entity.ErrorTooManyTags
+	}
+
+	tags := make([]*entity.Tag, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		slug, err := tagSlug(name)
+		if err != nil {
+			return nil, err
+		}
+		if seen[slug] {
+			continue
+		}
+		seen[slug] = true
+
+		newTag := &entity.Tag{
+			Slug: slug,
+			Name: tag.Name(slug),
+		}
+		p.beforeRequest(&newTag.Id, &newTag.CreatedAt, &newTag.UpdatedAt, nil)
+		tags = append(tags, newTag)
+	}
+
+	return tags, nil
+}
+
+// resolveTags resolves tags, following synonyms and creating the missing ones,
+// and returns their ids. The result is never nil.
+func (p postService) resolveTags(ctx context.Context, tags []*entity.Tag) ([]string, error) {
+	resolved, err := p.tagRepo.ResolveTags(ctx, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	tagIds := make([]string, 0, len(resolved))
+	for _, resolvedTag := range resolved {
+		tagIds = append(tagIds, resolvedTag.Id)
+	}
+
+	return tagIds, nil
 }
 func (p postService) DeletePost(ctx context.Context, req *entity.DeleteReq) error {
 	ctx, span := otlp.Start(ctx, serviceNamePostsService, spanNamePostsService+"DeletePost")
@@ -64,31 +277,88 @@ func (p postService) GetPost(ctx context.Context, req *entity.GetReq) (*entity.P
 	ctx, span := otlp.Start(ctx, serviceNamePostsService, spanNamePostsService+"GetPost")
 	defer span.End()
 
-	check, err := p.repo.CheckUnique(ctx, req.Filter["user_id"], req.Filter["id"])
+	post, err := p.repo.GetPost(ctx, req.Filter)
 	if err != nil {
-		log.Println(err.Error())
 		return nil, err
 	}
-	if !check {
-		_, err := p.repo.CreateViews(ctx, req.Filter["user_id"], req.Filter["id"])
+
+	// a view is only counted when a viewer is given, a signed in student or
+	// a guest's visitor id; downloads and admin lookups read the post without
+	// touching its counter. Views are buffered until FlushViews stores them,
+	// so the buffered ones are added here, and failing to count one does not
+	// fail the read.
+	view := &entity.View{
+		PostId:    post.Id,
+		CreatedAt: time.Now().UTC(),
+	}
+	if userId, ok := req.Filter["user_id"]; ok {
+		view.UserId = userId
+	} else if visitorId, ok := req.Filter["visitor_id"]; ok {
+		view.UserId = visitorId
+		view.Anonymous = true
+	}
+	if _, err := uuid.Parse(view.UserId); err == nil {
+		pending, pendingGuests, err := p.viewBuffer.Record(ctx, view)
 		if err != nil {
 			log.Println(err.Error())
-			return nil, err
 		}
-		_, err = p.repo.UpdateViews(ctx, req.Filter["id"])
-		if err != nil {
-			log.Println(err.Error())
-			return nil, err
+		post.Views += int(pending)
+		post.GuestViews += int(pendingGuests)
+	}
+
+	return post, nil
+}
+
+// FlushViews stores the buffered views in batches and counts them on their
+// posts. Storing is idempotent, so a flush that fails halfway is simply
+// retried with the same views next time.
+func (p postService) FlushViews(ctx context.Context) error {
+	ctx, span := otlp.Start(ctx, serviceNamePostsService, spanNamePostsService+"FlushViews")
+	defer span.End()
+
+	views, err := p.viewBuffer.Take(ctx)
+	if err != nil {
+		return err
+	}
+	if len(views) == 0 {
+		return nil
+	}
+
+	for start := 0; start < len(views); start += viewsFlushBatch {
+		end := min(start+viewsFlushBatch, len(views))
+		if _, err := p.repo.CreateViews(ctx, views[start:end]); err != nil {
+			return err
 		}
 	}
 
-	return p.repo.GetPost(ctx, req.Filter)
+	return p.viewBuffer.Done(ctx)
+}
+
+// CreateDownload counts a download of a post's file.
+func (p postService) CreateDownload(ctx context.Context, download *entity.Download) error {
+	ctx, span := otlp.Start(ctx, serviceNamePostsService, spanNamePostsService+"CreateDownload")
+	defer span.End()
+
+	download.CreatedAt = time.Now().UTC()
+
+	return p.repo.CreateDownload(ctx, download)
+}
+
+// DownloadStats returns a post's downloads, per day for the last days days
+// including today.
+func (p postService) DownloadStats(ctx context.Context, postId string, days int) (*entity.DownloadStats, error) {
+	ctx, span := otlp.Start(ctx, serviceNamePostsService, spanNamePostsService+"DownloadStats")
+	defer span.End()
+
+	since := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1-days)
+
+	return p.repo.DownloadStats(ctx, postId, since)
 }
 func (p postService) ListPost(ctx context.Context, req *entity.ListReq) (*entity.PostListRes, error) {
 	ctx, span := otlp.Start(ctx, serviceNamePostsService, spanNamePostsService+"ListPost")
 	defer span.End()
 
-	return p.repo.ListPost(ctx, req.Limit, req.Offset, req.Filter)
+	return p.repo.ListPost(ctx, req)
 }
 func (p postService) Search(ctx context.Context, req *entity.ListReq) (*entity.PostListRes, error) {
 	ctx, span := otlp.Start(ctx, serviceNamePostsService, spanNamePostsService+"Search")
@@ -96,3 +366,40 @@ func (p postService) Search(ctx context.Context, req *entity.ListReq) (*entity.P
 
 	return p.repo.Search(ctx, req)
 }
+
+func (p postService) SearchFacets(ctx context.Context, req *entity.ListReq) (*entity.SearchFacets, error) {
+	ctx, span := otlp.Start(ctx, serviceNamePostsService, spanNamePostsService+"SearchFacets")
+	defer span.End()
+
+	return p.repo.SearchFacets(ctx, req)
+}
+
+func (p postService) UpdatePdfPath(ctx context.Context, postId string, version int, pdfPath string) error {
+	ctx, span := otlp.Start(ctx, serviceNamePostsService, spanNamePostsService+"UpdatePdfPath")
+	defer span.End()
+
+	return p.repo.UpdatePdfPath(ctx, postId, version, pdfPath)
+}
+
+func (p postService) UpdatePreviews(ctx context.Context, postId string, version int, previews []string) error {
+	ctx, span := otlp.Start(ctx, serviceNamePostsService, spanNamePostsService+"UpdatePreviews")
+	defer span.End()
+
+	return p.repo.UpdatePreviews(ctx, postId, version, previews)
+}
+
+func (p postService) UpdateContent(ctx context.Context, content *entity.PostContent) error {
+	ctx, span := otlp.Start(ctx, serviceNamePostsService, spanNamePostsService+"UpdateContent")
+	defer span.End()
+
+	p.beforeRequest(nil, &content.CreatedAt, &content.UpdatedAt, nil)
+
+	return p.repo.UpdateContent(ctx, content)
+}
+
+func (p postService) Suggest(ctx context.Context, query string, limit int) ([]*entity.Suggestion, error) {
+	ctx, span := otlp.Start(ctx, serviceNamePostsService, spanNamePostsService+"Suggest")
+	defer span.End()
+
+	return p.repo.Suggest(ctx, query, limit)
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"time"
+	"univer/internal/entity"
+	"univer/internal/infrastructure/repository"
+	"univer/internal/pkg/otlp"
+)
+
+const (
+	serviceNamePostVersionService = "postVersionServiceUsecase"
+	spanNamePostVersionService    = "postVersionSpanUsecase"
+)
+
+type PostVersion interface {
+	Revise(ctx context.Context, post *entity.Post) (*entity.Post, error)
+	Rollback(ctx context.Context, postId string, version int) (*entity.Post, error)
+	GetVersion(ctx context.Context, postId string, version int) (*entity.PostVersion, error)
+	ListVersions(ctx context.Context, postId string) ([]*entity.PostVersion, error)
+}
+
+type postVersionService struct {
+	BaseUseCase
+	ctxTimeout time.Duration
+	repo       repository.PostVersion
+	postRepo   repository.Post
+}
+
+func NewPostVersionService(ctxTimeout time.Duration, repo repository.PostVersion, postRepo repository.Post) PostVersion {
+	return postVersionService{
+		ctxTimeout: ctxTimeout,
+		repo:       repo,
+		postRepo:   postRepo,
+	}
+}
+
+// Revise archives the current file of the post and makes the file given in
+// post (Path, FileHash, PdfPath, Previews) its next version.
+func (p postVersionService) Revise(ctx context.Context, post *entity.Post) (*entity.Post, error) {
+	ctx, span := otlp.Start(ctx, serviceNamePostVersionService, spanNamePostVersionService+"Revise")
+	defer span.End()
+
+	current, err := p.postRepo.GetPost(ctx, map[string]string{"id": post.Id})
+	if err != nil {
+		return nil, err
+	}
+
+	archived := &entity.PostVersion{
+		PostId:   current.Id,
+		Version:  current.Version,
+		Path:     current.Path,
+		FileHash: current.FileHash,
+		PdfPath:  current.PdfPath,
+		Previews: current.Previews,
+	}
+	p.beforeRequest(&archived.Id, &archived.CreatedAt, nil, nil)
+
+	current.Path = post.Path
+	current.FileHash = post.FileHash
+	current.PdfPath = post.PdfPath
+	current.Previews = post.Previews
+	current.Version++
+	p.beforeRequest(nil, nil, &current.UpdatedAt, nil)
+
+	// a concurrent revision archives the same version number and fails here
+	// with a conflict
+	if err := p.repo.ReviseFile(ctx, archived, current); err != nil {
+		return nil, err
+	}
+
+	return current, nil
+}
+
+// Rollback makes a previous version current again. The rolled back file
+// becomes a new version, so the history is kept.
+func (p postVersionService) Rollback(ctx context.Context, postId string, version int) (*entity.Post, error) {
+	ctx, span := otlp.Start(ctx, serviceNamePostVersionService, spanNamePostVersionService+"Rollback")
+	defer span.End()
+
+	previous, err := p.repo.GetPostVersion(ctx, postId, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.Revise(ctx, &entity.Post{
+		Id:       postId,
+		Path:     previous.Path,
+		FileHash: previous.FileHash,
+		PdfPath:  previous.PdfPath,
+		Previews: previous.Previews,
+	})
+}
+
+func (p postVersionService) GetVersion(ctx context.Context, postId string, version int) (*entity.PostVersion, error) {
+	ctx, span := otlp.Start(ctx, serviceNamePostVersionService, spanNamePostVersionService+"GetVersion")
+	defer span.End()
+
+	return p.repo.GetPostVersion(ctx, postId, version)
+}
+
+func (p postVersionService) ListVersions(ctx context.Context, postId string) ([]*entity.PostVersion, error) {
+	ctx, span := otlp.Start(ctx, serviceNamePostVersionService, spanNamePostVersionService+"ListVersions")
+	defer span.End()
+
+	return p.repo.ListPostVersion(ctx, postId)
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"time"
+	"univer/internal/entity"
+	"univer/internal/infrastructure/repository"
+	"univer/internal/pkg/otlp"
+)
+
+const (
+	serviceNameRatingService = "ratingServiceUsecase"
+	spanNameRatingService    = "ratingSpanUsecase"
+)
+
+type Rating interface {
+	Rate(ctx context.Context, rating *entity.Rating) (*entity.Rating, error)
+	GetRating(ctx context.Context, postId, userId string) (*entity.Rating, error)
+	DeleteRating(ctx context.Context, postId, userId string) error
+	ListRating(ctx context.Context, req *entity.ListReq) (*entity.RatingListRes, error)
+}
+
+type ratingService struct {
+	BaseUseCase
+	ctxTimeout time.Duration
+	repo       repository.Rating
+	postRepo   repository.Post
+}
+
+func NewRatingService(ctxTimeout time.Duration, repo repository.Rating, postRepo repository.Post) Rating {
+	return ratingService{
+		ctxTimeout: ctxTimeout,
+		repo:       repo,
+		postRepo:   postRepo,
+	}
+}
+
+// Rate saves the user's rating of a post, replacing an earlier one. Authors
+// cannot rate their own posts.
+func (r ratingService) Rate(ctx context.Context, rating *entity.Rating) (*entity.Rating, error) {
+	ctx, span := otlp.Start(ctx, serviceNameRatingService, spanNameRatingService+"Rate")
+	defer span.End()
+
+	post, err := r.postRepo.GetPost(ctx, map[string]string{"id": rating.PostId})
+	if err != nil {
+		return nil, err
+	}
+	if post.UserId == rating.UserId {
+		return nil, entity.ErrorOwnRating
+	}
+
+	r.beforeRequest(nil, &rating.CreatedAt, &rating.UpdatedAt, nil)
+
+	return r.repo.SaveRating(ctx, rating)
+}
+
+func (r ratingService) GetRating(ctx context.Context, postId, userId string) (*entity.Rating, error) {
+	ctx, span := otlp.Start(ctx, serviceNameRatingService, spanNameRatingService+"GetRating")
+	defer span.End()
+
+	return r.repo.GetRating(ctx, postId, userId)
+}
+
+func (r ratingService) DeleteRating(ctx context.Context, postId, userId string) error {
+	ctx, span := otlp.Start(ctx, serviceNameRatingService, spanNameRatingService+"DeleteRating")
+	defer span.End()
+
+	return r.repo.DeleteRating(ctx, postId, userId)
+}
+
+func (r ratingService) ListRating(ctx context.Context, req *entity.ListReq) (*entity.RatingListRes, error) {
+	ctx, span := otlp.Start(ctx, serviceNameRatingService, spanNameRatingService+"ListRating")
+	defer span.End()
+
+	return r.repo.ListRating(ctx, req)
+}
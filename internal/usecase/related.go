@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"context"
+	"time"
+	"univer/internal/entity"
+	"univer/internal/infrastructure/repository"
+	"univer/internal/pkg/otlp"
+)
+
+const (
+	serviceNameRelatedService = "relatedServiceUsecase"
+	spanNameRelatedService    = "relatedSpanUsecase"
+)
+
+type Related interface {
+	Compute(ctx context.Context, lookback time.Duration, limit int) error
+	Related(ctx context.Context, postId string, limit int) ([]*entity.RelatedPost, error)
+}
+
+type relatedService struct {
+	BaseUseCase
+	ctxTimeout time.Duration
+	repo       repository.Related
+	postRepo   repository.Post
+}
+
+func NewRelatedService(ctxTimeout time.Duration, repo repository.Related, postRepo repository.Post) Related {
+	return relatedService{
+		ctxTimeout: ctxTimeout,
+		repo:       repo,
+		postRepo:   postRepo,
+	}
+}
+
+// Compute recomputes the related posts of every post from the views and
+// downloads of the last lookback, keeping limit posts of each.
+func (p relatedService) Compute(ctx context.Context, lookback time.Duration, limit int) error {
+	ctx, span := otlp.Start(ctx, serviceNameRelatedService, spanNameRelatedService+"Compute")
+	defer span.End()
+
+	now := time.Now().UTC()
+	return p.repo.ComputeRelated(ctx, now.Add(-lookback), now, limit)
+}
+
+// Related returns up to limit posts viewed by the students who viewed a post,
+// most similar first. When there are fewer than that the rest are filled in
+// from the same subject and category.
+func (p relatedService) Related(ctx context.Context, postId string, limit int) ([]*entity.RelatedPost, error) {
+	ctx, span := otlp.Start(ctx, serviceNameRelatedService, spanNameRelatedService+"Related")
+	defer span.End()
+
+	if _, err := p.postRepo.GetPost(ctx, map[string]string{"id": postId}); err != nil {
+		return nil, err
+	}
+
+	related, err := p.repo.ListRelated(ctx, postId, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, limit)
+	for _, post := range related {
+		ids = append(ids, post.Post.Id)
+	}
+	if len(related) < limit {
+		similar, err := p.repo.ListSimilar(ctx, postId, ids, limit-len(related))
+		if err != nil {
+			return nil, err
+		}
+		for _, post := range similar {
+			ids = append(ids, post.Post.Id)
+		}
+		related = append(related, similar...)
+	}
+
+	posts, err := p.postRepo.GetPosts(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byId := make(map[string]*entity.Post, len(posts))
+	for _, post := range posts {
+		byId[post.Id] = post
+	}
+
+	// posts deleted since the related ones were computed are left out
+	response := make([]*entity.RelatedPost, 0, len(related))
+	for _, post := range related {
+		if full, ok := byId[post.Post.Id]; ok {
+			post.Post = full
+			response = append(response, post)
+		}
+	}
+
+	return response, nil
+}
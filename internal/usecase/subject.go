@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"time"
+	"univer/internal/entity"
+	"univer/internal/infrastructure/repository"
+	"univer/internal/pkg/otlp"
+)
+
+const (
+	serviceNameSubjectService = "subjectServiceUsecase"
+	spanNameSubjectService    = "subjectSpanUsecase"
+)
+
+type Subject interface {
+	CreateSubject(ctx context.Context, subject *entity.Subject) (*entity.Subject, error)
+	UpdateSubject(ctx context.Context, subject *entity.Subject) (*entity.Subject, error)
+	DeleteSubject(ctx context.Context, req *entity.DeleteReq) error
+	GetSubject(ctx context.Context, req *entity.GetReq) (*entity.Subject, error)
+	ListSubject(ctx context.Context, req *entity.ListReq) (*entity.ListSubjectRes, error)
+	SubjectMapping(ctx context.Context) ([]*entity.SubjectMapping, error)
+}
+
+type subjectService struct {
+	BaseUseCase
+	ctxTimeout time.Duration
+	repo       repository.Subject
+}
+
+func NewSubjectService(ctxTimeout time.Duration, repo repository.Subject) Subject {
+	return subjectService{
+		ctxTimeout: ctxTimeout,
+		repo:       repo,
+	}
+}
+
+func (p subjectService) CreateSubject(ctx context.Context, subject *entity.Subject) (*entity.Subject, error) {
+	ctx, span := otlp.Start(ctx, serviceNameSubjectService, spanNameSubjectService+"CreateSubject")
+	defer span.End()
+
+	p.beforeRequest(&subject.Id, &subject.CreatedAt, &subject.UpdatedAt, nil)
+
+	return p.repo.CreateSubject(ctx, subject)
+}
+
+func (p subjectService) UpdateSubject(ctx context.Context, subject *entity.Subject) (*entity.Subject, error) {
+	ctx, span := otlp.Start(ctx, serviceNameSubjectService, spanNameSubjectService+"UpdateSubject")
+	defer span.End()
+
+	p.beforeRequest(nil, nil, &subject.UpdatedAt, nil)
+
+	return p.repo.UpdateSubject(ctx, subject)
+}
+
+func (p subjectService) DeleteSubject(ctx context.Context, req *entity.DeleteReq) error {
+	ctx, span := otlp.Start(ctx, serviceNameSubjectService, spanNameSubjectService+"DeleteSubject")
+	defer span.End()
+
+	p.beforeRequest(nil, nil, nil, &req.DeletedAt)
+
+	return p.repo.DeleteSubject(ctx, req)
+}
+
+func (p subjectService) GetSubject(ctx context.Context, req *entity.GetReq) (*entity.Subject, error) {
+	ctx, span := otlp.Start(ctx, serviceNameSubjectService, spanNameSubjectService+"GetSubject")
+	defer span.End()
+
+	return p.repo.GetSubject(ctx, req.Filter)
+}
+
+func (p subjectService) ListSubject(ctx context.Context, req *entity.ListReq) (*entity.ListSubjectRes, error) {
+	ctx, span := otlp.Start(ctx, serviceNameSubjectService, spanNameSubjectService+"ListSubject")
+	defer span.End()
+
+	return p.repo.ListSubject(ctx, req)
+}
+
+func (p subjectService) SubjectMapping(ctx context.Context) ([]*entity.SubjectMapping, error) {
+	ctx, span := otlp.Start(ctx, serviceNameSubjectService, spanNameSubjectService+"SubjectMapping")
+	defer span.End()
+
+	return p.repo.SubjectMapping(ctx)
+}
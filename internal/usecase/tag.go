@@ -0,0 +1,199 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"univer/internal/entity"
+	"univer/internal/infrastructure/repository"
+	"univer/internal/pkg/otlp"
+	"univer/internal/pkg/tag"
+)
+
+const (
+	serviceNameTagService = "tagServiceUsecase"
+	spanNameTagService    = "tagSpanUsecase"
+
+	// maxTagNameLength caps the display name of a tag in runes, the size of
+	// its column.
+	maxTagNameLength = 100
+)
+
+type Tag interface {
+	GetTag(ctx context.Context, slug string) (*entity.Tag, error)
+	ListTag(ctx context.Context, req *entity.ListReq) (*entity.TagListRes, error)
+	SuggestTag(ctx context.Context, prefix string, limit int) ([]*entity.Tag, error)
+	RenameTag(ctx context.Context, slug, name string) (*entity.Tag, error)
+	MergeTag(ctx context.Context, slug, into string) (*entity.Tag, error)
+	AddSynonym(ctx context.Context, slug, synonym string) (*entity.Tag, error)
+	DeleteSynonym(ctx context.Context, slug, synonym string) error
+}
+
+type tagService struct {
+	BaseUseCase
+	ctxTimeout time.Duration
+	repo       repository.Tag
+}
+
+func NewTagService(ctxTimeout time.Duration, repo repository.Tag) Tag {
+	return tagService{
+		ctxTimeout: ctxTimeout,
+		repo:       repo,
+	}
+}
+
+func (r tagService) GetTag(ctx context.Context, slug string) (*entity.Tag, error) {
+	ctx, span := otlp.Start(ctx, serviceNameTagService, spanNameTagService+"GetTag")
+	defer span.End()
+
+	return r.repo.GetTag(ctx, tag.Slug(slug))
+}
+
+func (r tagService) ListTag(ctx context.Context, req *entity.ListReq) (*entity.TagListRes, error) {
+	ctx, span := otlp.Start(ctx, serviceNameTagService, spanNameTagService+"ListTag")
+	defer span.End()
+
+	return r.repo.ListTag(ctx, req)
+}
+
+func (r tagService) SuggestTag(ctx context.Context, prefix string, limit int) ([]*entity.Tag, error) {
+	ctx, span := otlp.Start(ctx, serviceNameTagService, spanNameTagService+"SuggestTag")
+	defer span.End()
+
+	prefix = tag.Slug(prefix)
+	if prefix == "" {
+		return nil, nil
+	}
+
+	return r.repo.SuggestTag(ctx, prefix, limit)
+}
+
+// RenameTag gives the tag with slug a new name and the slug made from it. The
+// new slug must not belong to another tag; merge the two instead.
+func (r tagService) RenameTag(ctx context.Context, slug, name string) (*entity.Tag, error) {
+	ctx, span := otlp.Start(ctx, serviceNameTagService, spanNameTagService+"RenameTag")
+	defer span.End()
+
+	newSlug, err := tagSlug(name)
+	if err != nil {
+		return nil, err
+	}
+	// the name is shown as the admin wrote it, only its spacing is tidied
+	name = strings.Join(strings.Fields(name), " ")
+	if utf8.RuneCountInString(name) > maxTagNameLength {
+		return nil, entity.ErrorInvalidTag
+	}
+
+	current, err := r.repo.GetTag(ctx, tag.Slug(slug))
+	if err != nil {
+		return nil, err
+	}
+	existing, err := r.repo.GetTag(ctx, newSlug)
+	if err == nil && existing.Id != current.Id {
+		return nil, entity.ErrorTagExists
+	}
+	if err != nil && !errors.Is(err, entity.ErrorNotFound) {
+		return nil, err
+	}
+
+	oldSlug := current.Slug
+	current.Slug = newSlug
+	current.Name = name
+	r.beforeRequest(nil, nil, &current.UpdatedAt, nil)
+	if err := r.repo.RenameTag(ctx, current, oldSlug); err != nil {
+		return nil, err
+	}
+
+	return r.repo.GetTag(ctx, newSlug)
+}
+
+// MergeTag folds the tag with slug into the tag into. Its posts and synonyms
+// move over and its slug becomes a synonym of the remaining tag.
+func (r tagService) MergeTag(ctx context.Context, slug, into string) (*entity.Tag, error) {
+	ctx, span := otlp.Start(ctx, serviceNameTagService, spanNameTagService+"MergeTag")
+	defer span.End()
+
+	source, err := r.repo.GetTag(ctx, tag.Slug(slug))
+	if err != nil {
+		return nil, err
+	}
+	target, err := r.repo.GetTag(ctx, tag.Slug(into))
+	if err != nil {
+		return nil, err
+	}
+	if source.Id == target.Id {
+		return nil, entity.ErrorMergeItself
+	}
+
+	if err := r.repo.MergeTag(ctx, source.Id, target.Id); err != nil {
+		return nil, err
+	}
+
+	return r.repo.GetTag(ctx, target.Slug)
+}
+
+// AddSynonym makes synonym lead to the tag with slug. A synonym cannot be the
+// slug or a synonym of any tag already.
+func (r tagService) AddSynonym(ctx context.Context, slug, synonym string) (*entity.Tag, error) {
+	ctx, span := otlp.Start(ctx, serviceNameTagService, spanNameTagService+"AddSynonym")
+	defer span.End()
+
+	synonymSlug, err := tagSlug(synonym)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := r.repo.GetTag(ctx, tag.Slug(slug))
+	if err != nil {
+		return nil, err
+	}
+	_, err = r.repo.GetTag(ctx, synonymSlug)
+	if err == nil {
+		return nil, entity.ErrorTagExists
+	}
+	if !errors.Is(err, entity.ErrorNotFound) {
+		return nil, err
+	}
+
+	if err := r.repo.AddSynonym(ctx, current.Id, synonymSlug); err != nil {
+		return nil, err
+	}
+
+	return r.repo.GetTag(ctx, current.Slug)
+}
+
+func (r tagService) DeleteSynonym(ctx context.Context, slug, synonym string) error {
+	ctx, span := otlp.Start(ctx, serviceNameTagService, spanNameTagService+"DeleteSynonym")
+	defer span.End()
+
+	current, err := r.repo.GetTag(ctx, tag.Slug(slug))
+	if err != nil {
+		return err
+	}
+	synonymSlug := tag.Slug(synonym)
+	if current.Slug == synonymSlug {
+		return entity.ErrorNotFound
+	}
+	owner, err := r.repo.GetTag(ctx, synonymSlug)
+	if err != nil {
+		return err
+	}
+	if owner.Id != current.Id {
+		return entity.ErrorNotFound
+	}
+
+	return r.repo.DeleteSynonym(ctx, synonymSlug)
+}
+
+// tagSlug returns the slug of name or ErrorInvalidTag when there is none or
+// it is too long.
+func tagSlug(name string) (string, error) {
+	slug := tag.Slug(name)
+	if slug == "" || utf8.RuneCountInString(slug) > tag.MaxLength {
+		return "", entity.ErrorInvalidTag
+	}
+
+	return slug, nil
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"time"
+	"univer/internal/entity"
+	"univer/internal/infrastructure/repository"
+	"univer/internal/pkg/otlp"
+)
+
+const (
+	serviceNameTrendingService = "trendingServiceUsecase"
+	spanNameTrendingService    = "trendingSpanUsecase"
+)
+
+type Trending interface {
+	Compute(ctx context.Context, limit int) error
+	Trending(ctx context.Context, req *entity.TrendingReq) (*entity.Trending, error)
+}
+
+type trendingService struct {
+	BaseUseCase
+	ctxTimeout time.Duration
+	repo       repository.Trending
+	postRepo   repository.Post
+}
+
+func NewTrendingService(ctxTimeout time.Duration, repo repository.Trending, postRepo repository.Post) Trending {
+	return trendingService{
+		ctxTimeout: ctxTimeout,
+		repo:       repo,
+		postRepo:   postRepo,
+	}
+}
+
+// Compute recomputes the trending posts of every window, keeping limit posts
+// of each list.
+func (p trendingService) Compute(ctx context.Context, limit int) error {
+	ctx, span := otlp.Start(ctx, serviceNameTrendingService, spanNameTrendingService+"Compute")
+	defer span.End()
+
+	return p.repo.ComputeTrending(ctx, entity.TrendingWindows, time.Now().UTC(), limit)
+}
+
+// Trending returns the last computed trending posts of a window. The
+// categories and subjects only come with the unscoped list.
+func (p trendingService) Trending(ctx context.Context, req *entity.TrendingReq) (*entity.Trending, error) {
+	ctx, span := otlp.Start(ctx, serviceNameTrendingService, spanNameTrendingService+"Trending")
+	defer span.End()
+
+	if trendingWindow(req.Window) == nil {
+		return nil, entity.ErrorInvalidWindow
+	}
+
+	scored, computedAt, err := p.repo.ListTrending(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(scored))
+	for _, post := range scored {
+		ids = append(ids, post.Post.Id)
+	}
+	posts, err := p.postRepo.GetPosts(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byId := make(map[string]*entity.Post, len(posts))
+	for _, post := range posts {
+		byId[post.Id] = post
+	}
+
+	trending := &entity.Trending{
+		Window:     req.Window,
+		Posts:      make([]*entity.TrendingPost, 0, len(scored)),
+		ComputedAt: computedAt,
+	}
+	// posts deleted since the scores were computed are left out
+	for _, post := range scored {
+		if full, ok := byId[post.Post.Id]; ok {
+			trending.Posts = append(trending.Posts, &entity.TrendingPost{
+				Post:  full,
+				Score: post.Score,
+			})
+		}
+	}
+
+	if req.Scope != entity.TrendingAll {
+		return trending, nil
+	}
+
+	topics, err := p.repo.ListTrendingTopics(ctx, req.Window, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, topic := range topics {
+		switch topic.Kind {
+		case entity.TrendingCategory:
+			trending.Categories = append(trending.Categories, topic)
+		case entity.TrendingScience:
+			trending.Sciences = append(trending.Sciences, topic)
+		}
+	}
+
+	return trending, nil
+}
+
+func trendingWindow(name string) *entity.TrendingWindow {
+	for _, window := range entity.TrendingWindows {
+		if window.Name == name {
+			return &window
+		}
+	}
+	return nil
+}
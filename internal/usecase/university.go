@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"time"
+	"univer/internal/entity"
+	"univer/internal/infrastructure/repository"
+	"univer/internal/pkg/otlp"
+)
+
+const (
+	serviceNameUniversityService = "universityServiceUsecase"
+	spanNameUniversityService    = "universitySpanUsecase"
+)
+
+// University keeps the universities, their faculties and the courses of the
+// faculties.
+type University interface {
+	CreateUniversity(ctx context.Context, university *entity.University) (*entity.University, error)
+	UpdateUniversity(ctx context.Context, university *entity.University) (*entity.University, error)
+	DeleteUniversity(ctx context.Context, req *entity.DeleteReq) error
+	GetUniversity(ctx context.Context, id string) (*entity.University, error)
+	ListUniversity(ctx context.Context, req *entity.ListReq) (*entity.UniversityListRes, error)
+
+	CreateFaculty(ctx context.Context, faculty *entity.Faculty) (*entity.Faculty, error)
+	UpdateFaculty(ctx context.Context, faculty *entity.Faculty) (*entity.Faculty, error)
+	DeleteFaculty(ctx context.Context, req *entity.DeleteReq) error
+	GetFaculty(ctx context.Context, id string) (*entity.Faculty, error)
+	ListFaculty(ctx context.Context, universityId string, req *entity.ListReq) (*entity.FacultyListRes, error)
+
+	CreateCourse(ctx context.Context, course *entity.Course) (*entity.Course, error)
+	UpdateCourse(ctx context.Context, course *entity.Course) (*entity.Course, error)
+	DeleteCourse(ctx context.Context, req *entity.DeleteReq) error
+	GetCourse(ctx context.Context, id string) (*entity.Course, error)
+	ListCourse(ctx context.Context, facultyId string, req *entity.ListReq) (*entity.CourseListRes, error)
+}
+
+type universityService struct {
+	BaseUseCase
+	ctxTimeout time.Duration
+	repo       repository.University
+}
+
+func NewUniversityService(ctxTimeout time.Duration, repo repository.University) University {
+	return universityService{
+		ctxTimeout: ctxTimeout,
+		repo:       repo,
+	}
+}
+
+func (p universityService) CreateUniversity(ctx context.Context, university *entity.University) (*entity.University, error) {
+	ctx, span := otlp.Start(ctx, serviceNameUniversityService, spanNameUniversityService+"CreateUniversity")
+	defer span.End()
+
+	p.beforeRequest(&university.Id, &university.CreatedAt, &university.UpdatedAt, nil)
+
+	return p.repo.CreateUniversity(ctx, university)
+}
+
+func (p universityService) UpdateUniversity(ctx context.Context, university *entity.University) (*entity.University, error) {
+	ctx, span := otlp.Start(ctx, serviceNameUniversityService, spanNameUniversityService+"UpdateUniversity")
+	defer span.End()
+
+	p.beforeRequest(nil, nil, &university.UpdatedAt, nil)
+
+	return p.repo.UpdateUniversity(ctx, university)
+}
+
+func (p universityService) DeleteUniversity(ctx context.Context, req *entity.DeleteReq) error {
+	ctx, span := otlp.Start(ctx, serviceNameUniversityService, spanNameUniversityService+"DeleteUniversity")
+	defer span.End()
+
+	p.beforeRequest(nil, nil, nil, &req.DeletedAt)
+
+	return p.repo.DeleteUniversity(ctx, req)
+}
+
+func (p universityService) GetUniversity(ctx context.Context, id string) (*entity.University, error) {
+	ctx, span := otlp.Start(ctx, serviceNameUniversityService, spanNameUniversityService+"GetUniversity")
+	defer span.End()
+
+	return p.repo.GetUniversity(ctx, id)
+}
+
+func (p universityService) ListUniversity(ctx context.Context, req *entity.ListReq) (*entity.UniversityListRes, error) {
+	ctx, span := otlp.Start(ctx, serviceNameUniversityService, spanNameUniversityService+"ListUniversity")
+	defer span.End()
+
+	return p.repo.ListUniversity(ctx, req)
+}
+
+func (p universityService) CreateFaculty(ctx context.Context, faculty *entity.Faculty) (*entity.Faculty, error) {
+	ctx, span := otlp.Start(ctx, serviceNameUniversityService, spanNameUniversityService+"CreateFaculty")
+	defer span.End()
+
+	p.beforeRequest(&faculty.Id, &faculty.CreatedAt, &faculty.UpdatedAt, nil)
+
+	return p.repo.CreateFaculty(ctx, faculty)
+}
+
+func (p universityService) UpdateFaculty(ctx context.Context, faculty *entity.Faculty) (*entity.Faculty, error) {
+	ctx, span := otlp.Start(ctx, serviceNameUniversityService, spanNameUniversityService+"UpdateFaculty")
+	defer span.End()
+
+	p.beforeRequest(nil, nil, &faculty.UpdatedAt, nil)
+
+	return p.repo.UpdateFaculty(ctx, faculty)
+}
+
+func (p universityService) DeleteFaculty(ctx context.Context, req *entity.DeleteReq) error {
+	ctx, span := otlp.Start(ctx, serviceNameUniversityService, spanNameUniversityService+"DeleteFaculty")
+	defer span.End()
+
+	p.beforeRequest(nil, nil, nil, &req.DeletedAt)
+
+	return p.repo.DeleteFaculty(ctx, req)
+}
+
+func (p universityService) GetFaculty(ctx context.Context, id string) (*entity.Faculty, error) {
+	ctx, span := otlp.Start(ctx, serviceNameUniversityService, spanNameUniversityService+"GetFaculty")
+	defer span.End()
+
+	return p.repo.GetFaculty(ctx, id)
+}
+
+func (p universityService) ListFaculty(ctx context.Context, universityId string, req *entity.ListReq) (*entity.FacultyListRes, error) {
+	ctx, span := otlp.Start(ctx, serviceNameUniversityService, spanNameUniversityService+"ListFaculty")
+	defer span.End()
+
+	return p.repo.ListFaculty(ctx, universityId, req)
+}
+
+func (p universityService) CreateCourse(ctx context.Context, course *entity.Course) (*entity.Course, error) {
+	ctx, span := otlp.Start(ctx, serviceNameUniversityService, spanNameUniversityService+"CreateCourse")
+	defer span.End()
+
+	p.beforeRequest(&course.Id, &course.CreatedAt, &course.UpdatedAt, nil)
+
+	return p.repo.CreateCourse(ctx, course)
+}
+
+func (p universityService) UpdateCourse(ctx context.Context, course *entity.Course) (*entity.Course, error) {
+	ctx, span := otlp.Start(ctx, serviceNameUniversityService, spanNameUniversityService+"UpdateCourse")
+	defer span.End()
+
+	p.beforeRequest(nil, nil, &course.UpdatedAt, nil)
+
+	return p.repo.UpdateCourse(ctx, course)
+}
+
+func (p universityService) DeleteCourse(ctx context.Context, req *entity.DeleteReq) error {
+	ctx, span := otlp.Start(ctx, serviceNameUniversityService, spanNameUniversityService+"DeleteCourse")
+	defer span.End()
+
+	p.beforeRequest(nil, nil, nil, &req.DeletedAt)
+
+	return p.repo.DeleteCourse(ctx, req)
+}
+
+func (p universityService) GetCourse(ctx context.Context, id string) (*entity.Course, error) {
+	ctx, span := otlp.Start(ctx, serviceNameUniversityService, spanNameUniversityService+"GetCourse")
+	defer span.End()
+
+	return p.repo.GetCourse(ctx, id)
+}
+
+func (p universityService) ListCourse(ctx context.Context, facultyId string, req *entity.ListReq) (*entity.CourseListRes, error) {
+	ctx, span := otlp.Start(ctx, serviceNameUniversityService, spanNameUniversityService+"ListCourse")
+	defer span.End()
+
+	return p.repo.ListCourse(ctx, facultyId, req)
+}
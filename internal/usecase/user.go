@@ -25,6 +25,7 @@ type User interface {
 	UpdateRefresh(ctx context.Context, request *entity.UpdateRefresh) (*entity.Response, error)
 	UpdatePassword(ctx context.Context, request *entity.UpdatePassword) (*entity.Response, error)
 	UpdateProfile(ctx context.Context, request *entity.UpdateProfile) (*entity.Response, error)
+	UpdateUniversity(ctx context.Context, request *entity.UpdateUniversity) (*entity.Response, error)
 	DeleteProfile(ctx context.Context,  id string)error
 	UpdateToPremium(ctx context.Context, id string)(*entity.Response, error)
 }
@@ -79,7 +80,7 @@ func (u userService) ListUser(ctx context.Context, req *entity.ListReq) (*entity
 	ctx, span := otlp.Start(ctx, serviceNameUserService, spanNameUserService + "ListUsers")
 	defer span.End()
 
-	return u.repo.List(ctx, req.Limit, req.Offset, req.Filter)
+	return u.repo.List(ctx, req)
 }
 
 func (u userService) CheckUnique(ctx context.Context, req *entity.GetReq) (*entity.Response, error) {
@@ -115,6 +116,13 @@ func (u userService) UpdateProfile(ctx context.Context, request *entity.UpdatePr
 	return u.repo.UpdateProfile(ctx, request)
 }
 
+func (u userService) UpdateUniversity(ctx context.Context, request *entity.UpdateUniversity) (*entity.Response, error) {
+	ctx, span := otlp.Start(ctx, serviceNameUserService, spanNameUserService + "UpdateUniversity")
+	defer span.End()
+
+	return u.repo.UpdateUniversity(ctx, request)
+}
+
 func (u userService)DeleteProfile(ctx context.Context, id string)error {
 	ctx, span := otlp.Start(ctx, serviceNameUserService, spanNameUserService + "DeleteProfile")
 	defer span.End()
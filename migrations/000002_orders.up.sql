@@ -0,0 +1,14 @@
+CREATE TABLE if not exists orders (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    post_id UUID NOT NULL,
+    price FLOAT NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL, -- paid
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    foreign key (user_id) references users(id),
+    foreign key (post_id) references posts(id)
+);
+
+-- a paid order is the user's entitlement to the post, so only one may exist
+CREATE UNIQUE INDEX if not exists orders_user_id_post_id_paid_idx ON orders (user_id, post_id) WHERE status = 'paid';
@@ -0,0 +1 @@
+UPDATE posts SET path = 'http://localhost:9000/univer/' || path WHERE path !~ '^https?://';
@@ -0,0 +1,3 @@
+-- posts.path used to hold a public http://<host>/<bucket>/<object> URL,
+-- keep only the object key now that the file bucket is private
+UPDATE posts SET path = regexp_replace(path, '^https?://[^/]+/[^/]+/', '') WHERE path ~ '^https?://';
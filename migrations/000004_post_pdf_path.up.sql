@@ -0,0 +1,2 @@
+-- object key of the PDF rendition of office documents, empty for PDFs and archives
+ALTER TABLE posts ADD COLUMN if not exists pdf_path TEXT NOT NULL DEFAULT '';
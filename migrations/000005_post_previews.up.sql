@@ -0,0 +1,2 @@
+-- object keys of the first page thumbnails in the preview bucket, in page order
+ALTER TABLE posts ADD COLUMN if not exists previews TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,3 @@
+DROP INDEX if exists posts_file_hash_idx;
+ALTER TABLE posts DROP COLUMN if exists file_hash;
+drop table if exists files;
@@ -0,0 +1,13 @@
+CREATE TABLE if not exists files (
+    hash CHAR(64) PRIMARY KEY, -- sha256 of the content, hex encoded
+    path TEXT NOT NULL, -- object key in the file bucket
+    size bigint NOT NULL,
+    content_type TEXT NOT NULL,
+    ref_count INT NOT NULL DEFAULT 0, -- live posts pointing at the blob
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- empty for posts uploaded before deduplication
+ALTER TABLE posts ADD COLUMN if not exists file_hash CHAR(64) NOT NULL DEFAULT '';
+CREATE INDEX if not exists posts_file_hash_idx ON posts (file_hash) WHERE deleted_at IS NULL;
@@ -0,0 +1,2 @@
+drop table if exists post_versions;
+ALTER TABLE posts DROP COLUMN if exists version;
@@ -0,0 +1,16 @@
+ALTER TABLE posts ADD COLUMN if not exists version INT NOT NULL DEFAULT 1;
+
+-- previous revisions of a post's file; the current one stays on posts
+CREATE TABLE if not exists post_versions (
+    id UUID PRIMARY KEY,
+    post_id UUID NOT NULL,
+    version INT NOT NULL,
+    path TEXT NOT NULL,
+    file_hash CHAR(64) NOT NULL DEFAULT '',
+    pdf_path TEXT NOT NULL DEFAULT '',
+    previews TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP, -- when a newer revision replaced it
+    foreign key (post_id) references posts(id)
+);
+
+CREATE UNIQUE INDEX if not exists post_versions_post_id_version_idx ON post_versions (post_id, version);
@@ -0,0 +1,7 @@
+DROP TRIGGER if exists category_search_vector_update ON category;
+DROP FUNCTION if exists category_search_vector_trigger();
+DROP TRIGGER if exists posts_search_vector_update ON posts;
+DROP FUNCTION if exists posts_search_vector_trigger();
+DROP INDEX if exists posts_search_vector_idx;
+ALTER TABLE posts DROP COLUMN if exists search_vector;
+DROP FUNCTION if exists post_search_vector(posts);
@@ -0,0 +1,36 @@
+ALTER TABLE posts ADD COLUMN if not exists search_vector tsvector NOT NULL DEFAULT ''::tsvector;
+
+-- the searchable text of a post, weighted theme > science > category
+CREATE OR REPLACE FUNCTION post_search_vector(post posts) RETURNS tsvector AS $$
+    SELECT setweight(to_tsvector('simple', coalesce(post.theme, '')), 'A') ||
+           setweight(to_tsvector('simple', coalesce(post.science, '')), 'B') ||
+           setweight(to_tsvector('simple', coalesce((SELECT name FROM category WHERE id = post.category_id), '')), 'C');
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION posts_search_vector_trigger() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector := post_search_vector(NEW);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER posts_search_vector_update
+    BEFORE INSERT OR UPDATE OF theme, science, category_id ON posts
+    FOR EACH ROW EXECUTE FUNCTION posts_search_vector_trigger();
+
+-- renaming a category changes the text of all its posts
+CREATE OR REPLACE FUNCTION category_search_vector_trigger() RETURNS trigger AS $$
+BEGIN
+    UPDATE posts SET search_vector = post_search_vector(posts) WHERE category_id = NEW.id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER category_search_vector_update
+    AFTER UPDATE OF name ON category
+    FOR EACH ROW WHEN (OLD.name IS DISTINCT FROM NEW.name)
+    EXECUTE FUNCTION category_search_vector_trigger();
+
+UPDATE posts SET search_vector = post_search_vector(posts);
+
+CREATE INDEX if not exists posts_search_vector_idx ON posts USING GIN (search_vector) WHERE deleted_at IS NULL;
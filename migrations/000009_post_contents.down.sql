@@ -0,0 +1,12 @@
+DROP TRIGGER if exists post_contents_update ON post_contents;
+DROP FUNCTION if exists post_contents_trigger();
+
+CREATE OR REPLACE FUNCTION post_search_vector(post posts) RETURNS tsvector AS $$
+    SELECT setweight(to_tsvector('simple', coalesce(post.theme, '')), 'A') ||
+           setweight(to_tsvector('simple', coalesce(post.science, '')), 'B') ||
+           setweight(to_tsvector('simple', coalesce((SELECT name FROM category WHERE id = post.category_id), '')), 'C');
+$$ LANGUAGE sql STABLE;
+
+drop table if exists post_contents;
+UPDATE posts SET search_vector = post_search_vector(posts);
+ALTER TABLE posts DROP COLUMN if exists pages;
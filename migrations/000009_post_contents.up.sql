@@ -0,0 +1,30 @@
+-- text extracted from the current file of a post
+CREATE TABLE if not exists post_contents (
+    post_id UUID PRIMARY KEY,
+    text TEXT NOT NULL,
+    pages INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    foreign key (post_id) references posts(id)
+);
+
+ALTER TABLE posts ADD COLUMN if not exists pages INT NOT NULL DEFAULT 0;
+
+CREATE OR REPLACE FUNCTION post_search_vector(post posts) RETURNS tsvector AS $$
+    SELECT setweight(to_tsvector('simple', coalesce(post.theme, '')), 'A') ||
+           setweight(to_tsvector('simple', coalesce(post.science, '')), 'B') ||
+           setweight(to_tsvector('simple', coalesce((SELECT name FROM category WHERE id = post.category_id), '')), 'C') ||
+           setweight(to_tsvector('simple', coalesce((SELECT text FROM post_contents WHERE post_id = post.id), '')), 'D');
+$$ LANGUAGE sql STABLE;
+
+-- new content changes the text and page count of its post
+CREATE OR REPLACE FUNCTION post_contents_trigger() RETURNS trigger AS $$
+BEGIN
+    UPDATE posts SET search_vector = post_search_vector(posts), pages = NEW.pages WHERE id = NEW.post_id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER post_contents_update
+    AFTER INSERT OR UPDATE ON post_contents
+    FOR EACH ROW EXECUTE FUNCTION post_contents_trigger();
@@ -0,0 +1,14 @@
+DROP INDEX if exists posts_science_normalized_idx;
+DROP INDEX if exists category_name_normalized_idx;
+DROP INDEX if exists users_username_normalized_idx;
+
+CREATE OR REPLACE FUNCTION post_search_vector(post posts) RETURNS tsvector AS $$
+    SELECT setweight(to_tsvector('simple', coalesce(post.theme, '')), 'A') ||
+           setweight(to_tsvector('simple', coalesce(post.science, '')), 'B') ||
+           setweight(to_tsvector('simple', coalesce((SELECT name FROM category WHERE id = post.category_id), '')), 'C') ||
+           setweight(to_tsvector('simple', coalesce((SELECT text FROM post_contents WHERE post_id = post.id), '')), 'D');
+$$ LANGUAGE sql STABLE;
+
+UPDATE posts SET search_vector = post_search_vector(posts);
+
+DROP FUNCTION if exists uz_normalize(text);
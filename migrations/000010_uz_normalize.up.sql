@@ -0,0 +1,29 @@
+-- canonical form of Uzbek Latin, Uzbek Cyrillic and Russian text for matching:
+-- lowercase Latin without apostrophes, "ye" folded to "e". Keep in sync with
+-- translit.Normalize. Uppercase Cyrillic is listed explicitly because lower()
+-- leaves it alone under the C locale.
+CREATE OR REPLACE FUNCTION uz_normalize(input text) RETURNS text AS $$
+    SELECT replace(
+        translate(
+            replace(replace(replace(replace(replace(replace(
+            replace(replace(replace(replace(replace(replace(lower(input),
+                'ё', 'yo'), 'Ё', 'yo'), 'ю', 'yu'), 'Ю', 'yu'), 'я', 'ya'), 'Я', 'ya'),
+                'ч', 'ch'), 'Ч', 'ch'), 'ш', 'sh'), 'Ш', 'sh'), 'щ', 'sh'), 'Щ', 'sh'),
+            'абвгдежзийклмнопрстуфхцыэўқғҳАБВГДЕЖЗИЙКЛМНОПРСТУФХЦЫЭЎҚҒҲъьЪЬ''ʻʼ`‘’',
+            'abvgdejziyklmnoprstufxsieoqghabvgdejziyklmnoprstufxsieoqgh'),
+        'ye', 'e');
+$$ LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE;
+
+CREATE OR REPLACE FUNCTION post_search_vector(post posts) RETURNS tsvector AS $$
+    SELECT setweight(to_tsvector('simple', uz_normalize(coalesce(post.theme, ''))), 'A') ||
+           setweight(to_tsvector('simple', uz_normalize(coalesce(post.science, ''))), 'B') ||
+           setweight(to_tsvector('simple', uz_normalize(coalesce((SELECT name FROM category WHERE id = post.category_id), ''))), 'C') ||
+           setweight(to_tsvector('simple', uz_normalize(coalesce((SELECT text FROM post_contents WHERE post_id = post.id), ''))), 'D');
+$$ LANGUAGE sql STABLE;
+
+UPDATE posts SET search_vector = post_search_vector(posts);
+
+-- filters compare the canonical forms
+CREATE INDEX if not exists users_username_normalized_idx ON users (uz_normalize(username) text_pattern_ops) WHERE deleted_at IS NULL;
+CREATE INDEX if not exists category_name_normalized_idx ON category (uz_normalize(name));
+CREATE INDEX if not exists posts_science_normalized_idx ON posts (uz_normalize(science)) WHERE deleted_at IS NULL;
@@ -0,0 +1,4 @@
+DROP INDEX if exists category_name_trgm_idx;
+DROP INDEX if exists posts_science_trgm_idx;
+DROP INDEX if exists posts_theme_trgm_idx;
+DROP EXTENSION if exists pg_trgm;
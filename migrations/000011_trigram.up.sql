@@ -0,0 +1,6 @@
+CREATE EXTENSION if not exists pg_trgm;
+
+-- typo tolerant matching and suggestions over the canonical text
+CREATE INDEX if not exists posts_theme_trgm_idx ON posts USING GIN (uz_normalize(theme) gin_trgm_ops) WHERE deleted_at IS NULL;
+CREATE INDEX if not exists posts_science_trgm_idx ON posts USING GIN (uz_normalize(science) gin_trgm_ops) WHERE deleted_at IS NULL;
+CREATE INDEX if not exists category_name_trgm_idx ON category USING GIN (uz_normalize(name) gin_trgm_ops) WHERE deleted_at IS NULL;
@@ -0,0 +1,4 @@
+DROP INDEX if exists category_created_at_id_idx;
+DROP INDEX if exists users_created_at_id_idx;
+DROP INDEX if exists comments_created_at_id_idx;
+DROP INDEX if exists posts_created_at_id_idx;
@@ -0,0 +1,5 @@
+-- keyset pagination walks the lists in (created_at, id) order
+CREATE INDEX if not exists posts_created_at_id_idx ON posts (created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX if not exists comments_created_at_id_idx ON comments (created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX if not exists users_created_at_id_idx ON users (role, created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX if not exists category_created_at_id_idx ON category (created_at, id) WHERE deleted_at IS NULL;
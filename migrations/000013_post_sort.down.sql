@@ -0,0 +1,8 @@
+DROP INDEX if exists posts_user_created_at_idx;
+DROP INDEX if exists posts_price_idx;
+DROP INDEX if exists posts_comments_count_idx;
+DROP INDEX if exists posts_views_idx;
+
+DROP TRIGGER if exists posts_comments_count_update ON comments;
+DROP FUNCTION if exists posts_comments_count_trigger();
+ALTER TABLE posts DROP COLUMN if exists comments_count;
@@ -0,0 +1,32 @@
+-- live comments of a post, kept by a trigger so posts can be sorted by them
+ALTER TABLE posts ADD COLUMN if not exists comments_count INT NOT NULL DEFAULT 0;
+
+UPDATE posts SET comments_count = (
+    SELECT count(*) FROM comments WHERE comments.post_id = posts.id AND comments.deleted_at IS NULL
+);
+
+CREATE OR REPLACE FUNCTION posts_comments_count_trigger() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        IF OLD.deleted_at IS NULL THEN
+            UPDATE posts SET comments_count = comments_count - 1 WHERE id = OLD.post_id;
+        END IF;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        IF NEW.deleted_at IS NULL THEN
+            UPDATE posts SET comments_count = comments_count + 1 WHERE id = NEW.post_id;
+        END IF;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER posts_comments_count_update
+    AFTER INSERT OR DELETE OR UPDATE OF post_id, deleted_at ON comments
+    FOR EACH ROW EXECUTE FUNCTION posts_comments_count_trigger();
+
+-- every sort key is followed by created_at and id for keyset pagination
+CREATE INDEX if not exists posts_views_idx ON posts (views, created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX if not exists posts_comments_count_idx ON posts (comments_count, created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX if not exists posts_price_idx ON posts (price, created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX if not exists posts_user_created_at_idx ON posts (user_id, created_at, id) WHERE deleted_at IS NULL;
@@ -0,0 +1,11 @@
+DROP INDEX if exists posts_rating_score_idx;
+
+DROP TRIGGER if exists posts_rating_update ON ratings;
+DROP FUNCTION if exists posts_rating_trigger();
+
+ALTER TABLE posts DROP COLUMN if exists rating_score;
+ALTER TABLE posts DROP COLUMN if exists rating_avg;
+ALTER TABLE posts DROP COLUMN if exists rating_count;
+ALTER TABLE posts DROP COLUMN if exists rating_sum;
+
+DROP TABLE if exists ratings;
@@ -0,0 +1,40 @@
+CREATE TABLE if not exists ratings (
+    post_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    stars SMALLINT NOT NULL CHECK (stars BETWEEN 1 AND 5),
+    review TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (post_id, user_id),
+    foreign key (post_id) references posts(id),
+    foreign key (user_id) references users(id)
+);
+
+CREATE INDEX if not exists ratings_post_id_created_at_idx ON ratings (post_id, created_at, user_id);
+
+-- rating_score is the Bayesian average, every post starts as if it had five
+-- ratings of three stars so a single vote does not top the list
+ALTER TABLE posts ADD COLUMN if not exists rating_sum INT NOT NULL DEFAULT 0;
+ALTER TABLE posts ADD COLUMN if not exists rating_count INT NOT NULL DEFAULT 0;
+ALTER TABLE posts ADD COLUMN if not exists rating_avg REAL
+    GENERATED ALWAYS AS (CASE WHEN rating_count = 0 THEN 0 ELSE rating_sum::real / rating_count END) STORED;
+ALTER TABLE posts ADD COLUMN if not exists rating_score REAL
+    GENERATED ALWAYS AS ((rating_sum + 15)::real / (rating_count + 5)) STORED;
+
+CREATE OR REPLACE FUNCTION posts_rating_trigger() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE posts SET rating_sum = rating_sum - OLD.stars, rating_count = rating_count - 1 WHERE id = OLD.post_id;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE posts SET rating_sum = rating_sum + NEW.stars, rating_count = rating_count + 1 WHERE id = NEW.post_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER posts_rating_update
+    AFTER INSERT OR DELETE OR UPDATE OF stars ON ratings
+    FOR EACH ROW EXECUTE FUNCTION posts_rating_trigger();
+
+CREATE INDEX if not exists posts_rating_score_idx ON posts (rating_score, created_at, id) WHERE deleted_at IS NULL;
@@ -0,0 +1,37 @@
+CREATE TABLE if not exists bookmarks (
+    user_id UUID NOT NULL,
+    post_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, post_id),
+    foreign key (user_id) references users(id),
+    foreign key (post_id) references posts(id)
+);
+
+CREATE INDEX if not exists bookmarks_user_id_created_at_idx ON bookmarks (user_id, created_at, post_id);
+
+CREATE TABLE if not exists collections (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    name VARCHAR(200) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    public BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    foreign key (user_id) references users(id)
+);
+
+CREATE INDEX if not exists collections_user_id_created_at_idx ON collections (user_id, created_at, id) WHERE deleted_at IS NULL;
+
+-- posts of a collection in the order the owner chose
+CREATE TABLE if not exists collection_posts (
+    collection_id UUID NOT NULL,
+    post_id UUID NOT NULL,
+    position INT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (collection_id, post_id),
+    foreign key (collection_id) references collections(id),
+    foreign key (post_id) references posts(id)
+);
+
+CREATE INDEX if not exists collection_posts_post_id_idx ON collection_posts (post_id);
@@ -0,0 +1,3 @@
+DROP TABLE if exists post_tags;
+DROP TABLE if exists tag_synonyms;
+DROP TABLE if exists tags;
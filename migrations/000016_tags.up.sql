@@ -0,0 +1,32 @@
+CREATE TABLE if not exists tags (
+    id UUID PRIMARY KEY,
+    slug VARCHAR(50) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- other spellings of a tag; a slug is either a tag or a synonym, never both
+CREATE TABLE if not exists tag_synonyms (
+    synonym VARCHAR(50) PRIMARY KEY,
+    tag_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    foreign key (tag_id) references tags(id) ON DELETE CASCADE
+);
+
+CREATE INDEX if not exists tag_synonyms_tag_id_idx ON tag_synonyms (tag_id);
+
+CREATE TABLE if not exists post_tags (
+    post_id UUID NOT NULL,
+    tag_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (post_id, tag_id),
+    foreign key (post_id) references posts(id),
+    foreign key (tag_id) references tags(id) ON DELETE CASCADE
+);
+
+CREATE INDEX if not exists post_tags_tag_id_idx ON post_tags (tag_id);
+
+-- prefix matching for autocomplete
+CREATE INDEX if not exists tags_slug_pattern_idx ON tags (slug text_pattern_ops);
+CREATE INDEX if not exists tag_synonyms_synonym_pattern_idx ON tag_synonyms (synonym text_pattern_ops);
@@ -0,0 +1,5 @@
+-- posts keep the subject names; the original spellings are in the report
+-- only, which is dropped too
+DROP TABLE if exists subject_mapping_report;
+ALTER TABLE posts DROP COLUMN if exists subject_id;
+DROP TABLE if exists subjects;
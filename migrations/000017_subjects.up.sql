@@ -0,0 +1,54 @@
+CREATE TABLE if not exists subjects (
+    id UUID PRIMARY KEY,
+    name VARCHAR(50) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+
+-- Latin and Cyrillic spellings of a subject are the same subject
+CREATE UNIQUE INDEX if not exists subjects_name_normalized_idx ON subjects (uz_normalize(name)) WHERE deleted_at IS NULL;
+CREATE INDEX if not exists subjects_created_at_idx ON subjects (created_at, id) WHERE deleted_at IS NULL;
+
+ALTER TABLE posts ADD COLUMN if not exists subject_id UUID REFERENCES subjects(id);
+
+CREATE INDEX if not exists posts_subject_id_idx ON posts (subject_id) WHERE deleted_at IS NULL;
+
+-- every free-text science becomes the subject of its canonical form, named by
+-- its most used spelling. The id is derived from the canonical form so posts
+-- can be pointed at it without a lookup table
+INSERT INTO subjects (id, name)
+SELECT md5(canonical)::uuid, name
+FROM (
+    SELECT uz_normalize(btrim(science)) AS canonical,
+           mode() WITHIN GROUP (ORDER BY btrim(science)) AS name
+    FROM posts
+    WHERE science IS NOT NULL
+    GROUP BY 1
+) sciences
+WHERE canonical <> ''
+ON CONFLICT DO NOTHING;
+
+UPDATE posts SET subject_id = md5(uz_normalize(btrim(science)))::uuid
+WHERE uz_normalize(btrim(science)) <> '';
+
+-- how each spelling was mapped, for review. Rows without a subject are posts
+-- whose science could not be mapped and needs to be set by hand
+CREATE TABLE if not exists subject_mapping_report (
+    science VARCHAR(50) PRIMARY KEY,
+    subject_id UUID,
+    subject_name VARCHAR(50),
+    posts INT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO subject_mapping_report (science, subject_id, subject_name, posts)
+SELECT coalesce(posts.science, ''), subjects.id, subjects.name, count(*)
+FROM posts
+LEFT JOIN subjects ON subjects.id = posts.subject_id
+GROUP BY coalesce(posts.science, ''), subjects.id, subjects.name;
+
+-- posts show the subject's name, which also re-indexes them for search
+UPDATE posts SET science = subjects.name
+FROM subjects
+WHERE subjects.id = posts.subject_id AND posts.science IS DISTINCT FROM subjects.name;
@@ -0,0 +1,7 @@
+ALTER TABLE posts DROP COLUMN if exists course_id;
+ALTER TABLE posts DROP COLUMN if exists faculty_id;
+ALTER TABLE posts DROP COLUMN if exists university_id;
+ALTER TABLE users DROP COLUMN if exists university_id;
+DROP TABLE if exists courses;
+DROP TABLE if exists faculties;
+DROP TABLE if exists universities;
@@ -0,0 +1,47 @@
+CREATE TABLE if not exists universities (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX if not exists universities_name_normalized_idx ON universities (uz_normalize(name)) WHERE deleted_at IS NULL;
+CREATE INDEX if not exists universities_created_at_idx ON universities (created_at, id) WHERE deleted_at IS NULL;
+
+CREATE TABLE if not exists faculties (
+    id UUID PRIMARY KEY,
+    university_id UUID NOT NULL REFERENCES universities(id),
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX if not exists faculties_name_normalized_idx ON faculties (university_id, uz_normalize(name)) WHERE deleted_at IS NULL;
+CREATE INDEX if not exists faculties_university_id_idx ON faculties (university_id, created_at, id) WHERE deleted_at IS NULL;
+
+-- a course is a programme in a given year of study, such as Economics, 2nd
+-- year; 0 is a course that is not tied to a year
+CREATE TABLE if not exists courses (
+    id UUID PRIMARY KEY,
+    faculty_id UUID NOT NULL REFERENCES faculties(id),
+    name VARCHAR(100) NOT NULL,
+    year SMALLINT NOT NULL DEFAULT 0 CHECK (year BETWEEN 0 AND 10),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX if not exists courses_name_normalized_idx ON courses (faculty_id, uz_normalize(name), year) WHERE deleted_at IS NULL;
+CREATE INDEX if not exists courses_faculty_id_idx ON courses (faculty_id, created_at, id) WHERE deleted_at IS NULL;
+
+ALTER TABLE users ADD COLUMN if not exists university_id UUID REFERENCES universities(id);
+
+ALTER TABLE posts ADD COLUMN if not exists university_id UUID REFERENCES universities(id);
+ALTER TABLE posts ADD COLUMN if not exists faculty_id UUID REFERENCES faculties(id);
+ALTER TABLE posts ADD COLUMN if not exists course_id UUID REFERENCES courses(id);
+
+CREATE INDEX if not exists posts_university_id_idx ON posts (university_id, created_at DESC, id DESC) WHERE deleted_at IS NULL;
+CREATE INDEX if not exists posts_faculty_id_idx ON posts (faculty_id) WHERE deleted_at IS NULL;
+CREATE INDEX if not exists posts_course_id_idx ON posts (course_id) WHERE deleted_at IS NULL;
@@ -0,0 +1,4 @@
+DROP TABLE if exists trending_topics;
+DROP TABLE if exists trending_posts;
+DROP INDEX if exists views_created_at_idx;
+ALTER TABLE views DROP COLUMN if exists created_at;
@@ -0,0 +1,30 @@
+-- views recorded before this migration have no time and never count towards
+-- trending scores
+ALTER TABLE views ADD COLUMN if not exists created_at TIMESTAMPTZ;
+ALTER TABLE views ALTER COLUMN created_at SET DEFAULT CURRENT_TIMESTAMP;
+
+CREATE INDEX if not exists views_created_at_idx ON views (created_at) WHERE created_at IS NOT NULL;
+
+-- the top posts of every window, overall (scope 'all') and per category and
+-- subject (scope_value is the category or subject id)
+CREATE TABLE if not exists trending_posts (
+    time_window VARCHAR(3) NOT NULL,
+    scope VARCHAR(10) NOT NULL,
+    scope_value VARCHAR(36) NOT NULL DEFAULT '',
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    score DOUBLE PRECISION NOT NULL,
+    computed_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (time_window, scope, scope_value, post_id)
+);
+
+CREATE INDEX if not exists trending_posts_score_idx ON trending_posts (time_window, scope, scope_value, score DESC);
+
+-- the categories and subjects with the most decayed views in every window
+CREATE TABLE if not exists trending_topics (
+    time_window VARCHAR(3) NOT NULL,
+    kind VARCHAR(10) NOT NULL,
+    value VARCHAR(36) NOT NULL,
+    score DOUBLE PRECISION NOT NULL,
+    computed_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (time_window, kind, value)
+);
@@ -0,0 +1,2 @@
+DROP INDEX if exists views_user_id_idx;
+DROP TABLE if exists related_posts;
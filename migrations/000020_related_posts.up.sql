@@ -0,0 +1,13 @@
+-- posts viewed by the same students, scored by the cosine similarity of
+-- their viewers and recomputed periodically from views
+CREATE TABLE if not exists related_posts (
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    related_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    score DOUBLE PRECISION NOT NULL,
+    co_views INT NOT NULL,
+    computed_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (post_id, related_id)
+);
+
+CREATE INDEX if not exists related_posts_score_idx ON related_posts (post_id, score DESC);
+CREATE INDEX if not exists views_user_id_idx ON views (user_id, post_id);
@@ -0,0 +1,2 @@
+DROP INDEX if exists views_user_post_idx;
+CREATE INDEX if not exists views_user_id_idx ON views (user_id, post_id);
@@ -0,0 +1,15 @@
+-- views used to be checked and stored in separate statements, so concurrent
+-- reads stored some twice and counted them twice on the post
+WITH removed AS (
+    DELETE FROM views a
+    USING views b
+    WHERE a.user_id = b.user_id AND a.post_id = b.post_id AND a.ctid > b.ctid
+    RETURNING a.post_id
+)
+UPDATE posts SET views = greatest(posts.views - removed.views, 0)
+FROM (SELECT post_id, count(*) AS views FROM removed GROUP BY post_id) removed
+WHERE posts.id = removed.post_id;
+
+-- buffered views are stored in bulk and skip the ones already there
+DROP INDEX if exists views_user_id_idx;
+CREATE UNIQUE INDEX if not exists views_user_post_idx ON views (user_id, post_id);
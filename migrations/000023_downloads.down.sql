@@ -0,0 +1,2 @@
+ALTER TABLE posts DROP COLUMN if exists downloads;
+DROP TABLE if exists downloads;
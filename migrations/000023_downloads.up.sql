@@ -0,0 +1,15 @@
+-- every time a post's file is handed out; version is 0 for the current file
+-- and format is 'pdf' for the converted PDF
+CREATE TABLE if not exists downloads (
+    user_id UUID NOT NULL,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    version INT NOT NULL DEFAULT 0,
+    format VARCHAR(10) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX if not exists downloads_post_id_idx ON downloads (post_id, created_at);
+CREATE INDEX if not exists downloads_created_at_idx ON downloads (created_at);
+CREATE INDEX if not exists downloads_user_id_idx ON downloads (user_id, post_id);
+
+ALTER TABLE posts ADD COLUMN if not exists downloads BIGINT NOT NULL DEFAULT 0;
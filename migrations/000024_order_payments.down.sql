@@ -0,0 +1,2 @@
+DROP INDEX if exists orders_user_id_post_id_pending_idx;
+ALTER TABLE orders DROP COLUMN if exists transaction_id;
@@ -0,0 +1,5 @@
+-- orders start out pending and are only paid once the payment provider
+-- confirms them, with its transaction id
+ALTER TABLE orders ADD COLUMN if not exists transaction_id VARCHAR(100) NOT NULL DEFAULT '';
+
+CREATE INDEX if not exists orders_user_id_post_id_pending_idx ON orders (user_id, post_id) WHERE status = 'pending';
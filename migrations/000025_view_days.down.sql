@@ -0,0 +1,15 @@
+DROP INDEX if exists views_user_post_day_idx;
+
+-- keep one view per viewer and post again
+WITH removed AS (
+    DELETE FROM views a
+    USING views b
+    WHERE a.user_id = b.user_id AND a.post_id = b.post_id AND a.ctid > b.ctid
+    RETURNING a.post_id
+)
+UPDATE posts SET views = greatest(posts.views - removed.views, 0)
+FROM (SELECT post_id, count(*) AS views FROM removed GROUP BY post_id) removed
+WHERE posts.id = removed.post_id;
+
+ALTER TABLE views DROP COLUMN if exists day;
+CREATE UNIQUE INDEX if not exists views_user_post_idx ON views (user_id, post_id);
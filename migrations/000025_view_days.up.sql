@@ -0,0 +1,6 @@
+-- a view is counted once per viewer, post and UTC day, the same window the
+-- view buffer dedups in; views stored before they had a time have no day
+ALTER TABLE views ADD COLUMN if not exists day DATE GENERATED ALWAYS AS ((created_at AT TIME ZONE 'UTC')::date) STORED;
+
+DROP INDEX if exists views_user_post_idx;
+CREATE UNIQUE INDEX if not exists views_user_post_day_idx ON views (user_id, post_id, day);
@@ -0,0 +1,6 @@
+-- guests' views are counted apart too; views stays the total
+ALTER TABLE posts ADD COLUMN if not exists guest_views INT NOT NULL DEFAULT 0;
+
+UPDATE posts SET guest_views = counted.views
+FROM (SELECT post_id, count(*) AS views FROM views WHERE anonymous GROUP BY post_id) counted
+WHERE posts.id = counted.post_id;
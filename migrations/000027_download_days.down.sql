@@ -0,0 +1,3 @@
+DROP INDEX if exists downloads_user_post_day_idx;
+CREATE INDEX if not exists downloads_user_id_idx ON downloads (user_id, post_id);
+ALTER TABLE downloads DROP COLUMN if exists day;
@@ -0,0 +1,16 @@
+-- a download is counted once per student, post and UTC day, so fetching the
+-- same file again does not push a post up
+ALTER TABLE downloads ADD COLUMN if not exists day DATE GENERATED ALWAYS AS ((created_at AT TIME ZONE 'UTC')::date) STORED;
+
+WITH removed AS (
+    DELETE FROM downloads a
+    USING downloads b
+    WHERE a.user_id = b.user_id AND a.post_id = b.post_id AND a.day = b.day AND a.ctid > b.ctid
+    RETURNING a.post_id
+)
+UPDATE posts SET downloads = greatest(posts.downloads - removed.downloads, 0)
+FROM (SELECT post_id, count(*) AS downloads FROM removed GROUP BY post_id) removed
+WHERE posts.id = removed.post_id;
+
+DROP INDEX if exists downloads_user_id_idx;
+CREATE UNIQUE INDEX if not exists downloads_user_post_day_idx ON downloads (user_id, post_id, day);
@@ -0,0 +1,4 @@
+ALTER TABLE views ADD COLUMN if not exists day DATE GENERATED ALWAYS AS ((created_at AT TIME ZONE 'UTC')::date) STORED;
+
+DROP INDEX if exists views_user_post_idx;
+CREATE UNIQUE INDEX if not exists views_user_post_day_idx ON views (user_id, post_id, day);
@@ -0,0 +1,16 @@
+-- a view is counted once per viewer and post for good; the view buffer only
+-- dedups per UTC day to keep from writing the same view over and over
+DROP INDEX if exists views_user_post_day_idx;
+
+WITH removed AS (
+    DELETE FROM views a
+    USING views b
+    WHERE a.user_id = b.user_id AND a.post_id = b.post_id AND a.ctid > b.ctid
+    RETURNING a.post_id, a.anonymous
+)
+UPDATE posts SET views = greatest(posts.views - removed.views, 0), guest_views = greatest(posts.guest_views - removed.guest_views, 0)
+FROM (SELECT post_id, count(*) AS views, count(*) FILTER (WHERE anonymous) AS guest_views FROM removed GROUP BY post_id) removed
+WHERE posts.id = removed.post_id;
+
+ALTER TABLE views DROP COLUMN if exists day;
+CREATE UNIQUE INDEX if not exists views_user_post_idx ON views (user_id, post_id);
@@ -0,0 +1,2 @@
+DELETE FROM downloads WHERE anonymous;
+ALTER TABLE downloads DROP COLUMN if exists anonymous;
@@ -0,0 +1,2 @@
+-- guests' downloads are stored under their visitor id instead of a user id
+ALTER TABLE downloads ADD COLUMN if not exists anonymous BOOLEAN NOT NULL DEFAULT false;